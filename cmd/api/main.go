@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -16,7 +22,9 @@ import (
 	_ "github.com/renatoroquejani/poc-gocache/docs"
 
 	"github.com/renatoroquejani/poc-gocache/internal/handlers"
+	"github.com/renatoroquejani/poc-gocache/internal/proxy"
 	"github.com/renatoroquejani/poc-gocache/internal/services"
+	"github.com/renatoroquejani/poc-gocache/internal/store"
 	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
 )
 
@@ -56,8 +64,8 @@ func main() {
 		apiURL = "https://api.gocache.com.br/v1"
 	}
 
-	// Cria o cliente da API
-	client, err := gocache.NewClient(apiURL, apiKey)
+	// Cria o cliente da API; o limitador de taxa é compartilhado por todos os serviços
+	client, err := gocache.NewClient(apiURL, apiKey, clientOptionsFromEnv()...)
 	if err != nil {
 		log.Fatalf("Erro ao criar cliente da API: %v", err)
 	}
@@ -67,18 +75,72 @@ func main() {
 	// smartRuleService removido - usando apenas smartRuleRewriteService
 	domainService := services.NewDomainService(client)
 	cacheService := services.NewCacheService(client)
+	if path := os.Getenv("CACHE_JOBS_PATH"); path != "" {
+		// Histórico dos jobs de expiração gravado em arquivo para sobreviver a reinícios
+		if err := cacheService.LoadJobs(path); err != nil {
+			log.Fatalf("Erro ao carregar jobs de expiração: %v", err)
+		}
+	}
+	if purgeURL := os.Getenv("PROXY_PURGE_URL"); purgeURL != "" {
+		// Cada expiração também limpa o cache local do cmd/proxy, autenticando com o
+		// mesmo PROXY_PURGE_TOKEN configurado nele
+		cacheService.Edge = services.NewProxyCachePurger(purgeURL, os.Getenv("PROXY_PURGE_TOKEN"))
+	}
+	// Índice URL -> cache-tags; gravado em CACHE_TAG_INDEX_PATH quando definido
+	tagService, err := services.NewTagService(cacheService, os.Getenv("CACHE_TAG_INDEX_PATH"))
+	if err != nil {
+		log.Fatalf("Erro ao carregar índice de cache-tags: %v", err)
+	}
 	redirectService := services.NewRedirectService(client)
 	smartRuleRewriteService := services.NewSmartRuleRewriteService(client)
-	proxyService := services.NewProxyService()
+	proxyService := newProxyService()
+	dnsSyncService := services.NewDNSSyncService(dnsService)
+
+	// Onboardings gravados em ONBOARDING_STATE_PATH quando definido, para serem retomados
+	// após reiniciar a API
+	onboardingService, err := services.NewOnboardingService(domainService, dnsService, smartRuleRewriteService, os.Getenv("ONBOARDING_STATE_PATH"))
+	if err != nil {
+		log.Fatalf("Erro ao carregar onboardings: %v", err)
+	}
+
+	purgeQueueOptions, err := services.PurgeQueueOptionsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	purgeQueue := services.NewPurgeQueue(cacheService, purgeQueueOptions)
+
+	// As alterações de regras, redirecionamentos, DNS e mapeamentos são publicadas no
+	// EventBus; o CachePurgeHook coloca as URLs afetadas na fila de expiração
+	events := services.NewEventBus()
+	dnsService.Events = events
+	redirectService.Events = events
+	smartRuleRewriteService.Events = events
+	proxyService.Events = events
+	purgeHook := newCachePurgeHook(purgeQueue, events)
+	if value := os.Getenv("DNS_SYNC_CONCURRENCY"); value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil || concurrency < 1 {
+			log.Fatalf("Valor inválido para DNS_SYNC_CONCURRENCY: %q", value)
+		}
+		dnsSyncService.Concurrency = concurrency
+	}
 
 	// Inicializa os handlers
 	dnsHandler := handlers.NewDNSHandler(dnsService)
+	dnsSyncHandler := handlers.NewDNSSyncHandler(dnsSyncService)
 	// smartRuleHandler removido - usando apenas smartRuleRewriteHandler
-	cacheHandler := handlers.NewCacheHandler(cacheService)
+	cacheHandler := handlers.NewCacheHandler(cacheService, purgeQueue)
+	cacheTagHandler := handlers.NewCacheTagHandler(tagService)
+	cacheHookHandler := handlers.NewCacheHookHandler(purgeHook)
 	redirectHandler := handlers.NewRedirectHandler(redirectService)
 	smartRuleRewriteHandler := handlers.NewSmartRuleRewriteHandler(smartRuleRewriteService)
 	proxyHandler := handlers.NewProxyHandler(proxyService)
-	domainHandler := handlers.NewDomainHandler(domainService, nil)
+	domainDeletionService := services.NewDomainDeletionService(domainService, dnsService, smartRuleRewriteService, redirectService, proxyService)
+	domainHandler := handlers.NewDomainHandler(domainService, domainDeletionService)
+	onboardingHandler := handlers.NewOnboardingHandler(onboardingService)
+	backupService := services.NewBackupService(domainService, dnsService, redirectService, smartRuleRewriteService)
+	backupService.Source = apiURL
+	backupHandler := handlers.NewBackupHandler(backupService)
 
 	// Inicializa o router
 	router := gin.Default()
@@ -87,60 +149,28 @@ func main() {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
-	// Middleware para processar redirecionamentos de domínio
-	router.Use(func(c *gin.Context) {
-		// Verifica se é uma requisição para a API ou para o Swagger
-		if strings.HasPrefix(c.Request.URL.Path, "/api/") ||
-			strings.HasPrefix(c.Request.URL.Path, "/swagger/") {
-			c.Next()
-			return
-		}
-
-		// Tenta encontrar um mapeamento para o host
-		host := c.Request.Host
-		path := c.Request.URL.Path
-
-		// Remove a porta do host, se presente
-		if strings.Contains(host, ":") {
-			host = strings.Split(host, ":")[0]
-		}
-
-		// Procura o mapeamento correspondente
-		mapping, err := proxyService.GetMapping(host)
-		if err != nil {
-			// Se não encontrou mapeamento, continua o processamento normal
-			c.Next()
-			return
-		}
-
-		// Encontrou mapeamento, faz o redirecionamento
-		destination := mapping.Destination
-
-		// Se o path não for a raiz, adiciona ao destino
-		if path != "/" {
-			// Remove a barra inicial do path se o destino já terminar com barra
-			if strings.HasSuffix(destination, "/") && strings.HasPrefix(path, "/") {
-				path = path[1:]
-			}
-			destination = destination + path
-		}
-
-		log.Printf("Redirecionando %s%s para: %s", host, path, destination)
-		c.Redirect(http.StatusMovedPermanently, destination)
-		c.Abort()
-	})
+	// Middleware para processar os domínios mapeados (redirecionamento ou proxy); as
+	// requisições para a API e para o Swagger seguem para as rotas normalmente
+	mappingHandler := proxy.NewHandler(proxyService, nil)
+	mappingHandler.Tags = tagService
+	router.Use(mappingHandler.Middleware("/api/", "/swagger/"))
 
 	// Configura as rotas da API
 	apiGroup := router.Group("/api/v1")
 	{
 		// Registra as rotas dos handlers
 		dnsHandler.RegisterRoutes(apiGroup)
+		dnsSyncHandler.RegisterRoutes(apiGroup)
 		// smartRuleHandler removido - usando apenas smartRuleRewriteHandler
 		cacheHandler.RegisterRoutes(apiGroup)
+		cacheTagHandler.RegisterRoutes(apiGroup)
+		cacheHookHandler.RegisterRoutes(apiGroup)
 		redirectHandler.RegisterRoutes(router)           // Registra as rotas de redirecionamento
 		smartRuleRewriteHandler.RegisterRoutes(apiGroup) // Registra as rotas de Smart Rules de redirecionamento no grupo de API
 		proxyHandler.RegisterRoutes(router)              // Registra as rotas de proxy
 		domainHandler.RegisterRoutes(apiGroup)
+		onboardingHandler.RegisterRoutes(apiGroup)
+		backupHandler.RegisterRoutes(apiGroup)
 	}
 
 	// Configura o Swagger
@@ -152,7 +182,137 @@ func main() {
 	log.Printf("Documentação Swagger disponível em http://localhost%s/swagger/index.html", serverAddr)
 	log.Printf("Serviço de redirecionamento de domínios ativado")
 
-	if err := router.Run(serverAddr); err != nil {
-		log.Fatalf("Erro ao iniciar o servidor: %v", err)
+	// Encerra com SIGINT/SIGTERM aguardando as requisições em andamento, enviando as URLs
+	// que ainda estão na fila de expiração e gravando o índice de tags
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Addr: serverAddr, Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Erro ao iniciar o servidor: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("Encerrando o servidor")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Erro ao encerrar o servidor: %v", err)
 	}
+	purgeQueue.Close()
+	if err := tagService.Close(); err != nil {
+		log.Printf("Erro ao gravar índice de tags: %v", err)
+	}
+	if err := cacheService.Close(); err != nil {
+		log.Printf("Erro ao gravar jobs de expiração: %v", err)
+	}
+}
+
+// newProxyService cria o serviço de proxy com o store de mapeamentos configurado em
+// MAPPING_STORE, recarregando periodicamente os mapeamentos gravados por outros processos
+func newProxyService() *services.ProxyService {
+	mappingStore, err := store.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Erro ao abrir store de mapeamentos: %v", err)
+	}
+	proxyService, err := services.NewProxyService(mappingStore)
+	if err != nil {
+		log.Fatalf("Erro ao inicializar serviço de proxy: %v", err)
+	}
+
+	interval, err := store.RefreshIntervalFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if interval > 0 {
+		proxyService.StartRefresh(context.Background(), interval)
+	}
+
+	healthOptions, err := services.HealthCheckOptionsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if healthOptions.Interval > 0 {
+		services.NewHealthChecker(proxyService, healthOptions).Start(context.Background())
+	}
+	return proxyService
+}
+
+// newCachePurgeHook cria o assinante que expira o cache após alterações de configuração.
+// CACHE_PURGE_HOOK_WINDOW define por quanto tempo as expirações concluídas são lembradas,
+// CACHE_PURGE_HOOKS_PATH o arquivo das configurações por domínio e
+// CACHE_PURGE_HOOKS_ENABLED=false desliga a assinatura.
+func newCachePurgeHook(purgeQueue *services.PurgeQueue, events *services.EventBus) *services.CachePurgeHook {
+	window := services.DefaultPurgeHookWindow
+	if value := os.Getenv("CACHE_PURGE_HOOK_WINDOW"); value != "" {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			log.Fatalf("Valor inválido para CACHE_PURGE_HOOK_WINDOW: %q", value)
+		}
+		window = duration
+	}
+
+	hook, err := services.NewCachePurgeHook(purgeQueue, window, os.Getenv("CACHE_PURGE_HOOKS_PATH"))
+	if err != nil {
+		log.Fatalf("Erro ao carregar configuração de expiração automática: %v", err)
+	}
+
+	enabled := true
+	if value := os.Getenv("CACHE_PURGE_HOOKS_ENABLED"); value != "" {
+		if enabled, err = strconv.ParseBool(value); err != nil {
+			log.Fatalf("Valor inválido para CACHE_PURGE_HOOKS_ENABLED: %q", value)
+		}
+	}
+	if enabled {
+		events.Subscribe(hook.Handle)
+	}
+	return hook
+}
+
+// clientOptionsFromEnv monta as opções do cliente Gocache a partir das variáveis de ambiente
+// GOCACHE_RATE_LIMIT (requisições por segundo), GOCACHE_RATE_BURST, GOCACHE_MAX_RETRIES,
+// GOCACHE_TIMEOUT e GOCACHE_DEBUG
+func clientOptionsFromEnv() []gocache.Option {
+	var opts []gocache.Option
+
+	if value := os.Getenv("GOCACHE_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Valor inválido para GOCACHE_TIMEOUT: %v", err)
+		}
+		opts = append(opts, gocache.WithTimeout(timeout))
+	}
+
+	if debug, _ := strconv.ParseBool(os.Getenv("GOCACHE_DEBUG")); debug {
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		opts = append(opts, gocache.WithDebug(true), gocache.WithLogger(logger))
+	}
+
+	if value := os.Getenv("GOCACHE_RATE_LIMIT"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Fatalf("Valor inválido para GOCACHE_RATE_LIMIT: %v", err)
+		}
+		burst := 1
+		if value := os.Getenv("GOCACHE_RATE_BURST"); value != "" {
+			if burst, err = strconv.Atoi(value); err != nil {
+				log.Fatalf("Valor inválido para GOCACHE_RATE_BURST: %v", err)
+			}
+		}
+		opts = append(opts, gocache.WithRateLimit(rate, burst))
+	}
+
+	if value := os.Getenv("GOCACHE_MAX_RETRIES"); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil {
+			log.Fatalf("Valor inválido para GOCACHE_MAX_RETRIES: %v", err)
+		}
+		policy := gocache.DefaultRetryPolicy()
+		policy.MaxRetries = retries
+		opts = append(opts, gocache.WithRetryPolicy(policy))
+	}
+
+	return opts
 }
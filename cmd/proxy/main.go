@@ -1,21 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-)
 
-// DomainMapping armazena o mapeamento entre domínios e seus destinos
-type DomainMapping struct {
-	Domain      string
-	Destination string
-}
+	"github.com/renatoroquejani/poc-gocache/internal/handlers"
+	"github.com/renatoroquejani/poc-gocache/internal/httpcache"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/proxy"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+	"github.com/renatoroquejani/poc-gocache/internal/store"
+)
 
 func main() {
 	// Carrega variáveis de ambiente
@@ -29,13 +32,27 @@ func main() {
 		port = "8082"
 	}
 
-	// Configuração inicial de mapeamentos
-	mappings := []DomainMapping{
-		{
-			Domain:      "elizio.sites.kodestech.com.br",
-			Destination: "https://onm-funnel-builder-stg.s3.us-east-2.amazonaws.com/account_pages/bolo-brigadeiro/index.html",
-		},
-		// Adicione mais mapeamentos conforme necessário
+	// Os mapeamentos ficam no store configurado em MAPPING_STORE, compartilhado com a API
+	proxyService := newProxyService()
+
+	// Cache local das respostas dos mapeamentos em modo proxy
+	responseCache := newResponseCache()
+	var transport http.RoundTripper
+	if responseCache != nil {
+		transport = httpcache.NewTransport(responseCache)
+	}
+	mappingHandler := proxy.NewHandler(proxyService, transport)
+	if apiURL := os.Getenv("CACHE_TAG_API_URL"); apiURL != "" {
+		// O header Cache-Tag das respostas alimenta o índice de tags da API
+		mappingHandler.Tags = services.NewRemoteTagRegistrar(apiURL)
+	}
+	if value := os.Getenv("PROXY_TRUST_FORWARDED_PROTO"); value != "" {
+		// Só deve ser ativado quando o proxy não é acessível diretamente pelos visitantes
+		trust, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Valor inválido para PROXY_TRUST_FORWARDED_PROTO: %q", value)
+		}
+		mappingHandler.TrustForwardedProto = trust
 	}
 
 	// Inicializa o router
@@ -45,66 +62,67 @@ func main() {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
-	// Rota para adicionar novos mapeamentos via API
-	router.POST("/api/mappings", func(c *gin.Context) {
-		var newMapping DomainMapping
-		if err := c.ShouldBindJSON(&newMapping); err != nil {
+	// Rotas de gerenciamento de mapeamentos, as mesmas da API em /api/v1/proxy. O proxy é
+	// público, então elas exigem PROXY_ADMIN_TOKEN e ficam bloqueadas sem ele.
+	adminToken := os.Getenv("PROXY_ADMIN_TOKEN")
+	if adminToken == "" {
+		log.Println("PROXY_ADMIN_TOKEN não definido: rotas de gerenciamento de mapeamentos desativadas")
+	}
+	handlers.NewProxyHandler(proxyService).RegisterMappingRoutes(router.Group("/api", handlers.RequireBearerToken(adminToken)))
+
+	// Rotas para expirar o cache local, com a mesma semântica das rotas de cache da API.
+	// A API as chama quando PROXY_PURGE_URL está definido, enviando PROXY_PURGE_TOKEN;
+	// sem o token configurado aqui as rotas ficam bloqueadas.
+	purgeToken := os.Getenv("PROXY_PURGE_TOKEN")
+	if purgeToken == "" {
+		log.Println("PROXY_PURGE_TOKEN não definido: rotas de expiração do cache local desativadas")
+	}
+	purgeRoutes := router.Group("/api/cache", handlers.RequireBearerToken(purgeToken))
+	purgeRoutes.DELETE("/purge-all/:domainName", func(c *gin.Context) {
+		removed := 0
+		if responseCache != nil {
+			removed = responseCache.PurgeDomain(c.Param("domainName"))
+		}
+		c.JSON(http.StatusOK, models.CacheInvalidationResponse{
+			Status:  true,
+			Message: fmt.Sprintf("%d itens removidos do cache local", removed),
+		})
+	})
+
+	purgeRoutes.DELETE("/purge-urls", func(c *gin.Context) {
+		var request models.CachePurgeRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-
-		// Valida os dados
-		if newMapping.Domain == "" || newMapping.Destination == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "domínio e destino são obrigatórios"})
+		if len(request.URLs) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A lista de URLs não pode estar vazia"})
 			return
 		}
 
-		// Adiciona o novo mapeamento
-		mappings = append(mappings, newMapping)
-		c.JSON(http.StatusCreated, newMapping)
-	})
-
-	// Rota para listar mapeamentos
-	router.GET("/api/mappings", func(c *gin.Context) {
-		c.JSON(http.StatusOK, mappings)
-	})
-
-	// Rota para processar redirecionamentos
-	router.GET("/*path", func(c *gin.Context) {
-		host := c.Request.Host
-		path := c.Param("path")
-
-		log.Printf("Recebida requisição para host: %s, path: %s", host, path)
-
-		// Remove a porta do host, se presente
-		if strings.Contains(host, ":") {
-			host = strings.Split(host, ":")[0]
+		removed := 0
+		if responseCache != nil {
+			removed = responseCache.PurgeURLs(request.URLs)
 		}
+		c.JSON(http.StatusOK, models.CacheInvalidationResponse{
+			Status:  true,
+			Message: fmt.Sprintf("%d itens removidos do cache local", removed),
+		})
+	})
 
-		// Procura o mapeamento correspondente
-		for _, mapping := range mappings {
-			if mapping.Domain == host {
-				destination := mapping.Destination
-				
-				// Se o path não for a raiz, adiciona ao destino
-				if path != "/" {
-					// Remove a barra inicial do path se o destino já terminar com barra
-					if strings.HasSuffix(destination, "/") && strings.HasPrefix(path, "/") {
-						path = path[1:]
-					}
-					destination = destination + path
-				}
-
-				log.Printf("Redirecionando para: %s", destination)
-				c.Redirect(http.StatusMovedPermanently, destination)
-				return
-			}
+	// Rota para consultar o uso do cache local
+	router.GET("/api/cache/stats", func(c *gin.Context) {
+		if responseCache == nil {
+			c.JSON(http.StatusOK, gin.H{"enabled": false})
+			return
 		}
-
-		// Se não encontrou mapeamento, retorna erro
-		c.JSON(http.StatusNotFound, gin.H{"error": "domínio não configurado"})
+		c.JSON(http.StatusOK, gin.H{"enabled": true, "stats": responseCache.Stats()})
 	})
 
+	// Rota para processar os domínios mapeados. NoRoute atende qualquer método e path que
+	// não seja da API; um catch-all "/*path" conflitaria com as rotas /api no gin.
+	router.NoRoute(gin.WrapH(mappingHandler))
+
 	// Inicia o servidor
 	serverAddr := fmt.Sprintf(":%s", port)
 	log.Printf("Servidor de proxy iniciado em http://localhost%s", serverAddr)
@@ -113,3 +131,69 @@ func main() {
 		log.Fatalf("Erro ao iniciar o servidor: %v", err)
 	}
 }
+
+// newProxyService cria o serviço de proxy com o store de mapeamentos configurado em
+// MAPPING_STORE, recarregando periodicamente os mapeamentos gravados pela API
+func newProxyService() *services.ProxyService {
+	mappingStore, err := store.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Erro ao abrir store de mapeamentos: %v", err)
+	}
+	proxyService, err := services.NewProxyService(mappingStore)
+	if err != nil {
+		log.Fatalf("Erro ao inicializar serviço de proxy: %v", err)
+	}
+
+	interval, err := store.RefreshIntervalFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if interval > 0 {
+		proxyService.StartRefresh(context.Background(), interval)
+	}
+
+	healthOptions, err := services.HealthCheckOptionsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if healthOptions.Interval > 0 {
+		services.NewHealthChecker(proxyService, healthOptions).Start(context.Background())
+	}
+	return proxyService
+}
+
+// newResponseCache cria o cache local conforme PROXY_CACHE_ENABLED (padrão: true),
+// PROXY_CACHE_MAX_MB, PROXY_CACHE_DIR e PROXY_CACHE_DEFAULT_TTL. Retorna nil se desativado.
+func newResponseCache() *httpcache.Cache {
+	if value := os.Getenv("PROXY_CACHE_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Valor inválido para PROXY_CACHE_ENABLED: %q", value)
+		}
+		if !enabled {
+			return nil
+		}
+	}
+
+	opts := httpcache.Options{Dir: os.Getenv("PROXY_CACHE_DIR")}
+	if value := os.Getenv("PROXY_CACHE_MAX_MB"); value != "" {
+		maxMB, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || maxMB < 1 {
+			log.Fatalf("Valor inválido para PROXY_CACHE_MAX_MB: %q", value)
+		}
+		opts.MaxBytes = maxMB << 20
+	}
+	if value := os.Getenv("PROXY_CACHE_DEFAULT_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Valor inválido para PROXY_CACHE_DEFAULT_TTL: %v", err)
+		}
+		opts.DefaultTTL = ttl
+	}
+
+	responseCache, err := httpcache.New(opts)
+	if err != nil {
+		log.Fatalf("Erro ao inicializar cache local: %v", err)
+	}
+	return responseCache
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -25,10 +26,9 @@ func main() {
 		Name:        "elizio.sites.exod.com.br",
 		Origin:      "onm-landing-pages.s3.us-east-1.amazonaws.com",
 		Description: "Test domain for cliente-2",
-		Enabled:     true,
 	}
 
-	resp, err := domainService.CreateDomain(req)
+	resp, err := domainService.CreateDomain(context.Background(), req)
 	if err != nil {
 		log.Fatalf("Failed to create domain: %v", err)
 	}
@@ -23,33 +23,39 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/cache/purge-all/{domainName}": {
-            "delete": {
-                "description": "Remove todo o cache de um domínio específico",
-                "consumes": [
-                    "application/json"
-                ],
+        "/backup": {
+            "get": {
+                "description": "Retorna, em um arquivo JSON versionado, as configurações, os registros DNS, os redirecionamentos e as Smart Rules dos domínios da conta",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cache"
+                    "Backup"
                 ],
-                "summary": "Expira todo o cache de um domínio",
+                "summary": "Gera o backup da conta",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Nome do domínio",
-                        "name": "domainName",
-                        "in": "path",
-                        "required": true
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Domínios a incluir (padrão: todos)",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Retorna o backup como arquivo para download",
+                        "name": "download",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CacheInvalidationResponse"
+                            "$ref": "#/definitions/models.BackupArchive"
                         }
                     },
                     "400": {
@@ -59,8 +65,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -69,9 +75,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/cache/purge-urls": {
-            "delete": {
-                "description": "Remove o cache de URLs específicas para um domínio, podendo incluir wildcards",
+        "/backup/restore": {
+            "post": {
+                "description": "Recria na conta os domínios, registros DNS, redirecionamentos e Smart Rules do backup. Itens que só existem na conta são mantidos; os que existem com outro conteúdo seguem a política de conflito (skip, overwrite ou fail). Com dry_run, apenas retorna o que seria feito. A restauração é sempre feita na conta configurada nesta instância da API; para restaurar em outra conta, use uma instância configurada com as credenciais dela.",
                 "consumes": [
                     "application/json"
                 ],
@@ -79,17 +85,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Cache"
+                    "Backup"
                 ],
-                "summary": "Expira o cache de URLs específicas",
+                "summary": "Restaura um backup",
                 "parameters": [
                     {
-                        "description": "Dados para expiração de cache",
+                        "description": "Backup e opções da restauração",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CachePurgeRequest"
+                            "$ref": "#/definitions/models.BackupRestoreRequest"
                         }
                     }
                 ],
@@ -97,7 +103,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CacheInvalidationResponse"
+                            "$ref": "#/definitions/models.BackupRestoreResponse"
                         }
                     },
                     "400": {
@@ -107,35 +113,64 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.BackupRestoreResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.BackupRestoreResponse"
+                        }
                     }
                 }
             }
         },
-        "/dns": {
+        "/cache/hooks": {
             "get": {
-                "description": "Retorna uma lista de todos os registros DNS cadastrados para um domínio específico",
-                "consumes": [
+                "description": "Retorna a configuração de cada domínio (\"*\" vale para os demais) e as últimas expirações disparadas por alterações de regras, redirecionamentos, DNS e mapeamentos",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Cache"
+                ],
+                "summary": "Lista a configuração da expiração automática",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CachePurgeHooksResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cache/hooks/{domain}": {
+            "get": {
+                "description": "Retorna a configuração aplicada ao domínio: a própria ou, se não houver, a de \"*\"",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "DNS"
+                    "Cache"
                 ],
-                "summary": "Lista todos os registros DNS",
+                "summary": "Consulta a expiração automática de um domínio",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Domínio para listar os registros DNS",
+                        "description": "Nome do domínio",
                         "name": "domain",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -143,29 +178,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DNSListResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.CachePurgeHookConfigResponse"
                         }
                     }
                 }
-            }
-        },
-        "/dns/{domain}": {
-            "post": {
-                "description": "Cria um novo registro DNS na Gocache",
+            },
+            "put": {
+                "description": "Ativa ou desativa a expiração automática do domínio, limitando os eventos e definindo os modelos de URL ({host}, {domain} e {path})",
                 "consumes": [
                     "application/json"
                 ],
@@ -173,32 +192,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DNS"
+                    "Cache"
                 ],
-                "summary": "Cria um novo registro DNS",
+                "summary": "Configura a expiração automática de um domínio",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Domínio para o qual criar o registro DNS",
+                        "description": "Nome do domínio (ou * para o padrão)",
                         "name": "domain",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados do registro DNS",
+                        "description": "Configuração",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.DNSCreateRequest"
+                            "$ref": "#/definitions/models.CachePurgeHookConfigRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DNSCreateResponse"
+                            "$ref": "#/definitions/models.CachePurgeHookConfigResponse"
                         }
                     },
                     "400": {
@@ -207,9 +226,37 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            },
+            "delete": {
+                "description": "O domínio volta a seguir a configuração de \"*\"; removida a de \"*\", ela volta ao padrão (ativada para todos os eventos)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cache"
+                ],
+                "summary": "Remove a configuração de expiração automática de um domínio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -218,33 +265,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/dns/{id}": {
+        "/cache/jobs": {
             "get": {
-                "description": "Retorna os detalhes de um registro DNS específico",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retorna o histórico de expirações, do mais recente para o mais antigo",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "DNS"
+                    "Cache"
                 ],
-                "summary": "Obtém um registro DNS específico",
+                "summary": "Lista os jobs de expiração de cache",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtra pelo domínio",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra pela situação (pending, running, completed, failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID do registro DNS",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Número máximo de jobs (padrão: 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DNSCreateResponse"
+                            "$ref": "#/definitions/models.CacheJobsListResponse"
                         }
                     },
                     "400": {
@@ -253,25 +308,49 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    }
+                }
+            }
+        },
+        "/cache/jobs/{id}": {
+            "get": {
+                "description": "Retorna o progresso e o histórico de uma expiração feita por purge-all ou purge-urls. A expiração é síncrona, então o job já está concluído (ou falho) quando a resposta da expiração volta; a rota serve de histórico, não para acompanhar o andamento.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cache"
+                ],
+                "summary": "Consulta um job de expiração de cache",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do job (campo job_id da resposta da expiração)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.CacheStatusResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Atualiza os dados de um registro DNS existente na Gocache",
+            }
+        },
+        "/cache/purge-all/{domainName}": {
+            "delete": {
+                "description": "Remove todo o cache de um domínio específico",
                 "consumes": [
                     "application/json"
                 ],
@@ -279,32 +358,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DNS"
+                    "Cache"
                 ],
-                "summary": "Atualiza um registro DNS existente",
+                "summary": "Expira todo o cache de um domínio",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID do registro DNS",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domainName",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Dados do domínio",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.DNSUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DNSUpdateResponse"
+                            "$ref": "#/definitions/models.CacheInvalidationResponse"
                         }
                     },
                     "400": {
@@ -314,13 +384,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -329,9 +392,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/cache/purge-prefix": {
             "delete": {
-                "description": "Remove um registro DNS existente na Gocache",
+                "description": "Expira tudo o que começa com os prefixos informados, nos esquemas http e https.\nCada prefixo pode ser um path (/blog/) ou uma URL do domínio ou de um subdomínio (https://www.example.com/blog/).",
                 "consumes": [
                     "application/json"
                 ],
@@ -339,23 +404,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "DNS"
+                    "Cache"
                 ],
-                "summary": "Remove um registro DNS",
+                "summary": "Expira o cache por prefixo de path",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID do registro DNS",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Domínio e prefixos (prefix e/ou prefixes)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CachePurgeByPrefixRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DNSDeleteResponse"
+                            "$ref": "#/definitions/models.CacheInvalidationResponse"
                         }
                     },
                     "400": {
@@ -365,13 +432,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -382,34 +442,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/domains": {
+        "/cache/purge-queue": {
             "get": {
-                "description": "Lista todos os domínios disponíveis na GoCache",
+                "description": "Retorna as URLs aguardando envio, por domínio, e o resultado dos últimos lotes enviados",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Domains"
+                    "Cache"
                 ],
-                "summary": "Listar domínios",
+                "summary": "Consulta a fila de expiração",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DomainListResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.PurgeQueueStatusResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates a domain and an associated smart rule",
+                "description": "Adiciona URLs à fila de expiração do domínio. URLs repetidas ou cobertas por um wildcard já enfileirado são descartadas.\nA fila é enviada à Gocache em lotes após alguns segundos sem novas URLs ou ao atingir o tamanho máximo do lote.",
                 "consumes": [
                     "application/json"
                 ],
@@ -417,27 +470,95 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Domains"
+                    "Cache"
                 ],
-                "summary": "Create domain with smart rule",
+                "summary": "Enfileira URLs para expiração em lote",
                 "parameters": [
                     {
-                        "description": "Domain info",
+                        "description": "Domínio e URLs a expirar",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.DomainCreateRequest"
+                            "$ref": "#/definitions/models.CachePurgeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurgeQueueResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            }
+        },
+        "/cache/purge-queue/flush": {
+            "post": {
+                "description": "Envia à Gocache as URLs pendentes do domínio informado (ou de todos) e retorna o resultado de cada lote",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cache"
+                ],
+                "summary": "Envia a fila de expiração imediatamente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domínio cuja fila será enviada; todos se omitido",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurgeQueueFlushResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/cache/purge-tags": {
+            "delete": {
+                "description": "Expira, nos esquemas http e https, as URLs do domínio associadas às tags informadas",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cache"
+                ],
+                "summary": "Expira o cache por cache-tags",
+                "parameters": [
+                    {
+                        "description": "Domínio e tags",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CachePurgeTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CachePurgeTagsResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -456,28 +577,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/domains/{domainID}": {
+        "/cache/purge-urls": {
             "delete": {
-                "description": "Deletes a domain",
+                "description": "Remove o cache de URLs específicas para um domínio, podendo incluir wildcards",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Domains"
+                    "Cache"
                 ],
-                "summary": "Delete domain",
+                "summary": "Expira o cache de URLs específicas",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Domain ID",
-                        "name": "domainID",
-                        "in": "path",
-                        "required": true
+                        "description": "Dados para expiração de cache",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CachePurgeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.CacheInvalidationResponse"
                         }
                     },
                     "400": {
@@ -497,25 +625,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/rules/settings/{domain}": {
+        "/cache/tags": {
             "get": {
-                "description": "Lista todas as regras de redirecionamento para um domínio específico",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retorna as URLs associadas a qualquer uma das tags informadas",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Smart Rules"
+                    "Cache"
                 ],
-                "summary": "Listar regras de redirecionamento",
+                "summary": "Consulta as URLs de cache-tags",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Nome do domínio",
-                        "name": "domain",
-                        "in": "path",
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Tags (repita o parâmetro ou separe por vírgula)",
+                        "name": "tag",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -523,27 +652,61 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SmartRuleRewriteListResponse"
+                            "$ref": "#/definitions/models.CacheTagLookupResponse"
                         }
                     },
                     "400": {
-                        "description": "Erro na requisição",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            },
+            "post": {
+                "description": "Associa cada URL às suas tags (ex: product-123). As tags enviadas substituem as anteriores; uma lista vazia remove a URL do índice. As URLs são indexadas sem query string.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cache"
+                ],
+                "summary": "Registra as cache-tags de URLs",
+                "parameters": [
+                    {
+                        "description": "URLs e tags",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CacheTagRegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CacheTagRegisterResponse"
+                        }
                     },
-                    "500": {
-                        "description": "Erro interno do servidor",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Cria uma nova regra de redirecionamento para um domínio específico",
+            }
+        },
+        "/dns": {
+            "get": {
+                "description": "Retorna uma lista de todos os registros DNS cadastrados para um domínio específico",
                 "consumes": [
                     "application/json"
                 ],
@@ -551,43 +714,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Smart Rules"
+                    "DNS"
                 ],
-                "summary": "Criar uma nova regra de redirecionamento",
+                "summary": "Lista todos os registros DNS",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Nome do domínio",
+                        "description": "Domínio para listar os registros DNS",
                         "name": "domain",
-                        "in": "path",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Dados da regra de redirecionamento",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SmartRuleRewriteCreateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SmartRuleRewriteCreateResponse"
+                            "$ref": "#/definitions/models.DNSListResponse"
                         }
                     },
                     "400": {
-                        "description": "Erro na requisição",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -596,9 +750,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/rules/settings/{domain}/{id}": {
-            "put": {
-                "description": "Atualiza uma regra de redirecionamento específica de um domínio",
+        "/dns/{domain}": {
+            "post": {
+                "description": "Cria um novo registro DNS na Gocache",
                 "consumes": [
                     "application/json"
                 ],
@@ -606,59 +760,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Smart Rules"
+                    "DNS"
                 ],
-                "summary": "Atualizar uma regra de redirecionamento",
+                "summary": "Cria um novo registro DNS",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Nome do domínio",
+                        "description": "Domínio para o qual criar o registro DNS",
                         "name": "domain",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "ID da regra de redirecionamento",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Dados da regra de redirecionamento",
+                        "description": "Dados do registro DNS",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SmartRuleRewriteCreateRequest"
+                            "$ref": "#/definitions/models.DNSCreateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.SmartRuleRewriteUpdateResponse"
+                            "$ref": "#/definitions/models.DNSCreateResponse"
                         }
                     },
                     "400": {
-                        "description": "Erro na requisição",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove uma regra de redirecionamento específica de um domínio",
+            }
+        },
+        "/dns/{domain}/sync/apply": {
+            "post": {
+                "description": "Executa as alterações de um plano criado em /dns/{domain}/sync/plan e retorna o resultado de cada registro.\nSe os registros mudaram desde a criação do plano, a execução é recusada com 409.",
                 "consumes": [
                     "application/json"
                 ],
@@ -666,41 +815,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Smart Rules"
+                    "DNS"
                 ],
-                "summary": "Remover uma regra de redirecionamento",
+                "summary": "Executa um plano de sincronização DNS",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Nome do domínio",
+                        "description": "Domínio a sincronizar",
                         "name": "domain",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "ID da regra de redirecionamento",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Plano a executar e concorrência",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DNSSyncApplyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SmartRuleRewriteDeleteResponse"
+                            "$ref": "#/definitions/models.DNSSyncApplyResponse"
                         }
                     },
                     "400": {
-                        "description": "Erro na requisição",
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -709,34 +874,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/rules/{domain}/simplified": {
+        "/dns/{domain}/sync/plan": {
             "post": {
-                "description": "Cria uma regra padrão de redirecionamento com domínio especificado na URL e parâmetros simplificados no body",
+                "description": "Recebe o conjunto completo de registros desejados (JSON ou YAML) e retorna o plano com os registros a criar, atualizar e excluir.\nRegistros existentes que não estiverem na lista serão excluídos, exceto os NS do apex.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/x-yaml"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Smart Rules"
+                    "DNS"
                 ],
-                "summary": "Criar regra padrão de redirecionamento usando domínio da URL",
+                "summary": "Planeja a sincronização dos registros DNS",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Domínio principal (ex: exod.com.br)",
+                        "description": "Domínio a sincronizar",
                         "name": "domain",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Parâmetros simplificados (sem parent_domain)",
+                        "description": "Estado desejado",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SmartRuleSimplifiedRequest"
+                            "$ref": "#/definitions/models.DNSSyncRequest"
                         }
                     }
                 ],
@@ -744,18 +910,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SmartRuleRewriteCreateResponse"
+                            "$ref": "#/definitions/models.DNSSyncPlan"
                         }
                     },
                     "400": {
-                        "description": "Erro na requisição",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Erro interno do servidor",
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -763,263 +929,2966 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.CacheInvalidationResponse": {
+        },
+        "/dns/{domain}/zone": {
+            "get": {
+                "description": "Retorna os registros DNS do domínio como um arquivo de zona no formato RFC 1035",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "DNS"
+                ],
+                "summary": "Exporta a zona DNS de um domínio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domínio a exportar",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Arquivo de zona",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Compara o arquivo de zona com os registros atuais e retorna o diff. Com dry_run=false, aplica as criações, atualizações e exclusões.\nO arquivo pode ser enviado no corpo da requisição ou como multipart no campo \"zone\".",
+                "consumes": [
+                    "text/plain",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DNS"
+                ],
+                "summary": "Importa um arquivo de zona BIND",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domínio de destino",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas simula a importação (padrão: true)",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Arquivo de zona",
+                        "name": "zone",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DNSZoneImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/dns/{id}": {
+            "get": {
+                "description": "Retorna os detalhes de um registro DNS específico",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DNS"
+                ],
+                "summary": "Obtém um registro DNS específico",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do registro DNS",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DNSCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Atualiza os dados de um registro DNS existente na Gocache",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DNS"
+                ],
+                "summary": "Atualiza um registro DNS existente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do registro DNS",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domínio (zona) do registro, usado na expiração automática do cache",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Dados do domínio",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DNSUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DNSUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove um registro DNS existente na Gocache",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "DNS"
+                ],
+                "summary": "Remove um registro DNS",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do registro DNS",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domínio (zona) do registro, usado na expiração automática do cache",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DNSDeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/domains": {
+            "get": {
+                "description": "Lista todos os domínios disponíveis na GoCache",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Listar domínios",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a domain and an associated smart rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Create domain with smart rule",
+                "parameters": [
+                    {
+                        "description": "Domain info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/{domain}": {
+            "delete": {
+                "description": "Remove as Smart Rules, os redirecionamentos, os registros DNS e os mapeamentos locais do proxy do domínio e, se todos forem removidos, o próprio domínio. Com dry_run=true, apenas lista o que seria removido.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Remove um domínio e suas configurações",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas lista o que seria removido (padrão: false)",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainDeletionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainDeletionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/domains/{domain}/settings": {
+            "get": {
+                "description": "Retorna origem, TTL de cache, WAF, modo de CDN, modo SSL e compressão do domínio na GoCache",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Consulta as configurações de um domínio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainSettingsResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Altera apenas as configurações informadas e retorna as configurações resultantes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Domains"
+                ],
+                "summary": "Altera as configurações de um domínio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Configurações a alterar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding": {
+            "get": {
+                "description": "Retorna os onboardings, do mais recente para o mais antigo",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Onboarding"
+                ],
+                "summary": "Lista os onboardings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtra pelo domínio",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número máximo de onboardings (padrão: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OnboardingListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria o domínio na Gocache, o registro DNS e a regra de redirecionamento para o bucket, nessa ordem. Se uma etapa falhar, as anteriores são desfeitas (o registro DNS e o domínio são removidos) e a resposta traz o estado de cada etapa.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Onboarding"
+                ],
+                "summary": "Cadastra um domínio com registro DNS e Smart Rule",
+                "parameters": [
+                    {
+                        "description": "Domínio, registro DNS e regra",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OnboardingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.OnboardingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.OnboardingResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding/{id}": {
+            "get": {
+                "description": "Retorna a situação do onboarding e de cada etapa",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Onboarding"
+                ],
+                "summary": "Consulta um onboarding",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do onboarding",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OnboardingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding/{id}/resume": {
+            "post": {
+                "description": "Executa novamente as etapas de um onboarding interrompido ou desfeito. Etapas cujo recurso ainda existe não são repetidas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Onboarding"
+                ],
+                "summary": "Retoma um onboarding",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do onboarding",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OnboardingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.OnboardingResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/proxy/health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Retorna a saúde dos destinos dos mapeamentos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProxyHealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/proxy/mappings": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Lista os mapeamentos de domínio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trecho do domínio",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trecho do destino",
+                        "name": "destination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Modo (redirect301, redirect302 ou proxy)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Página (padrão: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Itens por página (padrão: 50, máximo: 500)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingsListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria o mapeamento do domínio para o destino; responde 409 se o domínio já estiver mapeado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Cria um mapeamento de domínio",
+                "parameters": [
+                    {
+                        "description": "Mapeamento",
+                        "name": "mapping",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMapping"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/proxy/mappings/import": {
+            "post": {
+                "description": "Valida todos os itens antes de gravar: se algum for rejeitado, nada é gravado e a resposta lista a linha e o motivo de cada erro",
+                "consumes": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Importa mapeamentos em lote",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Substitui os domínios já mapeados",
+                        "name": "overwrite",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Mapeamentos (JSON ou CSV)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DomainMapping"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MappingImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.MappingImportResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.MappingImportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/proxy/mappings/{domain}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Retorna o mapeamento de um domínio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domínio (exato, *.exemplo.com ou *)",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Substitui um mapeamento de domínio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domínio do mapeamento",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão esperada (ETag do GET)",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Novo destino, modo, alternativos e regras",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Remove um mapeamento de domínio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domínio do mapeamento",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Versão esperada (ETag do GET)",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.DomainMappingResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/settings/{domain}": {
+            "get": {
+                "description": "Lista todas as regras de redirecionamento para um domínio específico",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Smart Rules"
+                ],
+                "summary": "Listar regras de redirecionamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartRuleRewriteListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro na requisição",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Cria uma nova regra de redirecionamento para um domínio específico",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Smart Rules"
+                ],
+                "summary": "Criar uma nova regra de redirecionamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados da regra de redirecionamento",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartRuleRewriteCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartRuleRewriteCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro na requisição",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/settings/{domain}/{id}": {
+            "put": {
+                "description": "Atualiza uma regra de redirecionamento específica de um domínio",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Smart Rules"
+                ],
+                "summary": "Atualizar uma regra de redirecionamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID da regra de redirecionamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados da regra de redirecionamento",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartRuleRewriteCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartRuleRewriteUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro na requisição",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove uma regra de redirecionamento específica de um domínio",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Smart Rules"
+                ],
+                "summary": "Remover uma regra de redirecionamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome do domínio",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID da regra de redirecionamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartRuleRewriteDeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro na requisição",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{domain}/simplified": {
+            "post": {
+                "description": "Cria uma regra padrão de redirecionamento com domínio especificado na URL e parâmetros simplificados no body",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Smart Rules"
+                ],
+                "summary": "Criar regra padrão de redirecionamento usando domínio da URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Domínio principal (ex: exod.com.br)",
+                        "name": "domain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parâmetros simplificados (sem parent_domain)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartRuleSimplifiedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartRuleRewriteCreateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro na requisição",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno do servidor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.BackupArchive": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainBackup"
+                    }
+                },
+                "source": {
+                    "description": "endereço da API de onde o backup foi feito",
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BackupRestoreItem": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "applied": {
+                    "type": "boolean"
+                },
+                "conflict": {
+                    "type": "boolean"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "domain, dns, redirect ou rule",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BackupRestoreRequest": {
+            "type": "object",
+            "properties": {
+                "archive": {
+                    "$ref": "#/definitions/models.BackupArchive"
+                },
+                "conflict": {
+                    "description": "Conflict é a política para itens que já existem com outro conteúdo (padrão: skip)",
+                    "type": "string",
+                    "enum": [
+                        "skip",
+                        "overwrite",
+                        "fail"
+                    ]
+                },
+                "domains": {
+                    "description": "Domains limita a restauração a esses domínios; vazio restaura todos",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.BackupRestoreResponse": {
+            "type": "object",
+            "properties": {
+                "conflict": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackupRestoreItem"
+                    }
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.BackupRestoreSummary"
+                }
+            }
+        },
+        "models.BackupRestoreSummary": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "integer"
+                },
+                "create": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "skip": {
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "update": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CacheInvalidationResponse": {
+            "type": "object",
+            "properties": {
+                "edge_error": {
+                    "description": "EdgeError informa a falha ao expirar o cache local do proxy, quando houver",
+                    "type": "string"
+                },
+                "job_id": {
+                    "description": "JobID identifica a expiração em GET /cache/jobs/{id}",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CacheJobEvent": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.CacheJobStatus"
+                }
+            }
+        },
+        "models.CacheJobStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "CacheJobPending",
+                "CacheJobRunning",
+                "CacheJobCompleted",
+                "CacheJobFailed"
+            ]
+        },
+        "models.CacheJobsListResponse": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CachePurgeJob"
+                    }
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CachePurgeByPrefixRequest": {
+            "type": "object",
+            "required": [
+                "domain"
+            ],
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "prefixes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CachePurgeHookConfig": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "description": "Events limita os eventos que disparam a expiração (ex: rule.updated); vazio aceita todos",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "patterns": {
+                    "description": "Patterns são os modelos das URLs expiradas, com {host}, {domain} e {path}; vazio usa\nhttp://{host}{path} e https://{host}{path}",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CachePurgeHookConfigRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "patterns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CachePurgeHookConfigResponse": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/models.CachePurgeHookConfig"
+                },
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CachePurgeHookResult": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "object_id": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CachePurgeHooksResponse": {
+            "type": "object",
+            "properties": {
+                "configs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CachePurgeHookConfig"
+                    }
+                },
+                "recent": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CachePurgeHookResult"
+                    }
+                },
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CachePurgeJob": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "edge_error": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CacheJobEvent"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "progress": {
+                    "$ref": "#/definitions/models.CacheStatus"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.CacheJobStatus"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CachePurgeRequest": {
+            "type": "object",
+            "required": [
+                "domain",
+                "urls"
+            ],
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CachePurgeTagsRequest": {
+            "type": "object",
+            "required": [
+                "domain",
+                "tags"
+            ],
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CachePurgeTagsResponse": {
+            "type": "object",
+            "properties": {
+                "edge_error": {
+                    "type": "string"
+                },
+                "job_ids": {
+                    "description": "JobIDs são os jobs de expiração criados, um por lote de URLs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "urls": {
+                    "description": "URLs são as URLs expiradas, nos esquemas http e https",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CacheStatus": {
+            "type": "object",
+            "properties": {
+                "pending": {
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CacheStatusResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.CacheStatus"
+                },
+                "job": {
+                    "$ref": "#/definitions/models.CachePurgeJob"
+                },
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CacheTagEntry": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CacheTagLookupResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CacheTagRegisterRequest": {
+            "type": "object",
+            "required": [
+                "entries"
+            ],
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CacheTagEntry"
+                    }
+                }
+            }
+        },
+        "models.CacheTagRegisterResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "boolean"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DNSChangeSummary": {
+            "type": "object",
+            "properties": {
+                "create": {
+                    "type": "integer"
+                },
+                "delete": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "update": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DNSCreateRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "name",
+                "ttl",
+                "type"
+            ],
+            "properties": {
+                "cloud": {
+                    "type": "integer",
+                    "enum": [
+                        0,
+                        1
+                    ]
+                },
+                "content": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "type": {
+                    "$ref": "#/definitions/models.DNSRecordType"
+                }
+            }
+        },
+        "models.DNSCreateResponse": {
+            "type": "object",
+            "properties": {
+                "response": {
+                    "type": "object",
+                    "properties": {
+                        "records": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DNSRecord"
+                            }
+                        }
+                    }
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DNSDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "response": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DNSListResponse": {
+            "type": "object",
+            "properties": {
+                "response": {
+                    "type": "object",
+                    "properties": {
+                        "records": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DNSRecord"
+                            }
+                        }
+                    }
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DNSRecord": {
+            "type": "object",
+            "properties": {
+                "cloud": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "record_id": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.DNSRecordType"
+                }
+            }
+        },
+        "models.DNSRecordChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "applied": {
+                    "type": "boolean"
+                },
+                "current": {
+                    "$ref": "#/definitions/models.DNSRecord"
+                },
+                "desired": {
+                    "$ref": "#/definitions/models.DNSRecord"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DNSRecordType": {
+            "type": "string",
+            "enum": [
+                "A",
+                "AAAA",
+                "CNAME",
+                "MX",
+                "TXT",
+                "NS",
+                "CAA",
+                "SRV"
+            ],
+            "x-enum-varnames": [
+                "DNSTypeA",
+                "DNSTypeAAAA",
+                "DNSTypeCNAME",
+                "DNSTypeMX",
+                "DNSTypeTXT",
+                "DNSTypeNS",
+                "DNSTypeCAA",
+                "DNSTypeSRV"
+            ]
+        },
+        "models.DNSSyncApplyRequest": {
+            "type": "object",
+            "required": [
+                "plan_id"
+            ],
+            "properties": {
+                "concurrency": {
+                    "type": "integer",
+                    "maximum": 16,
+                    "minimum": 1
+                },
+                "plan_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DNSSyncApplyResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DNSRecordChange"
+                    }
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "plan_id": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.DNSChangeSummary"
+                }
+            }
+        },
+        "models.DNSSyncPlan": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DNSRecordChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.DNSChangeSummary"
+                }
+            }
+        },
+        "models.DNSSyncRecord": {
+            "type": "object",
+            "required": [
+                "content",
+                "name",
+                "ttl",
+                "type"
+            ],
+            "properties": {
+                "cloud": {
+                    "type": "integer",
+                    "enum": [
+                        0,
+                        1
+                    ]
+                },
+                "content": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "type": {
+                    "$ref": "#/definitions/models.DNSRecordType"
+                }
+            }
+        },
+        "models.DNSSyncRequest": {
+            "type": "object",
+            "properties": {
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DNSSyncRecord"
+                    }
+                }
+            }
+        },
+        "models.DNSUpdateRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "name",
+                "ttl",
+                "type"
+            ],
+            "properties": {
+                "cloud": {
+                    "type": "integer",
+                    "enum": [
+                        0,
+                        1
+                    ]
+                },
+                "content": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "type": {
+                    "$ref": "#/definitions/models.DNSRecordType"
+                }
+            }
+        },
+        "models.DNSUpdateResponse": {
+            "type": "object",
+            "properties": {
+                "response": {
+                    "type": "object",
+                    "properties": {
+                        "records": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DNSRecord"
+                            }
+                        }
+                    }
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DNSZoneImportResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DNSRecordChange"
+                    }
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DNSZoneSkipped"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.DNSChangeSummary"
+                }
+            }
+        },
+        "models.DNSZoneSkipped": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DomainBackup": {
+            "type": "object",
+            "properties": {
+                "dns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DNSRecord"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "redirects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RedirectRule"
+                    }
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SmartRuleRewrite"
+                    }
+                },
+                "settings": {
+                    "$ref": "#/definitions/models.DomainSettings"
+                }
+            }
+        },
+        "models.DomainCreateRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "origin"
+            ],
+            "properties": {
+                "cache_ttl": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "cdn_mode": {
+                    "type": "string",
+                    "enum": [
+                        "cname",
+                        "ns"
+                    ]
+                },
+                "compression": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "padrão: true",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "origin": {
+                    "type": "string"
+                },
+                "ssl_mode": {
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "partial",
+                        "full",
+                        "full_strict"
+                    ]
+                },
+                "waf_status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DomainDeletionItem": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DomainDeletionResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainDeletionItem"
+                    }
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.DomainDeletionSummary"
+                }
+            }
+        },
+        "models.DomainDeletionSummary": {
+            "type": "object",
+            "properties": {
+                "dns": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "mappings": {
+                    "type": "integer"
+                },
+                "redirects": {
+                    "type": "integer"
+                },
+                "rules": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainListResponse": {
+            "type": "object",
+            "properties": {
+                "response": {
+                    "type": "object",
+                    "properties": {
+                        "auto_discovery": {
+                            "type": "object",
+                            "additionalProperties": true
+                        },
+                        "domains": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        },
+                        "size": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainMapping": {
+            "type": "object",
+            "required": [
+                "destination",
+                "domain"
+            ],
+            "properties": {
+                "destination": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "fallbacks": {
+                    "description": "Fallbacks são destinos alternativos, em ordem de preferência, usados quando o\nDestination principal falha na verificação de saúde",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mode": {
+                    "enum": [
+                        "redirect301",
+                        "redirect302",
+                        "proxy"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MappingMode"
+                        }
+                    ]
+                },
+                "rules": {
+                    "description": "Rules são avaliadas em ordem e a primeira que casar com o path vence; sem nenhuma,\nvale Destination",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PathRule"
+                    }
+                },
+                "version": {
+                    "description": "Version é incrementada a cada alteração e usada como ETag no controle de concorrência",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainMappingResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "mapping": {
+                    "$ref": "#/definitions/models.DomainMapping"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DomainMappingUpdateRequest": {
+            "type": "object",
+            "required": [
+                "destination"
+            ],
+            "properties": {
+                "destination": {
+                    "type": "string"
+                },
+                "fallbacks": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mode": {
+                    "enum": [
+                        "redirect301",
+                        "redirect302",
+                        "proxy"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MappingMode"
+                        }
+                    ]
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PathRule"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainMappingsListResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "mappings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainMapping"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainSettings": {
+            "type": "object",
+            "properties": {
+                "cache_ttl": {
+                    "description": "CacheTTL é o tempo de cache padrão, em segundos",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "cdn_mode": {
+                    "description": "CDNMode é o modo de apontamento: \"cname\" ou \"ns\"",
+                    "type": "string",
+                    "enum": [
+                        "cname",
+                        "ns"
+                    ]
+                },
+                "compression": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "origin": {
+                    "type": "string"
+                },
+                "ssl_mode": {
+                    "description": "SSLMode é o modo de conexão com a origem: \"off\", \"partial\", \"full\" ou \"full_strict\"",
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "partial",
+                        "full",
+                        "full_strict"
+                    ]
+                },
+                "waf_status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DomainSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "settings": {
+                    "$ref": "#/definitions/models.DomainSettings"
+                },
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.MappingHealth": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active é o destino usado no momento: o primeiro saudável na ordem principal, fallbacks",
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetHealth"
+                    }
+                }
+            }
+        },
+        "models.MappingImportError": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MappingImportResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MappingImportError"
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MappingMode": {
+            "type": "string",
+            "enum": [
+                "redirect301",
+                "redirect302",
+                "proxy"
+            ],
+            "x-enum-varnames": [
+                "MappingModeRedirect301",
+                "MappingModeRedirect302",
+                "MappingModeProxy"
+            ]
+        },
+        "models.OnboardingListResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "workflows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OnboardingWorkflow"
+                    }
+                }
+            }
+        },
+        "models.OnboardingRequest": {
+            "type": "object",
+            "required": [
+                "domain",
+                "origin"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "dns": {
+                    "$ref": "#/definitions/models.DNSCreateRequest"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "origin": {
+                    "type": "string"
+                },
+                "rule": {
+                    "$ref": "#/definitions/models.OnboardingRule"
+                }
+            }
+        },
+        "models.OnboardingResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "error": {
                     "type": "string"
                 },
                 "status": {
                     "type": "boolean"
+                },
+                "workflow": {
+                    "$ref": "#/definitions/models.OnboardingWorkflow"
                 }
             }
         },
-        "models.CachePurgeRequest": {
+        "models.OnboardingRule": {
             "type": "object",
             "required": [
-                "domain",
-                "urls"
+                "account_id",
+                "bucket_url"
             ],
             "properties": {
-                "domain": {
+                "account_id": {
                     "type": "string"
                 },
-                "urls": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "bucket_url": {
+                    "type": "string"
+                },
+                "host": {
+                    "description": "Host atendido pela regra; se vazio, usa o nome completo do registro DNS",
+                    "type": "string"
                 }
             }
         },
-        "models.DNSCreateRequest": {
-            "type": "object",
-            "required": [
-                "cloud",
-                "content",
-                "name",
-                "ttl",
-                "type"
+        "models.OnboardingStatus": {
+            "type": "string",
+            "enum": [
+                "running",
+                "completed",
+                "rolled_back",
+                "rollback_failed",
+                "interrupted"
             ],
+            "x-enum-varnames": [
+                "OnboardingRunning",
+                "OnboardingCompleted",
+                "OnboardingRolledBack",
+                "OnboardingRollbackFailed",
+                "OnboardingInterrupted"
+            ]
+        },
+        "models.OnboardingStep": {
+            "type": "object",
             "properties": {
-                "cloud": {
+                "attempts": {
                     "type": "integer"
                 },
-                "content": {
+                "compensation_error": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "ttl": {
-                    "type": "integer"
+                "resource_id": {
+                    "type": "string"
                 },
-                "type": {
+                "started_at": {
                     "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OnboardingStepStatus"
                 }
             }
         },
-        "models.DNSCreateResponse": {
+        "models.OnboardingStepStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "completed",
+                "failed",
+                "compensated",
+                "compensation_failed"
+            ],
+            "x-enum-varnames": [
+                "OnboardingStepPending",
+                "OnboardingStepRunning",
+                "OnboardingStepCompleted",
+                "OnboardingStepFailed",
+                "OnboardingStepCompensated",
+                "OnboardingStepCompensationFailed"
+            ]
+        },
+        "models.OnboardingWorkflow": {
             "type": "object",
             "properties": {
-                "response": {
-                    "type": "object",
-                    "properties": {
-                        "records": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "properties": {
-                                    "cloud": {
-                                        "type": "string"
-                                    },
-                                    "content": {
-                                        "type": "string"
-                                    },
-                                    "name": {
-                                        "type": "string"
-                                    },
-                                    "record_id": {
-                                        "description": "Pode ser string ou número"
-                                    },
-                                    "ttl": {
-                                        "type": "string"
-                                    },
-                                    "type": {
-                                        "type": "string"
-                                    }
-                                }
-                            }
-                        }
+                "created_at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "request": {
+                    "$ref": "#/definitions/models.OnboardingRequest"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OnboardingStatus"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OnboardingStep"
                     }
                 },
-                "status_code": {
-                    "type": "integer"
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.DNSDeleteResponse": {
+        "models.PathMatchType": {
+            "type": "string",
+            "enum": [
+                "prefix",
+                "exact",
+                "regex"
+            ],
+            "x-enum-varnames": [
+                "PathMatchPrefix",
+                "PathMatchExact",
+                "PathMatchRegex"
+            ]
+        },
+        "models.PathRule": {
             "type": "object",
+            "required": [
+                "destination",
+                "match",
+                "path"
+            ],
             "properties": {
-                "response": {
+                "destination": {
                     "type": "string"
                 },
-                "status_code": {
-                    "type": "integer"
+                "match": {
+                    "enum": [
+                        "prefix",
+                        "exact",
+                        "regex"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PathMatchType"
+                        }
+                    ]
+                },
+                "mode": {
+                    "description": "Mode, se vazio, herda o modo do mapeamento",
+                    "enum": [
+                        "redirect301",
+                        "redirect302",
+                        "proxy"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MappingMode"
+                        }
+                    ]
+                },
+                "path": {
+                    "type": "string"
                 }
             }
         },
-        "models.DNSListResponse": {
+        "models.PendingPurge": {
             "type": "object",
             "properties": {
-                "response": {
-                    "type": "object",
-                    "properties": {
-                        "records": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "properties": {
-                                    "cloud": {
-                                        "type": "string"
-                                    },
-                                    "content": {
-                                        "type": "string"
-                                    },
-                                    "name": {
-                                        "type": "string"
-                                    },
-                                    "record_id": {
-                                        "type": "string"
-                                    },
-                                    "ttl": {
-                                        "type": "string"
-                                    },
-                                    "type": {
-                                        "type": "string"
-                                    }
-                                }
-                            }
-                        }
+                "domain": {
+                    "type": "string"
+                },
+                "since": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ProxyHealthResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "mappings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MappingHealth"
                     }
                 },
-                "status_code": {
-                    "type": "integer"
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.DNSUpdateRequest": {
+        "models.PurgeBatchResult": {
             "type": "object",
-            "required": [
-                "cloud",
-                "content",
-                "name",
-                "ttl",
-                "type"
-            ],
             "properties": {
-                "cloud": {
-                    "type": "integer"
+                "domain": {
+                    "type": "string"
                 },
-                "content": {
+                "error": {
                     "type": "string"
                 },
-                "name": {
+                "flushed_at": {
                     "type": "string"
                 },
-                "ttl": {
-                    "type": "integer"
+                "job_id": {
+                    "type": "string"
                 },
-                "type": {
+                "message": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason indica o que disparou o envio: debounce, size ou manual",
                     "type": "string"
+                },
+                "status": {
+                    "type": "boolean"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.DNSUpdateResponse": {
+        "models.PurgeQueueFlushResponse": {
             "type": "object",
             "properties": {
-                "response": {
-                    "type": "object",
-                    "properties": {
-                        "records": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "properties": {
-                                    "cloud": {
-                                        "type": "string"
-                                    },
-                                    "content": {
-                                        "type": "string"
-                                    },
-                                    "name": {
-                                        "type": "string"
-                                    },
-                                    "record_id": {
-                                        "description": "Pode ser string ou número"
-                                    },
-                                    "ttl": {
-                                        "type": "string"
-                                    },
-                                    "type": {
-                                        "type": "string"
-                                    }
-                                }
-                            }
-                        }
+                "batches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurgeBatchResult"
                     }
                 },
-                "status_code": {
-                    "type": "integer"
+                "status": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.DomainCreateRequest": {
+        "models.PurgeQueueResponse": {
             "type": "object",
-            "required": [
-                "name",
-                "origin"
-            ],
             "properties": {
-                "description": {
-                    "type": "string"
+                "accepted": {
+                    "description": "Accepted é o número de URLs novas na fila",
+                    "type": "integer"
                 },
-                "enabled": {
-                    "type": "boolean"
+                "covered": {
+                    "description": "Covered são as URLs já cobertas por um wildcard na fila, somadas às que saíram da\nfila por serem cobertas por um wildcard novo",
+                    "type": "integer"
                 },
-                "name": {
+                "domain": {
                     "type": "string"
                 },
-                "origin": {
-                    "type": "string"
+                "duplicates": {
+                    "description": "Duplicates são as URLs que já estavam na fila",
+                    "type": "integer"
+                },
+                "pending": {
+                    "description": "Pending é o total de URLs do domínio aguardando envio",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.DomainListResponse": {
+        "models.PurgeQueueStatusResponse": {
             "type": "object",
             "properties": {
-                "response": {
-                    "type": "object",
-                    "properties": {
-                        "auto_discovery": {
-                            "type": "object",
-                            "additionalProperties": true
-                        },
-                        "domains": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        },
-                        "size": {
-                            "type": "integer"
-                        }
+                "batches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurgeBatchResult"
                     }
                 },
-                "status_code": {
+                "pending": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PendingPurge"
+                    }
+                },
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RedirectRule": {
+            "type": "object",
+            "required": [
+                "destination",
+                "domain",
+                "source",
+                "type"
+            ],
+            "properties": {
+                "destination": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "301 (permanente) ou 302 (temporário)",
                     "type": "integer"
                 }
             }
@@ -1061,6 +3930,10 @@ const docTemplate = `{
                 },
                 "rewrite_uri": {
                     "type": "string"
+                },
+                "ssl_mode": {
+                    "description": "Na criação, o padrão é \"partial\"",
+                    "type": "string"
                 }
             }
         },
@@ -1130,7 +4003,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "request": {
-                    "description": "Mantido para compatibilidade",
+                    "description": "Mantido para compatibilidade, enviado como request_uri",
                     "type": "string"
                 },
                 "request_method": {
@@ -1178,19 +4051,46 @@ const docTemplate = `{
             ],
             "properties": {
                 "account_id": {
-                    "description": "ID da conta (será usado no rewrite_uri)",
+                    "description": "ID da conta (ex: cliente-1)",
                     "type": "string"
                 },
                 "bucket_url": {
-                    "description": "URL do bucket (será usado como destino e host)",
+                    "description": "URL do bucket (ex: onm-landing-pages.s3-website-us-east-1.amazonaws.com)",
                     "type": "string"
                 },
                 "domain": {
-                    "description": "Domínio completo (será usado como CORS e host)",
+                    "description": "Subdomínio (campo unificado com nome consistente)",
                     "type": "string"
                 },
                 "parent_domain": {
-                    "description": "Domínio principal (exod.com.br)",
+                    "description": "Domínio principal já existente na GoCache (ex: sites.kodestech.com.br)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TargetHealth": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "Role é \"primary\" ou \"fallback\"",
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "url": {
                     "type": "string"
                 }
             }
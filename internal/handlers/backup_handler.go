@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+)
+
+// BackupHandler manipula o backup e a restauração das configurações da conta
+type BackupHandler struct {
+	service *services.BackupService
+}
+
+// NewBackupHandler cria uma nova instância de BackupHandler
+func NewBackupHandler(service *services.BackupService) *BackupHandler {
+	return &BackupHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes registra as rotas no router do Gin
+func (h *BackupHandler) RegisterRoutes(router *gin.RouterGroup) {
+	group := router.Group("/backup")
+	{
+		group.GET("", h.Backup)
+		group.POST("/restore", h.Restore)
+	}
+}
+
+// Backup godoc
+// @Summary Gera o backup da conta
+// @Description Retorna, em um arquivo JSON versionado, as configurações, os registros DNS, os redirecionamentos e as Smart Rules dos domínios da conta
+// @Tags Backup
+// @Produce json
+// @Param domain query []string false "Domínios a incluir (padrão: todos)" collectionFormat(multi)
+// @Param download query bool false "Retorna o backup como arquivo para download"
+// @Success 200 {object} models.BackupArchive
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /backup [get]
+func (h *BackupHandler) Backup(c *gin.Context) {
+	download := false
+	if value := c.Query("download"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro download inválido"})
+			return
+		}
+		download = parsed
+	}
+
+	// Aceita tanto ?domain=a&domain=b quanto ?domain=a,b
+	var domains []string
+	for _, value := range c.QueryArray("domain") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				domains = append(domains, name)
+			}
+		}
+	}
+
+	archive, err := h.service.Backup(c.Request.Context(), domains)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if download {
+		filename := fmt.Sprintf("gocache-backup-%s.json", archive.CreatedAt.Format("20060102-150405"))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	c.JSON(http.StatusOK, archive)
+}
+
+// Restore godoc
+// @Summary Restaura um backup
+// @Description Recria na conta os domínios, registros DNS, redirecionamentos e Smart Rules do backup. Itens que só existem na conta são mantidos; os que existem com outro conteúdo seguem a política de conflito (skip, overwrite ou fail). Com dry_run, apenas retorna o que seria feito. A restauração é sempre feita na conta configurada nesta instância da API; para restaurar em outra conta, use uma instância configurada com as credenciais dela.
+// @Tags Backup
+// @Accept json
+// @Produce json
+// @Param request body models.BackupRestoreRequest true "Backup e opções da restauração"
+// @Success 200 {object} models.BackupRestoreResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} models.BackupRestoreResponse
+// @Failure 502 {object} models.BackupRestoreResponse
+// @Router /backup/restore [post]
+func (h *BackupHandler) Restore(c *gin.Context) {
+	var request models.BackupRestoreRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	response, err := h.service.Restore(requesterContext(c), request)
+	switch {
+	case err != nil && response == nil:
+		respondError(c, err)
+	case err != nil:
+		c.JSON(statusFromError(err), response)
+	default:
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -11,12 +12,15 @@ import (
 // CacheHandler manipula as requisições relacionadas a cache
 type CacheHandler struct {
 	service *services.CacheService
+	queue   *services.PurgeQueue
 }
 
-// NewCacheHandler cria uma nova instância de CacheHandler
-func NewCacheHandler(service *services.CacheService) *CacheHandler {
+// NewCacheHandler cria uma nova instância de CacheHandler. Se queue for nil, as rotas da
+// fila de expiração não são registradas.
+func NewCacheHandler(service *services.CacheService, queue *services.PurgeQueue) *CacheHandler {
 	return &CacheHandler{
 		service: service,
+		queue:   queue,
 	}
 }
 
@@ -26,6 +30,14 @@ func (h *CacheHandler) RegisterRoutes(router *gin.RouterGroup) {
 	{
 		cacheGroup.DELETE("/purge-all/:domainName", h.PurgeAllCache)
 		cacheGroup.DELETE("/purge-urls", h.PurgeUrls)
+		cacheGroup.DELETE("/purge-prefix", h.PurgeByPrefix)
+		cacheGroup.GET("/jobs", h.ListJobs)
+		cacheGroup.GET("/jobs/:id", h.GetJob)
+		if h.queue != nil {
+			cacheGroup.POST("/purge-queue", h.EnqueuePurge)
+			cacheGroup.GET("/purge-queue", h.GetPurgeQueue)
+			cacheGroup.POST("/purge-queue/flush", h.FlushPurgeQueue)
+		}
 	}
 }
 
@@ -52,9 +64,37 @@ func (h *CacheHandler) PurgeUrls(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.PurgeUrls(request)
+	response, err := h.service.PurgeUrls(requesterContext(c), request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PurgeByPrefix godoc
+// @Summary Expira o cache por prefixo de path
+// @Description Expira tudo o que começa com os prefixos informados, nos esquemas http e https.
+// @Description Cada prefixo pode ser um path (/blog/) ou uma URL do domínio ou de um subdomínio (https://www.example.com/blog/).
+// @Tags Cache
+// @Accept json
+// @Produce json
+// @Param request body models.CachePurgeByPrefixRequest true "Domínio e prefixos (prefix e/ou prefixes)"
+// @Success 200 {object} models.CacheInvalidationResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /cache/purge-prefix [delete]
+func (h *CacheHandler) PurgeByPrefix(c *gin.Context) {
+	var request models.CachePurgeByPrefixRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	response, err := h.service.PurgeByPrefix(requesterContext(c), request)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -79,13 +119,146 @@ func (h *CacheHandler) PurgeAllCache(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.PurgeAllCache(domainName)
+	response, err := h.service.PurgeAllCache(requesterContext(c), domainName)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// GetJob godoc
+// @Summary Consulta um job de expiração de cache
+// @Description Retorna o progresso e o histórico de uma expiração feita por purge-all ou purge-urls. A expiração é síncrona, então o job já está concluído (ou falho) quando a resposta da expiração volta; a rota serve de histórico, não para acompanhar o andamento.
+// @Tags Cache
+// @Produce json
+// @Param id path string true "ID do job (campo job_id da resposta da expiração)"
+// @Success 200 {object} models.CacheStatusResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /cache/jobs/{id} [get]
+func (h *CacheHandler) GetJob(c *gin.Context) {
+	job, err := h.service.GetJob(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CacheStatusResponse{
+		Status: true,
+		Data:   job.Progress,
+		Job:    job,
+	})
+}
+
+// ListJobs godoc
+// @Summary Lista os jobs de expiração de cache
+// @Description Retorna o histórico de expirações, do mais recente para o mais antigo
+// @Tags Cache
+// @Produce json
+// @Param domain query string false "Filtra pelo domínio"
+// @Param status query string false "Filtra pela situação (pending, running, completed, failed)"
+// @Param limit query int false "Número máximo de jobs (padrão: 100)"
+// @Success 200 {object} models.CacheJobsListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /cache/jobs [get]
+func (h *CacheHandler) ListJobs(c *gin.Context) {
+	limit, err := positiveQueryInt(c, "limit", 100)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := models.CacheJobStatus(c.Query("status"))
+	switch status {
+	case "", models.CacheJobPending, models.CacheJobRunning, models.CacheJobCompleted, models.CacheJobFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status inválido: " + string(status)})
+		return
+	}
+
+	jobs := h.service.ListJobs(services.CacheJobFilter{
+		Domain: c.Query("domain"),
+		Status: status,
+		Limit:  limit,
+	})
+	c.JSON(http.StatusOK, models.CacheJobsListResponse{
+		Status: true,
+		Jobs:   jobs,
+		Total:  len(jobs),
+	})
+}
+
+// EnqueuePurge godoc
+// @Summary Enfileira URLs para expiração em lote
+// @Description Adiciona URLs à fila de expiração do domínio. URLs repetidas ou cobertas por um wildcard já enfileirado são descartadas.
+// @Description A fila é enviada à Gocache em lotes após alguns segundos sem novas URLs ou ao atingir o tamanho máximo do lote.
+// @Tags Cache
+// @Accept json
+// @Produce json
+// @Param request body models.CachePurgeRequest true "Domínio e URLs a expirar"
+// @Success 202 {object} models.PurgeQueueResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /cache/purge-queue [post]
+func (h *CacheHandler) EnqueuePurge(c *gin.Context) {
+	var request models.CachePurgeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	response, err := h.queue.Enqueue(requesterContext(c), request.Domain, request.URLs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// GetPurgeQueue godoc
+// @Summary Consulta a fila de expiração
+// @Description Retorna as URLs aguardando envio, por domínio, e o resultado dos últimos lotes enviados
+// @Tags Cache
+// @Produce json
+// @Success 200 {object} models.PurgeQueueStatusResponse
+// @Router /cache/purge-queue [get]
+func (h *CacheHandler) GetPurgeQueue(c *gin.Context) {
+	pending, batches := h.queue.Status()
+	c.JSON(http.StatusOK, models.PurgeQueueStatusResponse{
+		Status:  true,
+		Pending: pending,
+		Batches: batches,
+	})
+}
+
+// FlushPurgeQueue godoc
+// @Summary Envia a fila de expiração imediatamente
+// @Description Envia à Gocache as URLs pendentes do domínio informado (ou de todos) e retorna o resultado de cada lote
+// @Tags Cache
+// @Produce json
+// @Param domain query string false "Domínio cuja fila será enviada; todos se omitido"
+// @Success 200 {object} models.PurgeQueueFlushResponse
+// @Router /cache/purge-queue/flush [post]
+func (h *CacheHandler) FlushPurgeQueue(c *gin.Context) {
+	batches := h.queue.Flush(c.Query("domain"))
+
+	status := true
+	for _, batch := range batches {
+		status = status && batch.Status
+	}
+	c.JSON(http.StatusOK, models.PurgeQueueFlushResponse{
+		Status:  status,
+		Batches: batches,
+	})
+}
 
+// requesterContext identifica quem pediu a operação pelo header X-Requested-By ou, na
+// falta dele, pelo IP do cliente
+func requesterContext(c *gin.Context) context.Context {
+	requester := c.GetHeader("X-Requested-By")
+	if requester == "" {
+		requester = c.ClientIP()
+	}
+	return services.WithRequester(c.Request.Context(), requester)
+}
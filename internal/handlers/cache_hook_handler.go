@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+)
+
+// CacheHookHandler manipula a configuração da expiração automática de cache
+type CacheHookHandler struct {
+	hook *services.CachePurgeHook
+}
+
+// NewCacheHookHandler cria uma nova instância de CacheHookHandler
+func NewCacheHookHandler(hook *services.CachePurgeHook) *CacheHookHandler {
+	return &CacheHookHandler{
+		hook: hook,
+	}
+}
+
+// RegisterRoutes registra as rotas no router do Gin
+func (h *CacheHookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	cacheGroup := router.Group("/cache")
+	{
+		cacheGroup.GET("/hooks", h.ListHooks)
+		cacheGroup.GET("/hooks/:domain", h.GetHook)
+		cacheGroup.PUT("/hooks/:domain", h.SetHook)
+		cacheGroup.DELETE("/hooks/:domain", h.DeleteHook)
+	}
+}
+
+// ListHooks godoc
+// @Summary Lista a configuração da expiração automática
+// @Description Retorna a configuração de cada domínio ("*" vale para os demais) e as últimas expirações disparadas por alterações de regras, redirecionamentos, DNS e mapeamentos
+// @Tags Cache
+// @Produce json
+// @Success 200 {object} models.CachePurgeHooksResponse
+// @Router /cache/hooks [get]
+func (h *CacheHookHandler) ListHooks(c *gin.Context) {
+	configs, recent := h.hook.Configs()
+	c.JSON(http.StatusOK, models.CachePurgeHooksResponse{
+		Status:  true,
+		Configs: configs,
+		Recent:  recent,
+	})
+}
+
+// GetHook godoc
+// @Summary Consulta a expiração automática de um domínio
+// @Description Retorna a configuração aplicada ao domínio: a própria ou, se não houver, a de "*"
+// @Tags Cache
+// @Produce json
+// @Param domain path string true "Nome do domínio"
+// @Success 200 {object} models.CachePurgeHookConfigResponse
+// @Router /cache/hooks/{domain} [get]
+func (h *CacheHookHandler) GetHook(c *gin.Context) {
+	c.JSON(http.StatusOK, models.CachePurgeHookConfigResponse{
+		Status: true,
+		Config: h.hook.Config(c.Param("domain")),
+	})
+}
+
+// SetHook godoc
+// @Summary Configura a expiração automática de um domínio
+// @Description Ativa ou desativa a expiração automática do domínio, limitando os eventos e definindo os modelos de URL ({host}, {domain} e {path})
+// @Tags Cache
+// @Accept json
+// @Produce json
+// @Param domain path string true "Nome do domínio (ou * para o padrão)"
+// @Param request body models.CachePurgeHookConfigRequest true "Configuração"
+// @Success 200 {object} models.CachePurgeHookConfigResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /cache/hooks/{domain} [put]
+func (h *CacheHookHandler) SetHook(c *gin.Context) {
+	var request models.CachePurgeHookConfigRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	config, err := h.hook.SetConfig(c.Param("domain"), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CachePurgeHookConfigResponse{
+		Status: true,
+		Config: config,
+	})
+}
+
+// DeleteHook godoc
+// @Summary Remove a configuração de expiração automática de um domínio
+// @Description O domínio volta a seguir a configuração de "*"; removida a de "*", ela volta ao padrão (ativada para todos os eventos)
+// @Tags Cache
+// @Produce json
+// @Param domain path string true "Nome do domínio"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /cache/hooks/{domain} [delete]
+func (h *CacheHookHandler) DeleteHook(c *gin.Context) {
+	if err := h.hook.DeleteConfig(c.Param("domain")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": true, "message": "configuração removida"})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+)
+
+// CacheTagHandler manipula o índice de cache-tags e a expiração por tags
+type CacheTagHandler struct {
+	service *services.TagService
+}
+
+// NewCacheTagHandler cria uma nova instância de CacheTagHandler
+func NewCacheTagHandler(service *services.TagService) *CacheTagHandler {
+	return &CacheTagHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes registra as rotas no router do Gin
+func (h *CacheTagHandler) RegisterRoutes(router *gin.RouterGroup) {
+	cacheGroup := router.Group("/cache")
+	{
+		cacheGroup.POST("/tags", h.RegisterTags)
+		cacheGroup.GET("/tags", h.LookupTags)
+		cacheGroup.DELETE("/purge-tags", h.PurgeTags)
+	}
+}
+
+// RegisterTags godoc
+// @Summary Registra as cache-tags de URLs
+// @Description Associa cada URL às suas tags (ex: product-123). As tags enviadas substituem as anteriores; uma lista vazia remove a URL do índice. As URLs são indexadas sem query string.
+// @Tags Cache
+// @Accept json
+// @Produce json
+// @Param request body models.CacheTagRegisterRequest true "URLs e tags"
+// @Success 200 {object} models.CacheTagRegisterResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /cache/tags [post]
+func (h *CacheTagHandler) RegisterTags(c *gin.Context) {
+	var request models.CacheTagRegisterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	updated, err := h.service.Register(request.Entries)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CacheTagRegisterResponse{
+		Status:  true,
+		Updated: updated,
+	})
+}
+
+// LookupTags godoc
+// @Summary Consulta as URLs de cache-tags
+// @Description Retorna as URLs associadas a qualquer uma das tags informadas
+// @Tags Cache
+// @Produce json
+// @Param tag query []string true "Tags (repita o parâmetro ou separe por vírgula)"
+// @Success 200 {object} models.CacheTagLookupResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /cache/tags [get]
+func (h *CacheTagHandler) LookupTags(c *gin.Context) {
+	tags := services.ParseCacheTags(strings.Join(c.QueryArray("tag"), ","))
+	if len(tags) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "informe ao menos uma tag no parâmetro tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CacheTagLookupResponse{
+		Status: true,
+		Tags:   tags,
+		URLs:   h.service.URLsForTags(tags),
+	})
+}
+
+// PurgeTags godoc
+// @Summary Expira o cache por cache-tags
+// @Description Expira, nos esquemas http e https, as URLs do domínio associadas às tags informadas
+// @Tags Cache
+// @Accept json
+// @Produce json
+// @Param request body models.CachePurgeTagsRequest true "Domínio e tags"
+// @Success 200 {object} models.CachePurgeTagsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /cache/purge-tags [delete]
+func (h *CacheTagHandler) PurgeTags(c *gin.Context) {
+	var request models.CachePurgeTagsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	response, err := h.service.PurgeTags(requesterContext(c), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -30,6 +32,10 @@ func (h *DNSHandler) RegisterRoutes(router *gin.RouterGroup) {
 		dnsGroup.POST("/:domain", h.CreateDNS)
 		dnsGroup.PUT("/:id", h.UpdateDNS)
 		dnsGroup.DELETE("/:id", h.DeleteDNS)
+		// O gin exige o mesmo nome de parâmetro para rotas GET que compartilham o segmento,
+		// por isso a exportação usa :id para receber o domínio
+		dnsGroup.GET("/:id/zone", h.ExportZone)
+		dnsGroup.POST("/:domain/zone", h.ImportZone)
 	}
 }
 
@@ -51,9 +57,9 @@ func (h *DNSHandler) ListDNS(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.ListDNS(domain)
+	response, err := h.service.ListDNS(c.Request.Context(), domain)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -80,9 +86,9 @@ func (h *DNSHandler) GetDNS(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.GetDNS(id)
+	response, err := h.service.GetDNS(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -107,12 +113,12 @@ func (h *DNSHandler) CreateDNS(c *gin.Context) {
 
 	if contentType == "application/x-www-form-urlencoded" || contentType == "application/x-www-form-urlencoded; charset=UTF-8" {
 		if err := c.ShouldBind(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
 			return
 		}
 	} else {
 		if err := c.ShouldBindJSON(&request); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
 			return
 		}
 	}
@@ -126,9 +132,9 @@ func (h *DNSHandler) CreateDNS(c *gin.Context) {
 	request.Domain = domain
 
 	// Only create DNS record (assumes domain already exists in GoCache)
-	response, err := h.service.CreateDNS(request)
+	response, err := h.service.CreateDNS(requesterContext(c), request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -142,6 +148,7 @@ func (h *DNSHandler) CreateDNS(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID do registro DNS"
+// @Param domain query string false "Domínio (zona) do registro, usado na expiração automática do cache"
 // @Param request body models.DNSUpdateRequest true "Dados do domínio"
 // @Success 200 {object} models.DNSUpdateResponse
 // @Failure 400 {object} map[string]interface{}
@@ -158,13 +165,14 @@ func (h *DNSHandler) UpdateDNS(c *gin.Context) {
 
 	var request models.DNSUpdateRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
 		return
 	}
+	request.Domain = c.Query("domain")
 
-	response, err := h.service.UpdateDNS(id, request)
+	response, err := h.service.UpdateDNS(requesterContext(c), id, request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -178,6 +186,7 @@ func (h *DNSHandler) UpdateDNS(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID do registro DNS"
+// @Param domain query string false "Domínio (zona) do registro, usado na expiração automática do cache"
 // @Success 200 {object} models.DNSDeleteResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -191,9 +200,89 @@ func (h *DNSHandler) DeleteDNS(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.DeleteDNS(id)
+	response, err := h.service.DeleteDNS(requesterContext(c), c.Query("domain"), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// maxZoneFileSize limita o tamanho do arquivo de zona aceito na importação
+const maxZoneFileSize = 1 << 20
+
+// ExportZone godoc
+// @Summary Exporta a zona DNS de um domínio
+// @Description Retorna os registros DNS do domínio como um arquivo de zona no formato RFC 1035
+// @Tags DNS
+// @Produce plain
+// @Param domain path string true "Domínio a exportar"
+// @Success 200 {string} string "Arquivo de zona"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /dns/{domain}/zone [get]
+func (h *DNSHandler) ExportZone(c *gin.Context) {
+	domain := c.Param("id")
+
+	zone, err := h.service.ExportZone(c.Request.Context(), domain)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", domain+".zone"))
+	c.Data(http.StatusOK, "text/dns; charset=utf-8", []byte(zone))
+}
+
+// ImportZone godoc
+// @Summary Importa um arquivo de zona BIND
+// @Description Compara o arquivo de zona com os registros atuais e retorna o diff. Com dry_run=false, aplica as criações, atualizações e exclusões.
+// @Description O arquivo pode ser enviado no corpo da requisição ou como multipart no campo "zone".
+// @Tags DNS
+// @Accept plain
+// @Accept mpfd
+// @Produce json
+// @Param domain path string true "Domínio de destino"
+// @Param dry_run query bool false "Apenas simula a importação (padrão: true)"
+// @Param zone formData file false "Arquivo de zona"
+// @Success 200 {object} models.DNSZoneImportResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /dns/{domain}/zone [post]
+func (h *DNSHandler) ImportZone(c *gin.Context) {
+	domain := c.Param("domain")
+
+	dryRun := true
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run inválido"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxZoneFileSize)
+	var zone io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		file, err := c.FormFile("zone")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo de zona não enviado no campo \"zone\""})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		zone = f
+	}
+
+	response, err := h.service.ImportZone(c.Request.Context(), domain, zone, dryRun)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
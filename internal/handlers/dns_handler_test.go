@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+// newDNSTestRouter monta as rotas de DNS como na API, usando o servidor falso da Gocache
+func newDNSTestRouter(t *testing.T) (*gocachetest.Server, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	srv := gocachetest.NewServer()
+	t.Cleanup(srv.Close)
+	client := srv.Client(gocache.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	router := gin.New()
+	NewDNSHandler(services.NewDNSService(client)).RegisterRoutes(router.Group("/api/v1"))
+	return srv, router
+}
+
+func serve(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestDNSHandler(t *testing.T) {
+	srv, router := newDNSTestRouter(t)
+	srv.AddDNS("a.com", gocachetest.DNSRecord{ID: "10", Name: "mail", Type: "A", Content: "1.1.1.1", TTL: "300"})
+	srv.Fail(gocachetest.Failure{Method: http.MethodPost, Path: "/dns/conflito.com", Status: http.StatusUnprocessableEntity, Message: "record already exists"})
+	srv.Fail(gocachetest.Failure{Method: http.MethodGet, Path: "/dns/fora.com", Status: http.StatusInternalServerError})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"lista sem domínio", "GET", "/api/v1/dns", "", 400, "Domínio não especificado"},
+		{"lista", "GET", "/api/v1/dns?domain=a.com", "", 200, `"content":"1.1.1.1"`},
+		{"erro 5xx da Gocache vira 502", "GET", "/api/v1/dns?domain=fora.com", "", 502, "gocache: 500"},
+		{"cria", "POST", "/api/v1/dns/a.com", `{"name":"www","type":"A","content":"2.2.2.2","ttl":300,"cloud":1}`, 201, `"name":"www"`},
+		{"conteúdo inválido", "POST", "/api/v1/dns/a.com", `{"name":"www","type":"A","content":"::1","ttl":300}`, 400, "IPv4"},
+		{"erro 422 da Gocache é repassado", "POST", "/api/v1/dns/conflito.com", `{"name":"www","type":"A","content":"2.2.2.2","ttl":300}`, 422, "record already exists"},
+		{"ID inválido", "PUT", "/api/v1/dns/abc", `{}`, 400, "ID inválido"},
+		// O domínio é opcional: só é usado na expiração automática do cache
+		{"altera sem domínio", "PUT", "/api/v1/dns/10", `{"name":"mail","type":"A","content":"3.3.3.3","ttl":60}`, 200, ""},
+		{"altera", "PUT", "/api/v1/dns/10?domain=a.com", `{"name":"mail","type":"A","content":"3.3.3.3","ttl":60}`, 200, ""},
+		{"remove sem domínio", "DELETE", "/api/v1/dns/10", "", 200, ""},
+		{"remove inexistente", "DELETE", "/api/v1/dns/10?domain=a.com", "", 404, "record not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, tt.method, tt.path, tt.body)
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("%s %s = %d %s, esperado %d contendo %q", tt.method, tt.path, w.Code, w.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+			if w.Code >= 400 {
+				var body map[string]interface{}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body["error"] == nil {
+					t.Errorf("corpo de erro fora do padrão: %s", w.Body.String())
+				}
+			}
+		})
+	}
+
+	if records := srv.DNS("a.com"); len(records) != 1 || records[0].Name != "www" {
+		t.Errorf("registros = %+v", records)
+	}
+}
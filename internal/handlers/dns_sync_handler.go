@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+)
+
+// DNSSyncHandler manipula as requisições de sincronização declarativa de registros DNS
+type DNSSyncHandler struct {
+	service *services.DNSSyncService
+}
+
+// NewDNSSyncHandler cria uma nova instância de DNSSyncHandler
+func NewDNSSyncHandler(service *services.DNSSyncService) *DNSSyncHandler {
+	return &DNSSyncHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes registra as rotas no router do Gin
+func (h *DNSSyncHandler) RegisterRoutes(router *gin.RouterGroup) {
+	dnsGroup := router.Group("/dns")
+	{
+		dnsGroup.POST("/:domain/sync/plan", h.Plan)
+		dnsGroup.POST("/:domain/sync/apply", h.Apply)
+	}
+}
+
+// Plan godoc
+// @Summary Planeja a sincronização dos registros DNS
+// @Description Recebe o conjunto completo de registros desejados (JSON ou YAML) e retorna o plano com os registros a criar, atualizar e excluir.
+// @Description Registros existentes que não estiverem na lista serão excluídos, exceto os NS do apex.
+// @Tags DNS
+// @Accept json
+// @Accept application/x-yaml
+// @Produce json
+// @Param domain path string true "Domínio a sincronizar"
+// @Param request body models.DNSSyncRequest true "Estado desejado"
+// @Success 200 {object} models.DNSSyncPlan
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /dns/{domain}/sync/plan [post]
+func (h *DNSSyncHandler) Plan(c *gin.Context) {
+	var request models.DNSSyncRequest
+
+	bind := binding.JSON
+	switch c.ContentType() {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		bind = binding.YAML
+	}
+	if err := c.ShouldBindWith(&request, bind); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	plan, err := h.service.Plan(c.Request.Context(), c.Param("domain"), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// Apply godoc
+// @Summary Executa um plano de sincronização DNS
+// @Description Executa as alterações de um plano criado em /dns/{domain}/sync/plan e retorna o resultado de cada registro.
+// @Description Se os registros mudaram desde a criação do plano, a execução é recusada com 409.
+// @Tags DNS
+// @Accept json
+// @Produce json
+// @Param domain path string true "Domínio a sincronizar"
+// @Param request body models.DNSSyncApplyRequest true "Plano a executar e concorrência"
+// @Success 200 {object} models.DNSSyncApplyResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /dns/{domain}/sync/apply [post]
+func (h *DNSSyncHandler) Apply(c *gin.Context) {
+	var request models.DNSSyncApplyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	response, err := h.service.Apply(c.Request.Context(), c.Param("domain"), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -11,15 +11,15 @@ import (
 
 // DomainHandler handles domain + smart rule operations
 type DomainHandler struct {
-	domainService    *services.DomainService
-	smartRuleService *services.SmartRuleService
+	domainService   *services.DomainService
+	deletionService *services.DomainDeletionService
 }
 
 // NewDomainHandler creates a new DomainHandler
-func NewDomainHandler(domainService *services.DomainService, smartRuleService *services.SmartRuleService) *DomainHandler {
+func NewDomainHandler(domainService *services.DomainService, deletionService *services.DomainDeletionService) *DomainHandler {
 	return &DomainHandler{
-		domainService:    domainService,
-		smartRuleService: smartRuleService,
+		domainService:   domainService,
+		deletionService: deletionService,
 	}
 }
 
@@ -29,7 +29,9 @@ func (h *DomainHandler) RegisterRoutes(router *gin.RouterGroup) {
 	{
 		group.GET("", h.ListDomains)
 		group.POST("", h.CreateDomain)
-		group.DELETE("/:domainID", h.DeleteDomainWithSmartRules)
+		group.DELETE("/:domain", h.DeleteDomainWithSmartRules)
+		group.GET("/:domain/settings", h.GetDomainSettings)
+		group.PATCH("/:domain/settings", h.UpdateDomainSettings)
 	}
 
 	rulesGroup := router.Group("/rules")
@@ -52,13 +54,13 @@ func (h *DomainHandler) RegisterRoutes(router *gin.RouterGroup) {
 func (h *DomainHandler) CreateDomain(c *gin.Context) {
 	var req models.DomainCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
 		return
 	}
 
-	result, err := h.domainService.CreateDomain(req)
+	result, err := h.domainService.CreateDomain(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -79,9 +81,9 @@ func (h *DomainHandler) CreateSmartRule(c *gin.Context) {
 // @Failure 500 {object} map[string]interface{}
 // @Router /domains [get]
 func (h *DomainHandler) ListDomains(c *gin.Context) {
-	domains, err := h.domainService.ListDomains()
+	domains, err := h.domainService.ListDomains(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao listar domínios: " + err.Error()})
+		c.JSON(statusFromError(err), gin.H{"error": "falha ao listar domínios: " + err.Error()})
 		return
 	}
 
@@ -89,29 +91,92 @@ func (h *DomainHandler) ListDomains(c *gin.Context) {
 }
 
 // DeleteDomainWithSmartRules godoc
-// @Summary Delete domain
-// @Description Deletes a domain
+// @Summary Remove um domínio e suas configurações
+// @Description Remove as Smart Rules, os redirecionamentos, os registros DNS e os mapeamentos locais do proxy do domínio e, se todos forem removidos, o próprio domínio. Com dry_run=true, apenas lista o que seria removido.
 // @Tags Domains
-// @Param domainID path int true "Domain ID"
-// @Success 200 {object} map[string]interface{}
+// @Produce json
+// @Param domain path string true "Nome do domínio"
+// @Param dry_run query bool false "Apenas lista o que seria removido (padrão: false)"
+// @Success 200 {object} models.DomainDeletionResponse
 // @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
-// @Router /domains/{domainID} [delete]
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} models.DomainDeletionResponse
+// @Router /domains/{domain} [delete]
 func (h *DomainHandler) DeleteDomainWithSmartRules(c *gin.Context) {
-	domainIDStr := c.Param("domainID")
-	domainID, err := strconv.Atoi(domainIDStr)
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run inválido"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	response, err := h.deletionService.DeleteDomain(requesterContext(c), c.Param("domain"), dryRun)
+	switch {
+	case err != nil && response == nil:
+		respondError(c, err)
+	case err != nil:
+		c.JSON(statusFromError(err), response)
+	default:
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetDomainSettings godoc
+// @Summary Consulta as configurações de um domínio
+// @Description Retorna origem, TTL de cache, WAF, modo de CDN, modo SSL e compressão do domínio na GoCache
+// @Tags Domains
+// @Produce json
+// @Param domain path string true "Nome do domínio"
+// @Success 200 {object} models.DomainSettingsResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /domains/{domain}/settings [get]
+func (h *DomainHandler) GetDomainSettings(c *gin.Context) {
+	domain := c.Param("domain")
+	settings, err := h.domainService.GetDomainSettings(c.Request.Context(), domain)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid domain ID"})
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DomainSettingsResponse{
+		Status:   true,
+		Domain:   domain,
+		Settings: *settings,
+	})
+}
+
+// UpdateDomainSettings godoc
+// @Summary Altera as configurações de um domínio
+// @Description Altera apenas as configurações informadas e retorna as configurações resultantes
+// @Tags Domains
+// @Accept json
+// @Produce json
+// @Param domain path string true "Nome do domínio"
+// @Param request body models.DomainSettings true "Configurações a alterar"
+// @Success 200 {object} models.DomainSettingsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /domains/{domain}/settings [patch]
+func (h *DomainHandler) UpdateDomainSettings(c *gin.Context) {
+	var request models.DomainSettings
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
 		return
 	}
 
-	// A funcionalidade de listar e excluir Smart Rules foi movida para outro endpoint
-	// Apenas excluir o domínio
-	err = h.domainService.DeleteDomain(domainID)
+	domain := c.Param("domain")
+	settings, err := h.domainService.UpdateDomainSettings(c.Request.Context(), domain, request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete domain: " + err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "domain deleted"})
+	c.JSON(http.StatusOK, models.DomainSettingsResponse{
+		Status:   true,
+		Domain:   domain,
+		Settings: *settings,
+	})
 }
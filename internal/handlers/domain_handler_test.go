@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+// TestDomainHandlerDelete verifica que DELETE /domains/{domain} recebe o nome do domínio
+func TestDomainHandlerDelete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := gocachetest.NewServer()
+	t.Cleanup(srv.Close)
+	client := srv.Client(gocache.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	srv.AddDomain("a.com", nil)
+	srv.AddDNS("a.com", gocachetest.DNSRecord{Name: "www", Type: "A", Content: "1.1.1.1", TTL: "300"})
+
+	domains := services.NewDomainService(client)
+	deletion := services.NewDomainDeletionService(domains, services.NewDNSService(client), services.NewSmartRuleRewriteService(client), services.NewRedirectService(client), nil)
+	router := gin.New()
+	NewDomainHandler(domains, deletion).RegisterRoutes(router.Group("/api/v1"))
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"dry_run inválido", "/api/v1/domains/a.com?dry_run=talvez", 400, "dry_run inválido"},
+		{"simulação", "/api/v1/domains/a.com?dry_run=true", 200, `"dry_run":true`},
+		{"remove", "/api/v1/domains/a.com", 200, `"domain":"a.com"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, "DELETE", tt.path, "")
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("DELETE %s = %d %s, esperado %d contendo %q", tt.path, w.Code, w.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+
+	if names := srv.Domains(); len(names) != 0 {
+		t.Errorf("domínios após a remoção = %v", names)
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
+)
+
+// statusFromError traduz um erro dos serviços para o status HTTP adequado.
+// Erros de validação locais viram 400 e os da Gocache são repassados ao cliente;
+// falhas de autenticação e erros 5xx indicam problema na integração e viram 502.
+func statusFromError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	}
+
+	apiErr, ok := gocache.AsAPIError(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusConflict,
+		http.StatusUnprocessableEntity, http.StatusTooManyRequests:
+		return apiErr.StatusCode
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// respondError escreve a resposta de erro padrão da API, incluindo o request ID da Gocache quando houver
+func respondError(c *gin.Context, err error) {
+	body := gin.H{"error": err.Error()}
+	if apiErr, ok := gocache.AsAPIError(err); ok && apiErr.RequestID != "" {
+		body["request_id"] = apiErr.RequestID
+	}
+	c.JSON(statusFromError(err), body)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// maxMappingImportSize limita o corpo da importação em lote de mapeamentos
+const maxMappingImportSize = 5 << 20
+
+// parseMappingsJSON lê uma lista de mapeamentos ou um objeto {"mappings": [...]}
+func parseMappingsJSON(r io.Reader) ([]models.DomainMapping, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o corpo da requisição: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+
+	var mappings []models.DomainMapping
+	if bytes.HasPrefix(data, []byte("{")) {
+		var wrapper struct {
+			Mappings []models.DomainMapping `json:"mappings"`
+		}
+		err = json.Unmarshal(data, &wrapper)
+		mappings = wrapper.Mappings
+	} else {
+		err = json.Unmarshal(data, &mappings)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("JSON inválido: %w", err)
+	}
+	return mappings, nil
+}
+
+// parseMappingsCSV lê um CSV com cabeçalho. As colunas domain e destination são obrigatórias;
+// mode e fallbacks (destinos alternativos separados por "|") são opcionais. Retorna também a linha de cada mapeamento, usada nas mensagens de erro.
+func parseMappingsCSV(r io.Reader) ([]models.DomainMapping, []int, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("CSV inválido: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"domain", "destination"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV sem a coluna obrigatória %q (cabeçalho esperado: domain,destination,mode)", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var (
+		mappings []models.DomainMapping
+		lines    []int
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("CSV inválido: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		var fallbacks []string
+		for _, fallback := range strings.Split(field(record, "fallbacks"), "|") {
+			if fallback = strings.TrimSpace(fallback); fallback != "" {
+				fallbacks = append(fallbacks, fallback)
+			}
+		}
+
+		mappings = append(mappings, models.DomainMapping{
+			Domain:      field(record, "domain"),
+			Destination: field(record, "destination"),
+			Mode:        models.MappingMode(field(record, "mode")),
+			Fallbacks:   fallbacks,
+		})
+		lines = append(lines, line)
+	}
+	return mappings, lines, nil
+}
+
+// setMappingETag devolve a versão do mapeamento no header ETag
+func setMappingETag(c *gin.Context, mapping models.DomainMapping) {
+	if mapping.Version > 0 {
+		c.Header("ETag", strconv.Quote(strconv.FormatInt(mapping.Version, 10)))
+	}
+}
+
+// expectedVersion lê a versão esperada do header If-Match ("3", W/"3" ou *). Retorna zero
+// quando o header não foi enviado ou é "*", casos em que a versão não é verificada.
+func expectedVersion(c *gin.Context) (int64, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("header If-Match inválido: %q", c.GetHeader("If-Match"))
+	}
+	return version, nil
+}
+
+// positiveQueryInt lê um parâmetro inteiro positivo da query string
+func positiveQueryInt(c *gin.Context, name string, defaultValue int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("valor inválido para %s: %q", name, value)
+	}
+	return n, nil
+}
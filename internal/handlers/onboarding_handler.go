@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+)
+
+// OnboardingHandler manipula o onboarding de domínios (domínio, DNS e Smart Rule)
+type OnboardingHandler struct {
+	service *services.OnboardingService
+}
+
+// NewOnboardingHandler cria uma nova instância de OnboardingHandler
+func NewOnboardingHandler(service *services.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes registra as rotas no router do Gin
+func (h *OnboardingHandler) RegisterRoutes(router *gin.RouterGroup) {
+	group := router.Group("/onboarding")
+	{
+		group.POST("", h.StartOnboarding)
+		group.GET("", h.ListOnboardings)
+		group.GET("/:id", h.GetOnboarding)
+		group.POST("/:id/resume", h.ResumeOnboarding)
+	}
+}
+
+// StartOnboarding godoc
+// @Summary Cadastra um domínio com registro DNS e Smart Rule
+// @Description Cria o domínio na Gocache, o registro DNS e a regra de redirecionamento para o bucket, nessa ordem. Se uma etapa falhar, as anteriores são desfeitas (o registro DNS e o domínio são removidos) e a resposta traz o estado de cada etapa.
+// @Tags Onboarding
+// @Accept json
+// @Produce json
+// @Param request body models.OnboardingRequest true "Domínio, registro DNS e regra"
+// @Success 201 {object} models.OnboardingResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} models.OnboardingResponse
+// @Router /onboarding [post]
+func (h *OnboardingHandler) StartOnboarding(c *gin.Context) {
+	var request models.OnboardingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bindingErrorMessage(err)})
+		return
+	}
+
+	workflow, err := h.service.Start(requesterContext(c), request)
+	h.respondWorkflow(c, http.StatusCreated, workflow, err)
+}
+
+// ResumeOnboarding godoc
+// @Summary Retoma um onboarding
+// @Description Executa novamente as etapas de um onboarding interrompido ou desfeito. Etapas cujo recurso ainda existe não são repetidas.
+// @Tags Onboarding
+// @Produce json
+// @Param id path string true "ID do onboarding"
+// @Success 200 {object} models.OnboardingResponse
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} models.OnboardingResponse
+// @Router /onboarding/{id}/resume [post]
+func (h *OnboardingHandler) ResumeOnboarding(c *gin.Context) {
+	workflow, err := h.service.Resume(requesterContext(c), c.Param("id"))
+	h.respondWorkflow(c, http.StatusOK, workflow, err)
+}
+
+// GetOnboarding godoc
+// @Summary Consulta um onboarding
+// @Description Retorna a situação do onboarding e de cada etapa
+// @Tags Onboarding
+// @Produce json
+// @Param id path string true "ID do onboarding"
+// @Success 200 {object} models.OnboardingResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /onboarding/{id} [get]
+func (h *OnboardingHandler) GetOnboarding(c *gin.Context) {
+	workflow, err := h.service.Get(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.OnboardingResponse{
+		Status:   true,
+		Workflow: *workflow,
+	})
+}
+
+// ListOnboardings godoc
+// @Summary Lista os onboardings
+// @Description Retorna os onboardings, do mais recente para o mais antigo
+// @Tags Onboarding
+// @Produce json
+// @Param domain query string false "Filtra pelo domínio"
+// @Param limit query int false "Número máximo de onboardings (padrão: 100)"
+// @Success 200 {object} models.OnboardingListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /onboarding [get]
+func (h *OnboardingHandler) ListOnboardings(c *gin.Context) {
+	limit, err := positiveQueryInt(c, "limit", 100)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workflows := h.service.List(c.Query("domain"), limit)
+	c.JSON(http.StatusOK, models.OnboardingListResponse{
+		Status:    true,
+		Workflows: workflows,
+		Total:     len(workflows),
+	})
+}
+
+// respondWorkflow responde com o estado do onboarding. Quando uma etapa falhou, o status
+// HTTP vem do erro e o corpo traz o estado de cada etapa após a compensação.
+func (h *OnboardingHandler) respondWorkflow(c *gin.Context, status int, workflow *models.OnboardingWorkflow, err error) {
+	switch {
+	case err != nil && workflow == nil:
+		respondError(c, err)
+	case err != nil:
+		c.JSON(statusFromError(err), models.OnboardingResponse{
+			Status:   false,
+			Error:    err.Error(),
+			Workflow: *workflow,
+		})
+	default:
+		c.JSON(status, models.OnboardingResponse{
+			Status:   true,
+			Workflow: *workflow,
+		})
+	}
+}
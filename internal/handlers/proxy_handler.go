@@ -1,12 +1,13 @@
 package handlers
 
 import (
-	"log"
+	"errors"
 	"net/http"
-	"strings"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/proxy"
 	"github.com/renatoroquejani/poc-gocache/internal/services"
 )
 
@@ -23,49 +24,199 @@ func NewProxyHandler(service *services.ProxyService) *ProxyHandler {
 }
 
 // AddMapping adiciona um novo mapeamento de domu00ednio
+// @Summary Cria um mapeamento de domínio
+// @Description Cria o mapeamento do domínio para o destino; responde 409 se o domínio já estiver mapeado
+// @Tags Proxy
+// @Accept json
+// @Produce json
+// @Param mapping body models.DomainMapping true "Mapeamento"
+// @Success 201 {object} models.DomainMappingResponse
+// @Failure 400 {object} models.DomainMappingResponse
+// @Failure 409 {object} models.DomainMappingResponse
+// @Router /proxy/mappings [post]
 func (h *ProxyHandler) AddMapping(c *gin.Context) {
 	var mapping models.DomainMapping
 	if err := c.ShouldBindJSON(&mapping); err != nil {
 		c.JSON(http.StatusBadRequest, models.DomainMappingResponse{
 			Success: false,
-			Error:   err.Error(),
+			Error:   bindingErrorMessage(err),
 		})
 		return
 	}
 
-	err := h.service.AddMapping(mapping)
+	// Um domínio já mapeado retorna 409; para alterá-lo use PUT
+	created, err := h.service.AddMapping(mapping)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.DomainMappingResponse{
+		c.JSON(statusFromError(err), models.DomainMappingResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	setMappingETag(c, created)
 	c.JSON(http.StatusCreated, models.DomainMappingResponse{
 		Success: true,
-		Mapping: mapping,
+		Mapping: created,
 	})
 }
 
-// GetMappings lista todos os mapeamentos de domu00ednio
+// GetMappings lista os mapeamentos de domínio, com filtros e paginação:
+// ?domain=trecho&destination=trecho&mode=proxy&page=1&page_size=50
+// @Summary Lista os mapeamentos de domínio
+// @Tags Proxy
+// @Produce json
+// @Param domain query string false "Trecho do domínio"
+// @Param destination query string false "Trecho do destino"
+// @Param mode query string false "Modo (redirect301, redirect302 ou proxy)"
+// @Param page query int false "Página (padrão: 1)"
+// @Param page_size query int false "Itens por página (padrão: 50, máximo: 500)"
+// @Success 200 {object} models.DomainMappingsListResponse
+// @Failure 400 {object} models.DomainMappingsListResponse
+// @Router /proxy/mappings [get]
 func (h *ProxyHandler) GetMappings(c *gin.Context) {
-	mappings := h.service.GetAllMappings()
+	filter := services.MappingFilter{
+		Domain:      c.Query("domain"),
+		Destination: c.Query("destination"),
+		Mode:        models.MappingMode(c.Query("mode")),
+	}
+	if !filter.Mode.Valid() {
+		c.JSON(http.StatusBadRequest, models.DomainMappingsListResponse{
+			Success: false,
+			Error:   "modo de mapeamento inválido (use redirect301, redirect302 ou proxy)",
+		})
+		return
+	}
 
+	var err error
+	if filter.Page, err = positiveQueryInt(c, "page", 1); err == nil {
+		filter.PageSize, err = positiveQueryInt(c, "page_size", services.DefaultMappingPageSize)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.DomainMappingsListResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	filter.PageSize = min(filter.PageSize, services.MaxMappingPageSize)
+
+	mappings, total := h.service.ListMappings(filter)
 	c.JSON(http.StatusOK, models.DomainMappingsListResponse{
 		Success:  true,
 		Mappings: mappings,
-		Total:    len(mappings),
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+	})
+}
+
+// GetMapping retorna o mapeamento cadastrado para o domínio, com a versão no header ETag
+// @Summary Retorna o mapeamento de um domínio
+// @Tags Proxy
+// @Produce json
+// @Param domain path string true "Domínio (exato, *.exemplo.com ou *)"
+// @Success 200 {object} models.DomainMappingResponse
+// @Failure 404 {object} models.DomainMappingResponse
+// @Router /proxy/mappings/{domain} [get]
+func (h *ProxyHandler) GetMapping(c *gin.Context) {
+	mapping, err := h.service.GetMappingByDomain(c.Param("domain"))
+	if err != nil {
+		c.JSON(statusFromError(err), models.DomainMappingResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	setMappingETag(c, mapping)
+	c.JSON(http.StatusOK, models.DomainMappingResponse{
+		Success: true,
+		Mapping: mapping,
+	})
+}
+
+// UpdateMapping substitui um mapeamento existente. A versão esperada pode ser enviada no
+// header If-Match (ETag retornado pelo GET) ou no campo version; se não for a atual, responde 412.
+// @Summary Substitui um mapeamento de domínio
+// @Tags Proxy
+// @Accept json
+// @Produce json
+// @Param domain path string true "Domínio do mapeamento"
+// @Param If-Match header string false "Versão esperada (ETag do GET)"
+// @Param request body models.DomainMappingUpdateRequest true "Novo destino, modo, alternativos e regras"
+// @Success 200 {object} models.DomainMappingResponse
+// @Failure 400 {object} models.DomainMappingResponse
+// @Failure 404 {object} models.DomainMappingResponse
+// @Failure 412 {object} models.DomainMappingResponse
+// @Router /proxy/mappings/{domain} [put]
+func (h *ProxyHandler) UpdateMapping(c *gin.Context) {
+	var request models.DomainMappingUpdateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.DomainMappingResponse{
+			Success: false,
+			Error:   bindingErrorMessage(err),
+		})
+		return
+	}
+
+	version, err := expectedVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.DomainMappingResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if version == 0 {
+		version = request.Version
+	}
+
+	updated, err := h.service.UpdateMapping(c.Param("domain"), request, version)
+	if err != nil {
+		if errors.Is(err, services.ErrPreconditionFailed) {
+			setMappingETag(c, updated)
+		}
+		c.JSON(statusFromError(err), models.DomainMappingResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	setMappingETag(c, updated)
+	c.JSON(http.StatusOK, models.DomainMappingResponse{
+		Success: true,
+		Mapping: updated,
 	})
 }
 
-// DeleteMapping remove um mapeamento de domu00ednio
+// DeleteMapping remove um mapeamento de domu00ednio. Aceita If-Match como o PUT.
+// @Summary Remove um mapeamento de domínio
+// @Tags Proxy
+// @Produce json
+// @Param domain path string true "Domínio do mapeamento"
+// @Param If-Match header string false "Versão esperada (ETag do GET)"
+// @Success 200 {object} models.DomainMappingResponse
+// @Failure 400 {object} models.DomainMappingResponse
+// @Failure 404 {object} models.DomainMappingResponse
+// @Failure 412 {object} models.DomainMappingResponse
+// @Router /proxy/mappings/{domain} [delete]
 func (h *ProxyHandler) DeleteMapping(c *gin.Context) {
 	domain := c.Param("domain")
 
-	err := h.service.DeleteMapping(domain)
+	version, err := expectedVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.DomainMappingResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err = h.service.DeleteMapping(domain, version)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.DomainMappingResponse{
+		c.JSON(statusFromError(err), models.DomainMappingResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -77,48 +228,105 @@ func (h *ProxyHandler) DeleteMapping(c *gin.Context) {
 	})
 }
 
-// HandleRedirect processa redirecionamentos com base nos mapeamentos configurados
-func (h *ProxyHandler) HandleRedirect(c *gin.Context) {
-	host := c.Request.Host
-	path := c.Request.URL.Path
+// ImportMappings importa mapeamentos em lote. O corpo pode ser JSON (lista de mapeamentos ou
+// {"mappings": [...]}) ou CSV (Content-Type text/csv) com cabeçalho domain,destination[,mode].
+// Com ?overwrite=true os domínios existentes são substituídos; sem ele, viram erro (409).
+// @Summary Importa mapeamentos em lote
+// @Description Valida todos os itens antes de gravar: se algum for rejeitado, nada é gravado e a resposta lista a linha e o motivo de cada erro
+// @Tags Proxy
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param overwrite query bool false "Substitui os domínios já mapeados"
+// @Param request body []models.DomainMapping true "Mapeamentos (JSON ou CSV)"
+// @Success 200 {object} models.MappingImportResponse
+// @Failure 400 {object} models.MappingImportResponse
+// @Failure 409 {object} models.MappingImportResponse
+// @Router /proxy/mappings/import [post]
+func (h *ProxyHandler) ImportMappings(c *gin.Context) {
+	overwrite := false
+	if value := c.Query("overwrite"); value != "" {
+		var err error
+		if overwrite, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "valor inválido para overwrite"})
+			return
+		}
+	}
 
-	log.Printf("Recebida requisiu00e7u00e3o para host: %s, path: %s", host, path)
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxMappingImportSize)
 
-	// Remove a porta do host, se presente
-	if strings.Contains(host, ":") {
-		host = strings.Split(host, ":")[0]
+	var (
+		mappings []models.DomainMapping
+		lines    []int
+		err      error
+	)
+	switch c.ContentType() {
+	case "text/csv", "application/csv":
+		mappings, lines, err = parseMappingsCSV(body)
+	default:
+		mappings, err = parseMappingsJSON(body)
 	}
-
-	// Procura o mapeamento correspondente
-	mapping, err := h.service.GetMapping(host)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "domu00ednio nu00e3o configurado"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(mappings) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nenhum mapeamento para importar"})
 		return
 	}
 
-	destination := mapping.Destination
-
-	// Se o path nu00e3o for a raiz, adiciona ao destino
-	if path != "/" {
-		// Remove a barra inicial do path se o destino ju00e1 terminar com barra
-		if strings.HasSuffix(destination, "/") && strings.HasPrefix(path, "/") {
-			path = path[1:]
+	response, err := h.service.ImportMappings(mappings, lines, overwrite)
+	if err != nil {
+		if response == nil {
+			respondError(c, err)
+			return
 		}
-		destination = destination + path
+		c.JSON(statusFromError(err), response)
+		return
 	}
 
-	log.Printf("Redirecionando para: %s", destination)
-	c.Redirect(http.StatusMovedPermanently, destination)
+	c.JSON(http.StatusOK, response)
+}
+
+// HandleRedirect processa redirecionamentos (ou proxy) com base nos mapeamentos configurados
+func (h *ProxyHandler) HandleRedirect(c *gin.Context) {
+	proxy.NewHandler(h.service, nil).ServeHTTP(c.Writer, c.Request)
+}
+
+// GetHealth retorna o resultado da última verificação de saúde dos destinos de cada
+// mapeamento e o destino ativo de cada um
+// @Summary Retorna a saúde dos destinos dos mapeamentos
+// @Tags Proxy
+// @Produce json
+// @Success 200 {object} models.ProxyHealthResponse
+// @Router /proxy/health [get]
+func (h *ProxyHandler) GetHealth(c *gin.Context) {
+	report, enabled := h.service.HealthReport()
+	if report == nil {
+		report = []models.MappingHealth{}
+	}
+	c.JSON(http.StatusOK, models.ProxyHealthResponse{
+		Success:  true,
+		Enabled:  enabled,
+		Mappings: report,
+	})
 }
 
 // RegisterRoutes registra as rotas do handler no router
 func (h *ProxyHandler) RegisterRoutes(router *gin.Engine) {
-	proxyGroup := router.Group("/api/v1/proxy")
-	{
-		proxyGroup.POST("/mappings", h.AddMapping)
-		proxyGroup.GET("/mappings", h.GetMappings)
-		proxyGroup.DELETE("/mappings/:domain", h.DeleteMapping)
-	}
+	h.RegisterMappingRoutes(router.Group("/api/v1/proxy"))
 
 	// Rota para processar redirecionamentos (deve ser registrada separadamente no main.go)
 }
+
+// RegisterMappingRoutes registra as rotas de gerenciamento de mapeamentos no grupo informado.
+// É usada pela API (/api/v1/proxy) e pelo cmd/proxy (/api), que expõem as mesmas operações.
+func (h *ProxyHandler) RegisterMappingRoutes(group *gin.RouterGroup) {
+	group.POST("/mappings", h.AddMapping)
+	group.POST("/mappings/import", h.ImportMappings)
+	group.GET("/mappings", h.GetMappings)
+	group.GET("/mappings/:domain", h.GetMapping)
+	group.PUT("/mappings/:domain", h.UpdateMapping)
+	group.DELETE("/mappings/:domain", h.DeleteMapping)
+	group.GET("/health", h.GetHealth)
+}
@@ -29,9 +29,9 @@ func (h *RedirectHandler) CreateRedirect(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.CreateRedirect(&request)
+	response, err := h.service.CreateRedirect(requesterContext(c), &request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -46,9 +46,9 @@ func (h *RedirectHandler) ListRedirects(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.ListRedirects(domain)
+	response, err := h.service.ListRedirects(c.Request.Context(), domain)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -66,9 +66,9 @@ func (h *RedirectHandler) DeleteRedirect(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.DeleteRedirect(domain, id)
+	response, err := h.service.DeleteRedirect(requesterContext(c), domain, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -50,9 +50,9 @@ func (h *SmartRuleRewriteHandler) CreateRewriteRule(c *gin.Context) {
 	request.Domain = domain
 
 	// Cria a regra de redirecionamento
-	response, err := h.service.CreateRewriteRule(&request)
+	response, err := h.service.CreateRewriteRule(requesterContext(c), &request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -79,9 +79,9 @@ func (h *SmartRuleRewriteHandler) ListRewriteRules(c *gin.Context) {
 	}
 
 	// Lista as regras de redirecionamento
-	response, err := h.service.ListRewriteRules(domain)
+	response, err := h.service.ListRewriteRules(c.Request.Context(), domain)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -111,9 +111,9 @@ func (h *SmartRuleRewriteHandler) DeleteRewriteRule(c *gin.Context) {
 	}
 
 	// Remove a regra de redirecionamento
-	response, err := h.service.DeleteRewriteRule(domain, id)
+	response, err := h.service.DeleteRewriteRule(requesterContext(c), domain, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -153,9 +153,9 @@ func (h *SmartRuleRewriteHandler) UpdateRewriteRule(c *gin.Context) {
 	request.Domain = domain
 
 	// Atualiza a regra de redirecionamento
-	response, err := h.service.UpdateRewriteRule(domain, id, &request)
+	response, err := h.service.UpdateRewriteRule(requesterContext(c), domain, id, &request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -197,9 +197,9 @@ func (h *SmartRuleRewriteHandler) CreateSimplifiedRule(c *gin.Context) {
 	}
 
 	// Cria a regra de redirecionamento simplificada
-	response, err := h.service.CreateSimplifiedRule(&request)
+	response, err := h.service.CreateSimplifiedRule(requesterContext(c), &request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -246,9 +246,9 @@ func (h *SmartRuleRewriteHandler) CreateSimplifiedRuleWithDomain(c *gin.Context)
 	request.ParentDomain = domain
 
 	// Cria a regra de redirecionamento simplificada
-	response, err := h.service.CreateSimplifiedRule(&request)
+	response, err := h.service.CreateSimplifiedRule(requesterContext(c), &request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -264,9 +264,9 @@ func (h *SmartRuleRewriteHandler) GetSimplifiedRuleForm(c *gin.Context) {
 	var domainResponse models.DomainListResponse
 	endpoint := "/domain"
 	
-	_, err := h.service.GetClient().Get(endpoint, &domainResponse)
+	_, err := h.service.GetClient().GetContext(c.Request.Context(), endpoint, &domainResponse)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "falha ao obter domínios: " + err.Error()})
+		c.JSON(statusFromError(err), gin.H{"error": "falha ao obter domínios: " + err.Error()})
 		return
 	}
 	
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	_ = v.RegisterValidation("dns_type", func(fl validator.FieldLevel) bool {
+		return models.DNSRecordType(fl.Field().String()).Valid()
+	})
+	_ = v.RegisterValidation("dns_name", func(fl validator.FieldLevel) bool {
+		return models.ValidateDNSName(fl.Field().String()) == nil
+	})
+	v.RegisterStructValidation(validateDNSCreateRequest, models.DNSCreateRequest{})
+	v.RegisterStructValidation(validateDNSUpdateRequest, models.DNSUpdateRequest{})
+	v.RegisterStructValidation(validateDNSSyncRecord, models.DNSSyncRecord{})
+}
+
+func validateDNSCreateRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.DNSCreateRequest)
+	reportDNSContent(sl, req.Type, req.Content)
+}
+
+func validateDNSUpdateRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.DNSUpdateRequest)
+	reportDNSContent(sl, req.Type, req.Content)
+}
+
+func validateDNSSyncRecord(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.DNSSyncRecord)
+	reportDNSContent(sl, req.Type, req.Content)
+}
+
+// reportDNSContent valida o conteúdo conforme o tipo; a mensagem detalhada vai no parâmetro do erro
+func reportDNSContent(sl validator.StructLevel, recordType models.DNSRecordType, content string) {
+	if !recordType.Valid() || content == "" {
+		// Tipo e conteúdo ausentes ou inválidos já são reportados pelas tags dos campos
+		return
+	}
+	if err := models.ValidateDNSContent(recordType, content); err != nil {
+		sl.ReportError(content, "Content", "content", "dns_content", err.Error())
+	}
+}
+
+// bindingErrorMessage converte erros de validação em mensagens legíveis para o cliente da API
+func bindingErrorMessage(err error) string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err.Error()
+	}
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		field := strings.ToLower(fe.Field())
+		switch fe.Tag() {
+		case "required":
+			messages = append(messages, fmt.Sprintf("%s é obrigatório", field))
+		case "dns_type":
+			types := make([]string, len(models.DNSRecordTypes))
+			for i, t := range models.DNSRecordTypes {
+				types[i] = string(t)
+			}
+			messages = append(messages, fmt.Sprintf("tipo de registro inválido %q (use %s)", fe.Value(), strings.Join(types, ", ")))
+		case "dns_name":
+			messages = append(messages, fmt.Sprintf("nome de registro inválido: %q", fe.Value()))
+		case "dns_content":
+			messages = append(messages, fe.Param())
+		default:
+			messages = append(messages, fmt.Sprintf("%s inválido (%s=%s)", field, fe.Tag(), fe.Param()))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
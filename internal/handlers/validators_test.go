@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+func TestBindingErrorMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"campos obrigatórios", `{}`, "name é obrigatório; type é obrigatório; content é obrigatório; ttl é obrigatório"},
+		{"tipo inválido", `{"name":"www","type":"PTR","content":"a.com","ttl":300}`, `tipo de registro inválido "PTR" (use A, AAAA, CNAME, MX, TXT, NS, CAA, SRV)`},
+		{"nome inválido", `{"name":"w w","type":"A","content":"1.2.3.4","ttl":300}`, `nome de registro inválido: "w w"`},
+		{"conteúdo do tipo A", `{"name":"www","type":"A","content":"exemplo.com","ttl":300}`, `registro A requer um endereço IPv4, recebido "exemplo.com"`},
+		{"conteúdo do tipo CNAME", `{"name":"www","type":"cname","content":"1.2.3.4","ttl":300}`, "registro CNAME deve apontar para um hostname, não para um IP"},
+		{"prioridade MX", `{"name":"@","type":"MX","content":"x mail.a.com","ttl":300}`, `prioridade MX inválida: "x"`},
+		{"ttl mínimo", `{"name":"www","type":"A","content":"1.2.3.4","ttl":0}`, "ttl é obrigatório"},
+		{"ttl negativo", `{"name":"www","type":"A","content":"1.2.3.4","ttl":-1}`, "ttl inválido (min=1)"},
+		{"cloud", `{"name":"www","type":"A","content":"1.2.3.4","ttl":300,"cloud":2}`, "cloud inválido (oneof=0 1)"},
+		{"JSON malformado", `{"name":`, "unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var request models.DNSCreateRequest
+			err := c.ShouldBindJSON(&request)
+			if err == nil {
+				t.Fatal("ShouldBindJSON() não retornou erro")
+			}
+			if got := bindingErrorMessage(err); !strings.Contains(got, tt.want) {
+				t.Errorf("bindingErrorMessage() = %q, esperado conter %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindingAcceptsValidRecords(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, body := range []string{
+		`{"name":"www","type":"a","content":"1.2.3.4","ttl":"300","cloud":true}`,
+		`{"name":"@","type":"MX","content":"10 mail.a.com","ttl":3600}`,
+		`{"name":"_dmarc","type":"TXT","content":"v=DMARC1; p=none","ttl":300}`,
+		`{"name":"*.app","type":"CNAME","content":"app.a.com.","ttl":300}`,
+	} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		var request models.DNSUpdateRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			t.Errorf("%s: %s", body, bindingErrorMessage(err))
+		}
+	}
+}
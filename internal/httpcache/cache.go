@@ -0,0 +1,419 @@
+// Package httpcache implementa o cache HTTP local usado pelo proxy no modo "proxy" dos
+// mapeamentos. Ele respeita Cache-Control, revalida com ETag/Last-Modified, separa
+// variantes por Vary e descarta os itens menos usados quando atinge o limite de tamanho.
+package httpcache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxBytes é o tamanho máximo padrão do cache (soma dos corpos armazenados)
+	DefaultMaxBytes = 256 << 20
+	// DefaultMaxEntryBytes é o tamanho máximo padrão de uma resposta armazenada
+	DefaultMaxEntryBytes = 10 << 20
+
+	diskSuffix = ".cache"
+)
+
+// Options configura o cache
+type Options struct {
+	// MaxBytes limita a soma dos corpos armazenados; ao ultrapassar, os itens menos
+	// usados recentemente são descartados
+	MaxBytes int64
+	// MaxEntryBytes limita o tamanho de uma resposta; respostas maiores não são armazenadas
+	MaxEntryBytes int64
+	// DefaultTTL é aplicado a respostas sem Cache-Control nem Expires. Com zero, essas
+	// respostas só são armazenadas se tiverem ETag ou Last-Modified, e são sempre revalidadas.
+	DefaultTTL time.Duration
+	// Dir, quando definido, grava os corpos em disco para que o cache sobreviva a reinícios
+	Dir string
+}
+
+// Stats resume o estado do cache
+type Stats struct {
+	Entries int   `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+}
+
+// entry é uma resposta armazenada. No modo em disco, body fica vazio e o conteúdo é lido
+// do arquivo a cada acerto.
+type entry struct {
+	Key        string
+	URL        string
+	Host       string
+	Status     int
+	Header     http.Header
+	StoredAt   time.Time
+	InitialAge time.Duration
+	VaryNames  []string
+	VaryValues []string
+	Size       int64
+	Body       []byte
+
+	element *list.Element
+}
+
+// Cache é um cache HTTP compartilhado em memória, opcionalmente persistido em disco
+type Cache struct {
+	options Options
+
+	mutex   sync.Mutex
+	entries map[string]*entry   // chave: URL + valores de Vary
+	byURL   map[string][]string // URL pública -> chaves das variantes
+	lru     *list.List          // frente = usado mais recentemente
+	bytes   int64
+	hits    int64
+	misses  int64
+}
+
+// New cria o cache. Se opts.Dir estiver definido, os itens gravados anteriormente são carregados.
+func New(opts Options) (*Cache, error) {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+	if opts.MaxEntryBytes <= 0 || opts.MaxEntryBytes > opts.MaxBytes {
+		opts.MaxEntryBytes = min(int64(DefaultMaxEntryBytes), opts.MaxBytes)
+	}
+
+	c := &Cache{
+		options: opts,
+		entries: make(map[string]*entry),
+		byURL:   make(map[string][]string),
+		lru:     list.New(),
+	}
+
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("erro ao criar diretório do cache: %w", err)
+		}
+		if err := c.loadDisk(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Stats retorna os contadores do cache
+func (c *Cache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return Stats{Entries: len(c.entries), Bytes: c.bytes, Hits: c.hits, Misses: c.misses}
+}
+
+// PurgeDomain remove todos os itens do domínio e de seus subdomínios, como a expiração
+// total de cache da Gocache. Retorna o número de itens removidos.
+func (c *Cache) PurgeDomain(domain string) int {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	removed := 0
+	for _, e := range c.entries {
+		if e.Host == domain || strings.HasSuffix(e.Host, "."+domain) {
+			c.remove(e)
+			removed++
+		}
+	}
+	return removed
+}
+
+// PurgeURLs remove os itens das URLs informadas. Assim como na Gocache, "*" funciona como
+// wildcard (ex: https://exemplo.com/blog/*). O esquema é ignorado na comparação, já que a
+// borda pode repassar requisições HTTPS ao proxy como HTTP. Retorna o número de itens removidos.
+func (c *Cache) PurgeURLs(urls []string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	removed := 0
+	for _, e := range c.entries {
+		for _, pattern := range urls {
+			if matchPurgeURL(pattern, e.URL) {
+				c.remove(e)
+				removed++
+				break
+			}
+		}
+	}
+	return removed
+}
+
+// lookup retorna uma cópia da variante armazenada que corresponde à requisição. Os headers
+// da cópia nunca são alterados; touch substitui o mapa inteiro.
+func (c *Cache) lookup(publicURL string, req *http.Request) (*entry, []byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, key := range c.byURL[publicURL] {
+		e := c.entries[key]
+		if !e.matches(req) {
+			continue
+		}
+
+		body := e.Body
+		if c.options.Dir != "" {
+			stored, err := readDiskEntry(c.diskPath(e.Key))
+			if err != nil {
+				log.Printf("Erro ao ler item do cache em disco: %v", err)
+				c.remove(e)
+				return nil, nil, false
+			}
+			body = stored.Body
+		}
+		c.lru.MoveToFront(e.element)
+		snapshot := *e
+		return &snapshot, body, true
+	}
+	return nil, nil, false
+}
+
+// store guarda (ou substitui) uma variante
+func (c *Cache) store(e *entry) {
+	e.Key = variantKey(e.URL, e.VaryValues)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if old, ok := c.entries[e.Key]; ok {
+		c.remove(old)
+	}
+
+	if c.options.Dir != "" {
+		if err := writeDiskEntry(c.diskPath(e.Key), e); err != nil {
+			log.Printf("Erro ao gravar item do cache em disco: %v", err)
+			return
+		}
+		e.Body = nil
+	}
+	c.insert(e)
+}
+
+// touch substitui os headers de uma variante revalidada pela origem (304)
+func (c *Cache) touch(key string, header http.Header) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	current, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	current.Header = header
+	current.StoredAt = time.Now()
+	current.InitialAge = headerAge(header)
+
+	if c.options.Dir != "" {
+		stored, err := readDiskEntry(c.diskPath(key))
+		if err == nil {
+			current.Body = stored.Body
+			err = writeDiskEntry(c.diskPath(key), current)
+			current.Body = nil
+		}
+		if err != nil {
+			log.Printf("Erro ao atualizar item do cache em disco: %v", err)
+		}
+	}
+}
+
+func (c *Cache) recordHit(hit bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
+
+// insert adiciona a variante ao índice e descarta os itens menos usados se o limite for
+// ultrapassado. Deve ser chamada com c.mutex travado.
+func (c *Cache) insert(e *entry) {
+	c.entries[e.Key] = e
+	c.byURL[e.URL] = append(c.byURL[e.URL], e.Key)
+	e.element = c.lru.PushFront(e)
+	c.bytes += e.Size
+
+	for c.bytes > c.options.MaxBytes && c.lru.Len() > 1 {
+		c.remove(c.lru.Back().Value.(*entry))
+	}
+}
+
+// remove descarta a variante. Deve ser chamada com c.mutex travado.
+func (c *Cache) remove(e *entry) {
+	if e == nil {
+		return
+	}
+	if _, ok := c.entries[e.Key]; !ok {
+		return
+	}
+
+	delete(c.entries, e.Key)
+	c.lru.Remove(e.element)
+	c.bytes -= e.Size
+
+	keys := c.byURL[e.URL]
+	for i, key := range keys {
+		if key == e.Key {
+			keys = append(keys[:i], keys[i+1:]...)
+			break
+		}
+	}
+	if len(keys) == 0 {
+		delete(c.byURL, e.URL)
+	} else {
+		c.byURL[e.URL] = keys
+	}
+
+	if c.options.Dir != "" {
+		if err := os.Remove(c.diskPath(e.Key)); err != nil && !os.IsNotExist(err) {
+			log.Printf("Erro ao remover item do cache em disco: %v", err)
+		}
+	}
+}
+
+// matches compara os headers listados em Vary com os da requisição
+func (e *entry) matches(req *http.Request) bool {
+	for i, name := range e.VaryNames {
+		if strings.Join(req.Header.Values(name), ",") != e.VaryValues[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// age calcula a idade atual da resposta armazenada
+func (e *entry) age(now time.Time) time.Duration {
+	return e.InitialAge + now.Sub(e.StoredAt)
+}
+
+func variantKey(publicURL string, varyValues []string) string {
+	return publicURL + "\x00" + strings.Join(varyValues, "\x00")
+}
+
+func (c *Cache) diskPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.options.Dir, hex.EncodeToString(sum[:])+diskSuffix)
+}
+
+// loadDisk carrega os itens gravados em disco, do mais antigo para o mais recente
+func (c *Cache) loadDisk() error {
+	files, err := filepath.Glob(filepath.Join(c.options.Dir, "*"+diskSuffix))
+	if err != nil {
+		return fmt.Errorf("erro ao listar cache em disco: %w", err)
+	}
+
+	var loaded []*entry
+	for _, path := range files {
+		e, err := readDiskEntry(path)
+		if err != nil || c.diskPath(e.Key) != path {
+			log.Printf("Item inválido no cache em disco removido: %s", path)
+			os.Remove(path)
+			continue
+		}
+		e.Body = nil
+		loaded = append(loaded, e)
+	}
+
+	// Inserir do mais antigo para o mais recente mantém a ordem do LRU
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].StoredAt.Before(loaded[j].StoredAt) })
+	for _, e := range loaded {
+		c.insert(e)
+	}
+	return nil
+}
+
+func readDiskEntry(path string) (*entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var e entry
+	if err := gob.NewDecoder(f).Decode(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// writeDiskEntry grava o item em um arquivo temporário e o renomeia, evitando arquivos pela metade
+func writeDiskEntry(path string, e *entry) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(e); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// canonicalURL normaliza esquema e host de uma URL para comparação
+func canonicalURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// matchPurgeURL compara uma URL, sem o esquema, com um padrão de expiração
+func matchPurgeURL(pattern, value string) bool {
+	return MatchWildcard(withoutScheme(canonicalURL(pattern)), withoutScheme(value))
+}
+
+// MatchWildcard compara um texto com um padrão em que "*" corresponde a qualquer sequência
+// de caracteres, inclusive barras. Um padrão também cobre outro padrão mais específico, já
+// que o "*" de value é comparado como texto. É a mesma semântica dos wildcards de expiração
+// da Gocache, usada também pela fila de expiração da API.
+func MatchWildcard(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	for i := 1; i < len(parts); i++ {
+		if i == len(parts)-1 {
+			return strings.HasSuffix(value, parts[i])
+		}
+		index := strings.Index(value, parts[i])
+		if index < 0 {
+			return false
+		}
+		value = value[index+len(parts[i]):]
+	}
+	return value == ""
+}
+
+func withoutScheme(raw string) string {
+	if _, rest, ok := strings.Cut(raw, "://"); ok {
+		return rest
+	}
+	return raw
+}
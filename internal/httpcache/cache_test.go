@@ -0,0 +1,337 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// origin é um RoundTripper que responde com a função informada e conta as chamadas
+type origin struct {
+	calls   int
+	respond func(req *http.Request) *http.Response
+}
+
+func (o *origin) RoundTrip(req *http.Request) (*http.Response, error) {
+	o.calls++
+	resp := o.respond(req)
+	resp.Request = req
+	return resp, nil
+}
+
+func response(status int, body string, header ...string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+	for i := 0; i+1 < len(header); i += 2 {
+		resp.Header.Add(header[i], header[i+1])
+	}
+	return resp
+}
+
+func newTestTransport(t *testing.T, opts Options, respond func(req *http.Request) *http.Response) (*Transport, *origin) {
+	t.Helper()
+	cache, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := &origin{respond: respond}
+	return &Transport{Cache: cache, Base: o}, o
+}
+
+// get faz um GET pelo transport e retorna o X-Cache e o corpo da resposta
+func get(t *testing.T, transport *Transport, rawURL string, header ...string) (string, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.Header.Get("X-Cache"), string(body)
+}
+
+func TestFreshnessLifetime(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name       string
+		header     http.Header
+		defaultTTL time.Duration
+		want       time.Duration
+	}{
+		{"sem headers", http.Header{}, 0, 0},
+		{"TTL padrão", http.Header{}, time.Minute, time.Minute},
+		{"max-age", http.Header{"Cache-Control": {"public, max-age=60"}}, time.Hour, time.Minute},
+		{"s-maxage tem prioridade", http.Header{"Cache-Control": {"max-age=60, s-maxage=120"}}, 0, 2 * time.Minute},
+		{"no-cache", http.Header{"Cache-Control": {"no-cache, max-age=60"}}, time.Hour, 0},
+		{"max-age inválido", http.Header{"Cache-Control": {"max-age=abc"}}, time.Hour, 0},
+		{"Expires relativo a Date", http.Header{
+			"Date":    {now.Format(http.TimeFormat)},
+			"Expires": {now.Add(90 * time.Second).Format(http.TimeFormat)},
+		}, 0, 90 * time.Second},
+		{"Expires inválido", http.Header{"Expires": {"0"}}, time.Hour, 0},
+		{"Expires no passado", http.Header{"Expires": {now.Add(-time.Hour).Format(http.TimeFormat)}}, time.Hour, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := freshnessLifetime(tt.header, tt.defaultTTL); got != tt.want {
+				t.Errorf("freshnessLifetime() = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorable(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		reqHeader http.Header
+		status    int
+		header    http.Header
+		want      bool
+	}{
+		{"max-age", http.MethodGet, nil, 200, http.Header{"Cache-Control": {"max-age=60"}}, true},
+		{"só validadores", http.MethodGet, nil, 200, http.Header{"Etag": {`"v1"`}}, true},
+		{"sem validade nem validadores", http.MethodGet, nil, 200, http.Header{}, false},
+		{"POST", http.MethodPost, nil, 200, http.Header{"Cache-Control": {"max-age=60"}}, false},
+		{"status não armazenável", http.MethodGet, nil, 500, http.Header{"Cache-Control": {"max-age=60"}}, false},
+		{"404 armazenável", http.MethodGet, nil, 404, http.Header{"Cache-Control": {"max-age=60"}}, true},
+		{"private", http.MethodGet, nil, 200, http.Header{"Cache-Control": {"private, max-age=60"}}, false},
+		{"no-store na resposta", http.MethodGet, nil, 200, http.Header{"Cache-Control": {"no-store"}}, false},
+		{"no-store na requisição", http.MethodGet, http.Header{"Cache-Control": {"no-store"}}, 200, http.Header{"Cache-Control": {"max-age=60"}}, false},
+		{"Set-Cookie", http.MethodGet, nil, 200, http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"a=1"}}, false},
+		{"Vary *", http.MethodGet, nil, 200, http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}}, false},
+		{"Authorization sem public", http.MethodGet, http.Header{"Authorization": {"Bearer x"}}, 200, http.Header{"Cache-Control": {"max-age=60"}}, false},
+		{"Authorization com public", http.MethodGet, http.Header{"Authorization": {"Bearer x"}}, 200, http.Header{"Cache-Control": {"public, max-age=60"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "http://a.com/", nil)
+			if tt.reqHeader != nil {
+				req.Header = tt.reqHeader
+			}
+			resp := &http.Response{StatusCode: tt.status, Header: tt.header}
+			if got := storable(req, resp, 0); got != tt.want {
+				t.Errorf("storable() = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchWildcard(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"https://a.com/blog/*", "https://a.com/blog/post", true},
+		{"https://a.com/blog/*", "https://a.com/blog/2024/post", true},
+		{"https://a.com/blog/*", "https://a.com/blog/", true},
+		{"https://a.com/blog/*", "https://a.com/loja/x", false},
+		{"https://a.com/*.css", "https://a.com/static/site.css", true},
+		{"https://a.com/*.css", "https://a.com/static/site.js", false},
+		{"https://a.com/*/img/*", "https://a.com/x/img/logo.png", true},
+		{"https://a.com/*/img/*", "https://a.com/x/css/logo.png", false},
+		// Um padrão cobre outro mais específico
+		{"https://a.com/*", "https://a.com/blog/*", true},
+		{"https://a.com/blog/*", "https://a.com/*", false},
+		{"https://a.com/x", "https://a.com/x", true},
+		{"https://a.com/x", "https://a.com/xy", false},
+		// A comparação é textual: esquema e host não são normalizados
+		{"http://a.com/x", "https://a.com/x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.value, func(t *testing.T) {
+			if got := MatchWildcard(tt.pattern, tt.value); got != tt.want {
+				t.Errorf("MatchWildcard(%q, %q) = %v, esperado %v", tt.pattern, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPurgeURL(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"https://a.com/p", "https://a.com/p", true},
+		{"http://A.com/p", "https://a.com/p", true},
+		{"https://a.com", "https://a.com/", true},
+		{"https://a.com/p", "https://a.com/p2", false},
+		{"https://a.com/blog/*", "https://a.com/blog/post?x=1", true},
+		{"https://a.com/blog/*", "https://a.com/outro", false},
+		{"https://a.com/*.css", "https://a.com/css/site.css", true},
+		{"https://a.com/*.css", "https://a.com/site.css.map", false},
+		{"https://a.com/*/img/*", "https://a.com/x/img/y.png", true},
+		{"https://a.com/*/img/*", "https://a.com/x/foto/y.png", false},
+		{"https://*.a.com/*", "https://www.a.com/x", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.value, func(t *testing.T) {
+			if got := matchPurgeURL(tt.pattern, tt.value); got != tt.want {
+				t.Errorf("matchPurgeURL(%q, %q) = %v, esperado %v", tt.pattern, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransportFreshness(t *testing.T) {
+	version := 0
+	transport, o := newTestTransport(t, Options{}, func(req *http.Request) *http.Response {
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			return response(http.StatusNotModified, "", "Cache-Control", "max-age=0", "Etag", `"v1"`)
+		}
+		version++
+		return response(http.StatusOK, "conteúdo", "Cache-Control", "max-age=0", "Etag", `"v1"`)
+	})
+
+	steps := []struct {
+		status    string
+		wantCalls int
+	}{
+		{StatusMiss, 1},
+		{StatusRevalidated, 2},
+		{StatusRevalidated, 3},
+	}
+	for i, step := range steps {
+		status, body := get(t, transport, "http://a.com/p")
+		if status != step.status || body != "conteúdo" || o.calls != step.wantCalls {
+			t.Errorf("passo %d: X-Cache = %s, corpo = %q, chamadas = %d; esperado %s e %d chamadas", i, status, body, o.calls, step.status, step.wantCalls)
+		}
+	}
+	if version != 1 {
+		t.Errorf("conteúdo buscado %d vezes, esperado 1", version)
+	}
+
+	fresh, o := newTestTransport(t, Options{}, func(req *http.Request) *http.Response {
+		return response(http.StatusOK, "fresco", "Cache-Control", "max-age=60")
+	})
+	get(t, fresh, "http://a.com/p")
+	if status, _ := get(t, fresh, "http://a.com/p"); status != StatusHit || o.calls != 1 {
+		t.Errorf("X-Cache = %s, chamadas = %d; esperado HIT e 1", status, o.calls)
+	}
+	if status, _ := get(t, fresh, "http://a.com/p", "Cache-Control", "no-cache"); status != StatusMiss || o.calls != 2 {
+		t.Errorf("no-cache do visitante: X-Cache = %s, chamadas = %d; esperado MISS e 2", status, o.calls)
+	}
+}
+
+func TestTransportVary(t *testing.T) {
+	transport, o := newTestTransport(t, Options{}, func(req *http.Request) *http.Response {
+		return response(http.StatusOK, "idioma="+req.Header.Get("Accept-Language"), "Cache-Control", "max-age=60", "Vary", "accept-language")
+	})
+
+	tests := []struct {
+		language  string
+		want      string
+		status    string
+		wantCalls int
+	}{
+		{"pt-BR", "idioma=pt-BR", StatusMiss, 1},
+		{"en", "idioma=en", StatusMiss, 2},
+		{"pt-BR", "idioma=pt-BR", StatusHit, 2},
+		{"en", "idioma=en", StatusHit, 2},
+	}
+	for _, tt := range tests {
+		status, body := get(t, transport, "http://a.com/p", "Accept-Language", tt.language)
+		if status != tt.status || body != tt.want || o.calls != tt.wantCalls {
+			t.Errorf("%s: X-Cache = %s, corpo = %q, chamadas = %d; esperado %s, %q e %d", tt.language, status, body, o.calls, tt.status, tt.want, tt.wantCalls)
+		}
+	}
+	if stats := transport.Cache.Stats(); stats.Entries != 2 {
+		t.Errorf("variantes = %d, esperado 2", stats.Entries)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	transport, _ := newTestTransport(t, Options{MaxBytes: 30, MaxEntryBytes: 20}, func(req *http.Request) *http.Response {
+		body := strings.Repeat("x", 10)
+		if req.URL.Path == "/grande" {
+			body = strings.Repeat("x", 25)
+		}
+		return response(http.StatusOK, body, "Cache-Control", "max-age=60")
+	})
+
+	get(t, transport, "http://a.com/1")
+	get(t, transport, "http://a.com/2")
+	get(t, transport, "http://a.com/3")
+	get(t, transport, "http://a.com/1") // /1 passa a ser o mais recente
+	get(t, transport, "http://a.com/4") // descarta /2, o menos usado
+
+	if status, _ := get(t, transport, "http://a.com/1"); status != StatusHit {
+		t.Errorf("/1: X-Cache = %s, esperado HIT", status)
+	}
+	if status, _ := get(t, transport, "http://a.com/2"); status != StatusMiss {
+		t.Errorf("/2: X-Cache = %s, esperado MISS após o descarte", status)
+	}
+
+	// Respostas acima de MaxEntryBytes são entregues inteiras, mas não armazenadas
+	if _, body := get(t, transport, "http://a.com/grande"); len(body) != 25 {
+		t.Errorf("corpo = %d bytes, esperado 25", len(body))
+	}
+	if status, _ := get(t, transport, "http://a.com/grande"); status != StatusMiss {
+		t.Errorf("/grande: X-Cache = %s, esperado MISS", status)
+	}
+	if stats := transport.Cache.Stats(); stats.Bytes > 30 {
+		t.Errorf("bytes = %d, esperado no máximo 30", stats.Bytes)
+	}
+}
+
+func TestCachePurge(t *testing.T) {
+	urls := []string{
+		"https://a.com/",
+		"https://a.com/blog/1",
+		"https://a.com/blog/2?page=2",
+		"https://www.a.com/blog/1",
+		"https://b.com/blog/1",
+	}
+	tests := []struct {
+		name    string
+		purge   func(c *Cache) int
+		want    int
+		remains []string
+	}{
+		{"URL exata ignorando o esquema", func(c *Cache) int { return c.PurgeURLs([]string{"http://a.com/blog/1"}) }, 1,
+			[]string{"https://a.com/", "https://a.com/blog/2?page=2", "https://www.a.com/blog/1", "https://b.com/blog/1"}},
+		{"wildcard", func(c *Cache) int { return c.PurgeURLs([]string{"https://a.com/blog/*"}) }, 2,
+			[]string{"https://a.com/", "https://www.a.com/blog/1", "https://b.com/blog/1"}},
+		{"vários padrões", func(c *Cache) int { return c.PurgeURLs([]string{"https://*/blog/1", "https://a.com/"}) }, 4,
+			[]string{"https://a.com/blog/2?page=2"}},
+		{"domínio com subdomínios", func(c *Cache) int { return c.PurgeDomain("A.com.") }, 4,
+			[]string{"https://b.com/blog/1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, _ := newTestTransport(t, Options{Dir: t.TempDir()}, func(req *http.Request) *http.Response {
+				return response(http.StatusOK, "ok", "Cache-Control", "max-age=60")
+			})
+			for _, u := range urls {
+				get(t, transport, u)
+			}
+
+			if got := tt.purge(transport.Cache); got != tt.want {
+				t.Errorf("itens removidos = %d, esperado %d", got, tt.want)
+			}
+			for _, u := range tt.remains {
+				if status, _ := get(t, transport, u); status != StatusHit {
+					t.Errorf("%s: X-Cache = %s, esperado HIT", u, status)
+				}
+			}
+			if stats := transport.Cache.Stats(); stats.Entries != len(tt.remains) {
+				t.Errorf("itens = %d, esperado %d", stats.Entries, len(tt.remains))
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package httpcache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControl guarda as diretivas de um header Cache-Control
+type cacheControl map[string]string
+
+// parseCacheControl interpreta as diretivas do header, ex: "public, max-age=60"
+func parseCacheControl(header http.Header) cacheControl {
+	cc := cacheControl{}
+	for _, value := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, arg, _ := strings.Cut(part, "=")
+			cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(arg), `"`)
+		}
+	}
+	return cc
+}
+
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// seconds retorna o valor numérico de uma diretiva como max-age
+func (cc cacheControl) seconds(directive string) (time.Duration, bool) {
+	value, ok := cc[directive]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, true
+	}
+	return time.Duration(n) * time.Second, true
+}
+
+// cacheableStatus lista os status que podem ser armazenados (RFC 9110, seção 15.1)
+var cacheableStatus = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMovedPermanently:     true,
+	http.StatusNotFound:             true,
+	http.StatusGone:                 true,
+}
+
+// storable indica se a resposta pode ser guardada por um cache compartilhado
+func storable(req *http.Request, resp *http.Response, defaultTTL time.Duration) bool {
+	if req.Method != http.MethodGet || !cacheableStatus[resp.StatusCode] {
+		return false
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	respCC := parseCacheControl(resp.Header)
+	if reqCC.has("no-store") || respCC.has("no-store") || respCC.has("private") {
+		return false
+	}
+	if req.Header.Get("Authorization") != "" && !respCC.has("public") && !respCC.has("s-maxage") {
+		return false
+	}
+	// Respostas que definem cookies são específicas do visitante
+	if resp.Header.Get("Set-Cookie") != "" {
+		return false
+	}
+	for _, name := range varyNames(resp.Header) {
+		if name == "*" {
+			return false
+		}
+	}
+
+	return freshnessLifetime(resp.Header, defaultTTL) > 0 || hasValidators(resp.Header)
+}
+
+// freshnessLifetime calcula por quanto tempo a resposta é considerada fresca:
+// s-maxage, max-age, Expires e, por fim, o TTL padrão configurado
+func freshnessLifetime(header http.Header, defaultTTL time.Duration) time.Duration {
+	cc := parseCacheControl(header)
+	if cc.has("no-cache") {
+		return 0
+	}
+	if ttl, ok := cc.seconds("s-maxage"); ok {
+		return ttl
+	}
+	if ttl, ok := cc.seconds("max-age"); ok {
+		return ttl
+	}
+	if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			// Expires inválido (ex: "0") significa já expirado
+			return 0
+		}
+		date, err := http.ParseTime(header.Get("Date"))
+		if err != nil {
+			date = time.Now()
+		}
+		if ttl := expiresAt.Sub(date); ttl > 0 {
+			return ttl
+		}
+		return 0
+	}
+	return defaultTTL
+}
+
+func hasValidators(header http.Header) bool {
+	return header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+}
+
+// varyNames retorna os nomes de header listados em Vary, em forma canônica
+func varyNames(header http.Header) []string {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names
+}
+
+// headerAge lê o header Age recebido da origem
+func headerAge(header http.Header) time.Duration {
+	age, err := strconv.ParseInt(header.Get("Age"), 10, 64)
+	if err != nil || age < 0 {
+		return 0
+	}
+	return time.Duration(age) * time.Second
+}
+
+// revalidationHeaders são atualizados no item em cache quando a origem responde 304
+var revalidationHeaders = []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified", "Vary"}
+
+// refreshHeader retorna uma cópia dos headers armazenados com os valores atualizados pela
+// resposta 304 da origem
+func refreshHeader(stored, notModified http.Header) http.Header {
+	header := stored.Clone()
+	for _, name := range revalidationHeaders {
+		if values := notModified.Values(name); len(values) > 0 {
+			header[name] = values
+		}
+	}
+	return header
+}
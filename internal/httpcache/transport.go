@@ -0,0 +1,245 @@
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Valores do header X-Cache adicionado às respostas que passam pelo cache
+const (
+	StatusHit         = "HIT"
+	StatusMiss        = "MISS"
+	StatusRevalidated = "REVALIDATED"
+	StatusBypass      = "BYPASS"
+)
+
+type keyContextKey struct{}
+
+// WithKey associa à requisição a URL pública usada como chave do cache. O proxy a define
+// com o host e o caminho recebidos do visitante, já que a requisição enviada à origem
+// aponta para o destino do mapeamento.
+func WithKey(ctx context.Context, publicURL string) context.Context {
+	return context.WithValue(ctx, keyContextKey{}, canonicalURL(publicURL))
+}
+
+func keyFromRequest(req *http.Request) string {
+	if key, ok := req.Context().Value(keyContextKey{}).(string); ok {
+		return key
+	}
+	return canonicalURL(req.URL.String())
+}
+
+// Transport é um http.RoundTripper que responde a partir do cache quando possível e
+// repassa as demais requisições a Base
+type Transport struct {
+	Cache *Cache
+	// Base é o transporte usado para falar com a origem; http.DefaultTransport se nil
+	Base http.RoundTripper
+}
+
+// NewTransport cria um Transport sobre http.DefaultTransport
+func NewTransport(cache *Cache) *Transport {
+	return &Transport{Cache: cache}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := keyFromRequest(req)
+
+	if req.Method != http.MethodGet {
+		resp, err := t.base().RoundTrip(req)
+		// Métodos que alteram o recurso invalidam a cópia armazenada
+		if err == nil && req.Method != http.MethodHead && req.Method != http.MethodOptions && resp.StatusCode < 400 {
+			t.Cache.PurgeURLs([]string{key})
+		}
+		return resp, err
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	if reqCC.has("no-store") {
+		resp, err := t.base().RoundTrip(req)
+		if err == nil {
+			resp.Header.Set("X-Cache", StatusBypass)
+		}
+		return resp, err
+	}
+
+	cached, body, ok := t.Cache.lookup(key, req)
+	if !ok {
+		t.Cache.recordHit(false)
+		return t.fetch(req, key)
+	}
+
+	now := time.Now()
+	age := cached.age(now)
+	if isFresh(cached, age, reqCC, t.Cache.options.DefaultTTL) {
+		t.Cache.recordHit(true)
+		return cachedResponse(req, cached, body, age, StatusHit), nil
+	}
+
+	if !hasValidators(cached.Header) {
+		t.Cache.recordHit(false)
+		return t.fetch(req, key)
+	}
+
+	// Resposta expirada com validadores: pergunta à origem se ela ainda é válida
+	conditional := req.Clone(req.Context())
+	if etag := cached.Header.Get("ETag"); etag != "" {
+		conditional.Header.Set("If-None-Match", etag)
+	} else {
+		conditional.Header.Del("If-None-Match")
+	}
+	if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+		conditional.Header.Set("If-Modified-Since", lastModified)
+	} else {
+		conditional.Header.Del("If-Modified-Since")
+	}
+
+	resp, err := t.base().RoundTrip(conditional)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusNotModified {
+		t.Cache.recordHit(false)
+		return t.store(req, key, resp)
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	cached.Header = refreshHeader(cached.Header, resp.Header)
+	t.Cache.touch(cached.Key, cached.Header)
+	t.Cache.recordHit(true)
+	return cachedResponse(req, cached, body, headerAge(resp.Header), StatusRevalidated), nil
+}
+
+// fetch busca a resposta na origem e a armazena se permitido
+func (t *Transport) fetch(req *http.Request, key string) (*http.Response, error) {
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return t.store(req, key, resp)
+}
+
+// store armazena a resposta da origem, se ela puder ser guardada e couber no limite por item
+func (t *Transport) store(req *http.Request, key string, resp *http.Response) (*http.Response, error) {
+	if !storable(req, resp, t.Cache.options.DefaultTTL) {
+		resp.Header.Set("X-Cache", StatusMiss)
+		return resp, nil
+	}
+
+	limit := t.Cache.options.MaxEntryBytes
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		// Grande demais para o cache: entrega o que já foi lido seguido do restante
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		resp.Header.Set("X-Cache", StatusMiss)
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	names := varyNames(resp.Header)
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = strings.Join(req.Header.Values(name), ",")
+	}
+
+	host := ""
+	if u, err := url.Parse(key); err == nil {
+		host = strings.ToLower(u.Hostname())
+	}
+
+	e := &entry{
+		URL:        key,
+		Host:       host,
+		Status:     resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		StoredAt:   time.Now(),
+		InitialAge: headerAge(resp.Header),
+		VaryNames:  names,
+		VaryValues: values,
+		Size:       int64(len(body)),
+		Body:       body,
+	}
+	t.Cache.store(e)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("X-Cache", StatusMiss)
+	return resp, nil
+}
+
+// isFresh indica se o item pode ser entregue sem consultar a origem, considerando também
+// as diretivas enviadas pelo visitante (no-cache, max-age)
+func isFresh(e *entry, age time.Duration, reqCC cacheControl, defaultTTL time.Duration) bool {
+	if reqCC.has("no-cache") {
+		return false
+	}
+	if maxAge, ok := reqCC.seconds("max-age"); ok && age > maxAge {
+		return false
+	}
+	return age < freshnessLifetime(e.Header, defaultTTL)
+}
+
+// cachedResponse monta a resposta a partir do item armazenado. Se o visitante enviou um
+// If-None-Match que corresponde ao ETag, responde 304 sem corpo.
+func cachedResponse(req *http.Request, e *entry, body []byte, age time.Duration, status string) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	header.Set("X-Cache", status)
+
+	statusCode := e.Status
+	if etag := header.Get("ETag"); etag != "" && etagMatches(req.Header.Get("If-None-Match"), etag) {
+		statusCode = http.StatusNotModified
+		body = nil
+		header.Del("Content-Length")
+	}
+
+	return &http.Response{
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// etagMatches compara um If-None-Match com o ETag usando a comparação fraca
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
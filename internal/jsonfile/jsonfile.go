@@ -0,0 +1,65 @@
+// Package jsonfile lê e grava os arquivos JSON de estado (mapeamentos, jobs de expiração,
+// onboardings, índice de tags e configurações da expiração automática). A gravação é
+// atômica: o conteúdo vai para um arquivo temporário no mesmo diretório, que substitui o
+// original com rename, então leitores nunca veem um arquivo pela metade.
+package jsonfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Load lê o JSON de path em v, criando o diretório do arquivo se necessário. Um arquivo
+// inexistente ou vazio não é erro e deixa v inalterado.
+func Load(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de %s: %w", path, err)
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("arquivo %s inválido: %w", path, err)
+	}
+	return nil
+}
+
+// Save grava v em path como JSON indentado
+func Save(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar %s: %w", path, err)
+	}
+	return writeAtomic(path, append(data, '\n'))
+}
+
+// writeAtomic grava data em um arquivo temporário no mesmo diretório e o renomeia sobre path
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", path, err)
+	}
+	return nil
+}
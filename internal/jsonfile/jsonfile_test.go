@@ -0,0 +1,62 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "estado", "itens.json")
+
+	// Arquivo inexistente: v fica inalterado e o diretório é criado
+	items := []string{"padrão"}
+	if err := Load(path, &items); err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0] != "padrão" {
+		t.Errorf("Load() de arquivo inexistente alterou v: %v", items)
+	}
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		t.Errorf("diretório não criado: %v", err)
+	}
+
+	if err := Save(path, []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	var loaded []string
+	if err := Load(path, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[1] != "b" {
+		t.Errorf("Load() = %v, esperado [a b]", loaded)
+	}
+	if matches, _ := filepath.Glob(path + ".tmp-*"); len(matches) != 0 {
+		t.Errorf("arquivos temporários restantes: %v", matches)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"vazio", "", false},
+		{"inválido", "{", true},
+		{"tipo errado", `{"a": 1}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "itens.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var items []string
+			if err := Load(path, &items); (err != nil) != tt.wantErr {
+				t.Errorf("Load() erro = %v, esperado erro = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// BackupFormatVersion é a versão atual do formato do arquivo de backup
+const BackupFormatVersion = 1
+
+// Políticas para itens do backup que já existem com outro conteúdo na conta de destino
+const (
+	// BackupConflictSkip mantém o que existe no destino
+	BackupConflictSkip = "skip"
+	// BackupConflictOverwrite substitui pelo conteúdo do backup
+	BackupConflictOverwrite = "overwrite"
+	// BackupConflictFail cancela a restauração inteira, sem alterar nada
+	BackupConflictFail = "fail"
+)
+
+// Ações da restauração de um item
+const (
+	BackupActionCreate    = "create"
+	BackupActionUpdate    = "update"
+	BackupActionUnchanged = "unchanged"
+	BackupActionSkip      = "skip"
+)
+
+// BackupArchive é o arquivo de backup das configurações de uma conta GoCache
+type BackupArchive struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Source    string         `json:"source,omitempty"` // endereço da API de onde o backup foi feito
+	Domains   []DomainBackup `json:"domains"`
+}
+
+// DomainBackup guarda as configurações, os registros DNS, os redirecionamentos e as Smart
+// Rules de um domínio
+type DomainBackup struct {
+	Name      string             `json:"name"`
+	Settings  DomainSettings     `json:"settings"`
+	DNS       []DNSRecord        `json:"dns"`
+	Redirects []RedirectRule     `json:"redirects"`
+	Rules     []SmartRuleRewrite `json:"rules"`
+}
+
+// BackupRestoreRequest representa a requisição para restaurar um backup
+type BackupRestoreRequest struct {
+	Archive BackupArchive `json:"archive"`
+	// Domains limita a restauração a esses domínios; vazio restaura todos
+	Domains []string `json:"domains,omitempty"`
+	// Conflict é a política para itens que já existem com outro conteúdo (padrão: skip)
+	Conflict string `json:"conflict,omitempty" binding:"omitempty,oneof=skip overwrite fail"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// BackupRestoreItem é o resultado (ou a simulação) da restauração de um item
+type BackupRestoreItem struct {
+	Domain   string `json:"domain"`
+	Type     string `json:"type"` // domain, dns, redirect ou rule
+	Key      string `json:"key"`
+	Action   string `json:"action"`
+	Conflict bool   `json:"conflict,omitempty"`
+	Applied  bool   `json:"applied"`
+	Error    string `json:"error,omitempty"`
+}
+
+// BackupRestoreSummary totaliza os itens da restauração por ação
+type BackupRestoreSummary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Unchanged int `json:"unchanged"`
+	Skip      int `json:"skip"`
+	Conflicts int `json:"conflicts"`
+	Failed    int `json:"failed"`
+}
+
+// BackupRestoreResponse representa o resultado da restauração
+type BackupRestoreResponse struct {
+	Status   bool                 `json:"status"`
+	DryRun   bool                 `json:"dry_run"`
+	Conflict string               `json:"conflict"`
+	Error    string               `json:"error,omitempty"`
+	Summary  BackupRestoreSummary `json:"summary"`
+	Items    []BackupRestoreItem  `json:"items"`
+}
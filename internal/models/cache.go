@@ -1,27 +1,36 @@
 package models
 
+import "time"
+
 // CachePurgeRequest representa a requisição para expirar cache de URLs
 type CachePurgeRequest struct {
 	Domain string   `json:"domain" binding:"required"`
 	URLs   []string `json:"urls" binding:"required"`
 }
 
-// CachePurgeByPrefixRequest representa a requisição para expirar cache por prefixo
+// CachePurgeByPrefixRequest representa a requisição para expirar cache por prefixo. Cada
+// prefixo pode ser um path ("/blog/") ou uma URL do domínio ("https://www.example.com/blog/").
 type CachePurgeByPrefixRequest struct {
-	Domain string `json:"domain" binding:"required"`
-	Prefix string `json:"prefix" binding:"required"`
+	Domain   string   `json:"domain" binding:"required"`
+	Prefix   string   `json:"prefix,omitempty"`
+	Prefixes []string `json:"prefixes,omitempty"`
 }
 
 // CacheInvalidationResponse representa a resposta da API para invalidação de cache
 type CacheInvalidationResponse struct {
 	Status  bool   `json:"status"`
 	Message string `json:"message"`
+	// EdgeError informa a falha ao expirar o cache local do proxy, quando houver
+	EdgeError string `json:"edge_error,omitempty"`
+	// JobID identifica a expiração em GET /cache/jobs/{id}
+	JobID string `json:"job_id,omitempty"`
 }
 
 // CacheStatusResponse representa a resposta da API para status de cache
 type CacheStatusResponse struct {
-	Status bool        `json:"status"`
-	Data   CacheStatus `json:"data"`
+	Status bool           `json:"status"`
+	Data   CacheStatus    `json:"data"`
+	Job    *CachePurgeJob `json:"job,omitempty"`
 }
 
 // CacheStatus contém informações sobre o status de cache
@@ -30,3 +39,189 @@ type CacheStatus struct {
 	Processed int `json:"processed"`
 	Pending   int `json:"pending"`
 }
+
+// CacheJobStatus é a situação de um job de expiração de cache
+type CacheJobStatus string
+
+const (
+	CacheJobPending   CacheJobStatus = "pending"
+	CacheJobRunning   CacheJobStatus = "running"
+	CacheJobCompleted CacheJobStatus = "completed"
+	CacheJobFailed    CacheJobStatus = "failed"
+)
+
+// Tipos de job de expiração de cache
+const (
+	CacheJobTypeAll  = "purge-all"
+	CacheJobTypeURLs = "purge-urls"
+)
+
+// CachePurgeJob registra uma expiração de cache: quem pediu, o que foi expirado, o
+// progresso e o histórico de cada etapa
+type CachePurgeJob struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Domain      string          `json:"domain"`
+	URLs        []string        `json:"urls,omitempty"`
+	RequestedBy string          `json:"requested_by,omitempty"`
+	Status      CacheJobStatus  `json:"status"`
+	Progress    CacheStatus     `json:"progress"`
+	Message     string          `json:"message,omitempty"`
+	EdgeError   string          `json:"edge_error,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	History     []CacheJobEvent `json:"history"`
+}
+
+// CacheJobEvent é uma etapa do histórico de um job de expiração
+type CacheJobEvent struct {
+	At      time.Time      `json:"at"`
+	Status  CacheJobStatus `json:"status"`
+	Message string         `json:"message"`
+}
+
+// CacheJobsListResponse representa a resposta da API para listagem de jobs de expiração
+type CacheJobsListResponse struct {
+	Status bool            `json:"status"`
+	Jobs   []CachePurgeJob `json:"jobs"`
+	Total  int             `json:"total"`
+}
+
+// PurgeQueueResponse representa a resposta ao enfileirar URLs para expiração
+type PurgeQueueResponse struct {
+	Status bool   `json:"status"`
+	Domain string `json:"domain"`
+	// Accepted é o número de URLs novas na fila
+	Accepted int `json:"accepted"`
+	// Duplicates são as URLs que já estavam na fila
+	Duplicates int `json:"duplicates"`
+	// Covered são as URLs já cobertas por um wildcard na fila, somadas às que saíram da
+	// fila por serem cobertas por um wildcard novo
+	Covered int `json:"covered"`
+	// Pending é o total de URLs do domínio aguardando envio
+	Pending int `json:"pending"`
+}
+
+// PendingPurge resume as URLs de um domínio aguardando envio
+type PendingPurge struct {
+	Domain string    `json:"domain"`
+	URLs   []string  `json:"urls"`
+	Since  time.Time `json:"since"`
+}
+
+// PurgeBatchResult é o resultado do envio de um lote da fila de expiração
+type PurgeBatchResult struct {
+	Domain string   `json:"domain"`
+	URLs   []string `json:"urls"`
+	// Reason indica o que disparou o envio: debounce, size ou manual
+	Reason    string    `json:"reason"`
+	Status    bool      `json:"status"`
+	Message   string    `json:"message,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	JobID     string    `json:"job_id,omitempty"`
+	FlushedAt time.Time `json:"flushed_at"`
+}
+
+// PurgeQueueStatusResponse representa o estado da fila de expiração e os últimos lotes enviados
+type PurgeQueueStatusResponse struct {
+	Status  bool               `json:"status"`
+	Pending []PendingPurge     `json:"pending"`
+	Batches []PurgeBatchResult `json:"batches"`
+}
+
+// PurgeQueueFlushResponse representa os lotes enviados por um esvaziamento manual da fila
+type PurgeQueueFlushResponse struct {
+	Status  bool               `json:"status"`
+	Batches []PurgeBatchResult `json:"batches"`
+}
+
+// CacheTagEntry associa uma URL às suas cache-tags (ex: product-123)
+type CacheTagEntry struct {
+	URL  string   `json:"url" binding:"required"`
+	Tags []string `json:"tags"`
+}
+
+// CacheTagRegisterRequest representa a requisição para registrar as tags de URLs. As tags
+// enviadas substituem as anteriores da URL; uma lista vazia remove a URL do índice.
+type CacheTagRegisterRequest struct {
+	Entries []CacheTagEntry `json:"entries" binding:"required,dive"`
+}
+
+// CacheTagRegisterResponse representa a resposta ao registrar tags
+type CacheTagRegisterResponse struct {
+	Status  bool `json:"status"`
+	Updated int  `json:"updated"`
+}
+
+// CacheTagLookupResponse representa as URLs associadas às tags consultadas
+type CacheTagLookupResponse struct {
+	Status bool     `json:"status"`
+	Tags   []string `json:"tags"`
+	URLs   []string `json:"urls"`
+}
+
+// CachePurgeTagsRequest representa a requisição para expirar as URLs associadas a tags
+type CachePurgeTagsRequest struct {
+	Domain string   `json:"domain" binding:"required"`
+	Tags   []string `json:"tags" binding:"required,min=1"`
+}
+
+// CachePurgeTagsResponse representa o resultado da expiração por tags
+type CachePurgeTagsResponse struct {
+	Status  bool     `json:"status"`
+	Message string   `json:"message,omitempty"`
+	Tags    []string `json:"tags"`
+	// URLs são as URLs expiradas, nos esquemas http e https
+	URLs []string `json:"urls"`
+	// JobIDs são os jobs de expiração criados, um por lote de URLs
+	JobIDs    []string `json:"job_ids,omitempty"`
+	EdgeError string   `json:"edge_error,omitempty"`
+}
+
+// CachePurgeHookConfig configura a expiração automática de cache de um domínio após
+// alterações de regras, DNS e mapeamentos. O domínio "*" vale para os domínios sem
+// configuração própria.
+type CachePurgeHookConfig struct {
+	Domain  string `json:"domain"`
+	Enabled bool   `json:"enabled"`
+	// Events limita os eventos que disparam a expiração (ex: rule.updated); vazio aceita todos
+	Events []string `json:"events,omitempty"`
+	// Patterns são os modelos das URLs expiradas, com {host}, {domain} e {path}; vazio usa
+	// http://{host}{path} e https://{host}{path}
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// CachePurgeHookConfigRequest representa a requisição para configurar a expiração automática
+type CachePurgeHookConfigRequest struct {
+	Enabled  *bool    `json:"enabled" binding:"required"`
+	Events   []string `json:"events,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// CachePurgeHookConfigResponse representa a resposta com a configuração de um domínio
+type CachePurgeHookConfigResponse struct {
+	Status bool                 `json:"status"`
+	Config CachePurgeHookConfig `json:"config"`
+}
+
+// CachePurgeHookResult registra as URLs colocadas na fila de expiração por um evento, ou o
+// motivo de elas não terem sido. Os lotes enviados ficam em GET /cache/purge-queue.
+type CachePurgeHookResult struct {
+	Event    string    `json:"event"`
+	Domain   string    `json:"domain"`
+	Host     string    `json:"host,omitempty"`
+	ObjectID string    `json:"object_id,omitempty"`
+	URLs     []string  `json:"urls,omitempty"`
+	Skipped  string    `json:"skipped,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// CachePurgeHooksResponse lista as configurações e as últimas expirações automáticas
+type CachePurgeHooksResponse struct {
+	Status  bool                   `json:"status"`
+	Configs []CachePurgeHookConfig `json:"configs"`
+	Recent  []CachePurgeHookResult `json:"recent"`
+}
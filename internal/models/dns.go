@@ -1,17 +1,20 @@
 package models
 
+// DNSRecord representa um registro DNS retornado pela Gocache
+type DNSRecord struct {
+	RecordID DNSRecordID   `json:"record_id,omitempty"`
+	Name     string        `json:"name"`
+	Type     DNSRecordType `json:"type"`
+	Content  string        `json:"content"`
+	TTL      FlexInt       `json:"ttl"`
+	Cloud    FlexInt       `json:"cloud"`
+}
+
 // DNSListResponse representa a resposta da API para listagem de domínios
 type DNSListResponse struct {
 	StatusCode int `json:"status_code"`
 	Response   struct {
-		Records []struct {
-			Name     string `json:"name"`
-			Content  string `json:"content"`
-			Type     string `json:"type"`
-			TTL      string `json:"ttl"`
-			Cloud    string `json:"cloud"`
-			RecordID string `json:"record_id"`
-		} `json:"records"`
+		Records []DNSRecord `json:"records"`
 	} `json:"response"`
 }
 
@@ -29,35 +32,29 @@ type DNSEntry struct {
 
 // DNSCreateRequest representa a requisição para criar um novo domínio
 type DNSCreateRequest struct {
-	Name    string `json:"name" binding:"required"`
-	Type    string `json:"type" binding:"required"`
-	Content string `json:"content" binding:"required"`
-	TTL     int    `json:"ttl" binding:"required"`
-	Cloud   int    `json:"cloud" binding:"required"`
-	Domain  string `json:"-"` // Campo para armazenar o domínio, não será serializado para JSON
+	Name    string        `json:"name" form:"name" binding:"required,dns_name"`
+	Type    DNSRecordType `json:"type" form:"type" binding:"required,dns_type"`
+	Content string        `json:"content" form:"content" binding:"required"`
+	TTL     FlexInt       `json:"ttl" form:"ttl" binding:"required,min=1"`
+	Cloud   FlexInt       `json:"cloud" form:"cloud" binding:"oneof=0 1"`
+	Domain  string        `json:"-" form:"-"` // Campo para armazenar o domínio, não será serializado para JSON
 }
 
 // DNSUpdateRequest representa a requisição para atualizar um domínio existente
 type DNSUpdateRequest struct {
-	Name    string `json:"name" binding:"required"`
-	Type    string `json:"type" binding:"required"`
-	Content string `json:"content" binding:"required"`
-	TTL     int    `json:"ttl" binding:"required"`
-	Cloud   int    `json:"cloud" binding:"required"`
+	Name    string        `json:"name" form:"name" binding:"required,dns_name"`
+	Type    DNSRecordType `json:"type" form:"type" binding:"required,dns_type"`
+	Content string        `json:"content" form:"content" binding:"required"`
+	TTL     FlexInt       `json:"ttl" form:"ttl" binding:"required,min=1"`
+	Cloud   FlexInt       `json:"cloud" form:"cloud" binding:"oneof=0 1"`
+	Domain  string        `json:"-" form:"-"` // Zona do registro, usada no evento de alteração; não é enviada à Gocache
 }
 
 // DNSCreateResponse representa a resposta da API para criação de domínio
 type DNSCreateResponse struct {
 	StatusCode int `json:"status_code"`
 	Response   struct {
-		Records []struct {
-			Name     string      `json:"name"`
-			Content  string      `json:"content"`
-			Type     string      `json:"type"`
-			TTL      string      `json:"ttl"`
-			Cloud    string      `json:"cloud"`
-			RecordID interface{} `json:"record_id"` // Pode ser string ou número
-		} `json:"records"`
+		Records []DNSRecord `json:"records"`
 	} `json:"response"`
 }
 
@@ -65,14 +62,7 @@ type DNSCreateResponse struct {
 type DNSUpdateResponse struct {
 	StatusCode int `json:"status_code"`
 	Response   struct {
-		Records []struct {
-			Name     string      `json:"name"`
-			Content  string      `json:"content"`
-			Type     string      `json:"type"`
-			TTL      string      `json:"ttl"`
-			Cloud    string      `json:"cloud"`
-			RecordID interface{} `json:"record_id"` // Pode ser string ou número
-		} `json:"records"`
+		Records []DNSRecord `json:"records"`
 	} `json:"response"`
 }
 
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// Ações possíveis de uma alteração em registros DNS
+const (
+	DNSChangeCreate    = "create"
+	DNSChangeUpdate    = "update"
+	DNSChangeDelete    = "delete"
+	DNSChangeUnchanged = "unchanged"
+)
+
+// DNSRecordChange descreve a diferença entre um registro atual e o registro desejado
+type DNSRecordChange struct {
+	Action  string     `json:"action"`
+	Current *DNSRecord `json:"current,omitempty"`
+	Desired *DNSRecord `json:"desired,omitempty"`
+	Line    int        `json:"line,omitempty"`
+	Applied bool       `json:"applied"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// DNSChangeSummary totaliza as alterações de uma importação ou sincronização
+type DNSChangeSummary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Delete    int `json:"delete"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+}
+
+// DNSZoneSkipped descreve uma entrada do arquivo de zona ignorada na importação
+type DNSZoneSkipped struct {
+	Line   int    `json:"line"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// DNSZoneImportResponse representa o resultado (ou a simulação) de uma importação de zona
+type DNSZoneImportResponse struct {
+	Domain  string            `json:"domain"`
+	DryRun  bool              `json:"dry_run"`
+	Summary DNSChangeSummary  `json:"summary"`
+	Changes []DNSRecordChange `json:"changes"`
+	Skipped []DNSZoneSkipped  `json:"skipped,omitempty"`
+}
+
+// DNSSyncRecord é um registro do estado desejado enviado para sincronização
+type DNSSyncRecord struct {
+	Name    string        `json:"name" yaml:"name" binding:"required,dns_name"`
+	Type    DNSRecordType `json:"type" yaml:"type" binding:"required,dns_type"`
+	Content string        `json:"content" yaml:"content" binding:"required"`
+	TTL     FlexInt       `json:"ttl" yaml:"ttl" binding:"required,min=1"`
+	Cloud   FlexInt       `json:"cloud" yaml:"cloud" binding:"oneof=0 1"`
+}
+
+// DNSSyncRequest representa o conjunto completo de registros desejados para um domínio.
+// Registros existentes que não estiverem na lista serão excluídos.
+type DNSSyncRequest struct {
+	Records []DNSSyncRecord `json:"records" yaml:"records" binding:"dive"`
+}
+
+// DNSSyncPlan é o plano de alterações calculado a partir do estado desejado
+type DNSSyncPlan struct {
+	ID        string            `json:"id"`
+	Domain    string            `json:"domain"`
+	CreatedAt time.Time         `json:"created_at"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	Summary   DNSChangeSummary  `json:"summary"`
+	Changes   []DNSRecordChange `json:"changes"`
+}
+
+// DNSSyncApplyRequest representa a requisição de execução de um plano
+type DNSSyncApplyRequest struct {
+	PlanID      string `json:"plan_id" binding:"required"`
+	Concurrency int    `json:"concurrency" binding:"omitempty,min=1,max=16"`
+}
+
+// DNSSyncApplyResponse traz o resultado de cada alteração de um plano executado
+type DNSSyncApplyResponse struct {
+	PlanID  string            `json:"plan_id"`
+	Domain  string            `json:"domain"`
+	Summary DNSChangeSummary  `json:"summary"`
+	Changes []DNSRecordChange `json:"changes"`
+}
@@ -0,0 +1,289 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DNSRecordType representa os tipos de registro DNS suportados pela Gocache
+type DNSRecordType string
+
+// Tipos de registro DNS suportados
+const (
+	DNSTypeA     DNSRecordType = "A"
+	DNSTypeAAAA  DNSRecordType = "AAAA"
+	DNSTypeCNAME DNSRecordType = "CNAME"
+	DNSTypeMX    DNSRecordType = "MX"
+	DNSTypeTXT   DNSRecordType = "TXT"
+	DNSTypeNS    DNSRecordType = "NS"
+	DNSTypeCAA   DNSRecordType = "CAA"
+	DNSTypeSRV   DNSRecordType = "SRV"
+)
+
+// DNSRecordTypes lista todos os tipos de registro suportados
+var DNSRecordTypes = []DNSRecordType{
+	DNSTypeA, DNSTypeAAAA, DNSTypeCNAME, DNSTypeMX, DNSTypeTXT, DNSTypeNS, DNSTypeCAA, DNSTypeSRV,
+}
+
+// Normalize retorna o tipo em letras maiúsculas
+func (t DNSRecordType) Normalize() DNSRecordType {
+	return DNSRecordType(strings.ToUpper(strings.TrimSpace(string(t))))
+}
+
+// Valid indica se o tipo é suportado, sem diferenciar maiúsculas de minúsculas
+func (t DNSRecordType) Valid() bool {
+	normalized := t.Normalize()
+	for _, known := range DNSRecordTypes {
+		if normalized == known {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON normaliza o tipo para letras maiúsculas
+func (t *DNSRecordType) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*t = DNSRecordType(value).Normalize()
+	return nil
+}
+
+// UnmarshalYAML normaliza o tipo para letras maiúsculas
+func (t *DNSRecordType) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	*t = DNSRecordType(value).Normalize()
+	return nil
+}
+
+// DNSRecordID é o identificador de um registro DNS. A Gocache retorna o ID ora como
+// string, ora como número, então ambos os formatos são aceitos.
+type DNSRecordID string
+
+// UnmarshalJSON aceita IDs em formato string ou numérico
+func (id *DNSRecordID) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalFlexString(data)
+	if err != nil {
+		return fmt.Errorf("record_id inválido: %w", err)
+	}
+	*id = DNSRecordID(value)
+	return nil
+}
+
+// Int converte o ID para inteiro, formato usado nas rotas de atualização e exclusão
+func (id DNSRecordID) Int() (int, error) {
+	return strconv.Atoi(string(id))
+}
+
+// FlexInt é um inteiro que aceita, no JSON, números, strings numéricas e booleanos
+type FlexInt int
+
+// UnmarshalJSON aceita 300, "300", true ou "true"
+func (n *FlexInt) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalFlexString(data)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(value) {
+	case "", "null":
+		*n = 0
+		return nil
+	case "true":
+		*n = 1
+		return nil
+	case "false":
+		*n = 0
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("valor inteiro inválido %q", value)
+	}
+	*n = FlexInt(parsed)
+	return nil
+}
+
+// UnmarshalYAML aceita os mesmos formatos de UnmarshalJSON
+func (n *FlexInt) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value interface{}
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return n.UnmarshalJSON(data)
+}
+
+// unmarshalFlexString lê um valor JSON escalar (string, número ou booleano) como string
+func unmarshalFlexString(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		err := json.Unmarshal(data, &value)
+		return value, err
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("tipo JSON não suportado: %s", data)
+	}
+}
+
+// ValidateDNSName valida o nome de um registro: "@", um hostname ou um wildcard ("*.exemplo")
+func ValidateDNSName(name string) error {
+	if name == "@" || name == "*" {
+		return nil
+	}
+	if !isHostname(strings.TrimPrefix(name, "*.")) {
+		return fmt.Errorf("nome de registro inválido: %q", name)
+	}
+	return nil
+}
+
+// ValidateDNSContent valida o conteúdo de um registro de acordo com seu tipo
+func ValidateDNSContent(recordType DNSRecordType, content string) error {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return fmt.Errorf("conteúdo do registro não pode ser vazio")
+	}
+
+	switch recordType.Normalize() {
+	case DNSTypeA:
+		if ip := net.ParseIP(content); ip == nil || ip.To4() == nil || strings.Contains(content, ":") {
+			return fmt.Errorf("registro A requer um endereço IPv4, recebido %q", content)
+		}
+	case DNSTypeAAAA:
+		if ip := net.ParseIP(content); ip == nil || !strings.Contains(content, ":") {
+			return fmt.Errorf("registro AAAA requer um endereço IPv6, recebido %q", content)
+		}
+	case DNSTypeCNAME, DNSTypeNS:
+		if net.ParseIP(content) != nil {
+			return fmt.Errorf("registro %s deve apontar para um hostname, não para um IP (%q)", recordType.Normalize(), content)
+		}
+		if !isHostname(content) {
+			return fmt.Errorf("registro %s requer um hostname válido, recebido %q", recordType.Normalize(), content)
+		}
+	case DNSTypeMX:
+		return validateMX(content)
+	case DNSTypeTXT:
+		// TXT aceita texto livre; apenas o tamanho total é limitado
+		if len(content) > 4096 {
+			return fmt.Errorf("registro TXT excede 4096 caracteres")
+		}
+	case DNSTypeCAA:
+		return validateCAA(content)
+	case DNSTypeSRV:
+		return validateSRV(content)
+	default:
+		return fmt.Errorf("tipo de registro não suportado: %q", recordType)
+	}
+	return nil
+}
+
+// validateMX aceita "mail.exemplo.com" ou "10 mail.exemplo.com"
+func validateMX(content string) error {
+	fields := strings.Fields(content)
+	host := fields[0]
+	if len(fields) == 2 {
+		if _, err := strconv.ParseUint(fields[0], 10, 16); err != nil {
+			return fmt.Errorf("prioridade MX inválida: %q", fields[0])
+		}
+		host = fields[1]
+	} else if len(fields) > 2 {
+		return fmt.Errorf("registro MX deve ter o formato \"[prioridade] hostname\", recebido %q", content)
+	}
+
+	if net.ParseIP(host) != nil || !isHostname(host) {
+		return fmt.Errorf("registro MX requer um hostname válido, recebido %q", host)
+	}
+	return nil
+}
+
+// validateCAA aceita o formato "flags tag valor", ex: 0 issue "letsencrypt.org"
+func validateCAA(content string) error {
+	fields := strings.SplitN(content, " ", 3)
+	if len(fields) != 3 {
+		return fmt.Errorf("registro CAA deve ter o formato \"flags tag valor\", recebido %q", content)
+	}
+	if _, err := strconv.ParseUint(fields[0], 10, 8); err != nil {
+		return fmt.Errorf("flags CAA inválidas: %q", fields[0])
+	}
+	switch strings.ToLower(fields[1]) {
+	case "issue", "issuewild", "iodef":
+	default:
+		return fmt.Errorf("tag CAA inválida: %q (use issue, issuewild ou iodef)", fields[1])
+	}
+	if strings.TrimSpace(fields[2]) == "" {
+		return fmt.Errorf("valor CAA não pode ser vazio")
+	}
+	return nil
+}
+
+// validateSRV aceita o formato "prioridade peso porta destino"
+func validateSRV(content string) error {
+	fields := strings.Fields(content)
+	if len(fields) != 4 {
+		return fmt.Errorf("registro SRV deve ter o formato \"prioridade peso porta destino\", recebido %q", content)
+	}
+	for i, name := range []string{"prioridade", "peso", "porta"} {
+		if _, err := strconv.ParseUint(fields[i], 10, 16); err != nil {
+			return fmt.Errorf("%s SRV inválido: %q", name, fields[i])
+		}
+	}
+	if fields[3] != "." && (net.ParseIP(fields[3]) != nil || !isHostname(fields[3])) {
+		return fmt.Errorf("destino SRV requer um hostname válido, recebido %q", fields[3])
+	}
+	return nil
+}
+
+// isHostname valida um nome de domínio (com ou sem ponto final). Underscores são aceitos
+// por aparecerem em nomes de serviço como _dmarc e _sip._tcp.
+func isHostname(host string) bool {
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || len(host) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateDNSContent(t *testing.T) {
+	tests := []struct {
+		recordType DNSRecordType
+		content    string
+		wantErr    string
+	}{
+		{"A", "192.0.2.1", ""},
+		{"a", " 192.0.2.1 ", ""},
+		{"A", "2001:db8::1", "IPv4"},
+		{"A", "::ffff:192.0.2.1", "IPv4"},
+		{"A", "exemplo.com", "IPv4"},
+		{"A", "", "vazio"},
+		{"AAAA", "2001:db8::1", ""},
+		{"AAAA", "192.0.2.1", "IPv6"},
+		{"CNAME", "destino.exemplo.com", ""},
+		{"CNAME", "destino.exemplo.com.", ""},
+		{"CNAME", "192.0.2.1", "não para um IP"},
+		{"CNAME", "-invalido.com", "hostname válido"},
+		{"CNAME", "com espaço.com", "hostname válido"},
+		{"NS", "ns1.gocache.com.br", ""},
+		{"MX", "mail.exemplo.com", ""},
+		{"MX", "10 mail.exemplo.com", ""},
+		{"MX", "abc mail.exemplo.com", "prioridade MX"},
+		{"MX", "10 192.0.2.1", "hostname válido"},
+		{"MX", "10 mail.exemplo.com extra", "formato"},
+		{"TXT", `"v=spf1 include:_spf.exemplo.com -all"`, ""},
+		{"TXT", strings.Repeat("x", 4097), "4096"},
+		{"CAA", `0 issue "letsencrypt.org"`, ""},
+		{"CAA", `0 autorizar "letsencrypt.org"`, "tag CAA"},
+		{"CAA", `256 issue "letsencrypt.org"`, "flags CAA"},
+		{"SRV", "10 5 5060 sip.exemplo.com", ""},
+		{"SRV", "10 5 sip.exemplo.com", "formato"},
+		{"SRV", "10 5 99999 sip.exemplo.com", "porta"},
+		{"PTR", "exemplo.com", "não suportado"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.recordType)+" "+tt.content, func(t *testing.T) {
+			err := ValidateDNSContent(tt.recordType, tt.content)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("ValidateDNSContent() = %v, esperado nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("ValidateDNSContent() = %v, esperado erro contendo %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDNSName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"@", true},
+		{"*", true},
+		{"www", true},
+		{"*.app", true},
+		{"_dmarc", true},
+		{"_sip._tcp", true},
+		{"www.exemplo.com.", true},
+		{"", false},
+		{"www..exemplo", false},
+		{"-www", false},
+		{"www_", true},
+		{"w w", false},
+		{"a.*.b", false},
+		{strings.Repeat("a", 64), false},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateDNSName(tt.name); (err == nil) != tt.valid {
+			t.Errorf("ValidateDNSName(%q) = %v, esperado válido: %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestDNSRecordJSON(t *testing.T) {
+	var records []DNSRecord
+	data := `[
+		{"record_id": 12, "name": "www", "type": "a", "content": "1.2.3.4", "ttl": "300", "cloud": true},
+		{"record_id": "13", "name": "@", "type": "MX", "content": "10 mail.a.com", "ttl": 3600, "cloud": "0"}
+	]`
+	if err := json.Unmarshal([]byte(data), &records); err != nil {
+		t.Fatal(err)
+	}
+
+	if records[0].RecordID != "12" || records[0].Type != DNSTypeA || records[0].TTL != 300 || records[0].Cloud != 1 {
+		t.Errorf("registro = %+v", records[0])
+	}
+	if records[1].RecordID != "13" || records[1].TTL != 3600 || records[1].Cloud != 0 {
+		t.Errorf("registro = %+v", records[1])
+	}
+
+	var invalid FlexInt
+	if err := json.Unmarshal([]byte(`"abc"`), &invalid); err == nil {
+		t.Error("FlexInt aceitou \"abc\"")
+	}
+}
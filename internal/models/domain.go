@@ -1,11 +1,40 @@
 package models
 
-// DomainCreateRequest represents a request to create a new domain in GoCache
+// DomainCreateRequest representa a requisição para criar um domínio na GoCache. As
+// configurações não informadas usam os padrões de DefaultDomainSettings.
 type DomainCreateRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Origin      string `json:"origin" binding:"required"`
-	Description string `json:"description,omitempty"`
-	Enabled     bool   `json:"enabled"`
+	Name        string  `json:"name" binding:"required"`
+	Origin      string  `json:"origin" binding:"required"`
+	Description string  `json:"description,omitempty"`
+	Enabled     *bool   `json:"enabled,omitempty"` // padrão: true
+	CacheTTL    *int    `json:"cache_ttl,omitempty" binding:"omitempty,min=0"`
+	WAFStatus   *bool   `json:"waf_status,omitempty"`
+	CDNMode     *string `json:"cdn_mode,omitempty" binding:"omitempty,oneof=cname ns"`
+	SSLMode     *string `json:"ssl_mode,omitempty" binding:"omitempty,oneof=off partial full full_strict"`
+	Compression *bool   `json:"compression,omitempty"`
+}
+
+// DomainSettings são as configurações de um domínio na GoCache. Campos nil não são
+// enviados, então a mesma estrutura serve para alterações parciais.
+type DomainSettings struct {
+	Origin      *string `json:"origin,omitempty" form:"origin"`
+	Description *string `json:"description,omitempty" form:"description"`
+	Enabled     *bool   `json:"enabled,omitempty" form:"enabled"`
+	// CacheTTL é o tempo de cache padrão, em segundos
+	CacheTTL  *int  `json:"cache_ttl,omitempty" form:"cache_ttl" binding:"omitempty,min=0"`
+	WAFStatus *bool `json:"waf_status,omitempty" form:"waf_status"`
+	// CDNMode é o modo de apontamento: "cname" ou "ns"
+	CDNMode *string `json:"cdn_mode,omitempty" form:"cdn_mode" binding:"omitempty,oneof=cname ns"`
+	// SSLMode é o modo de conexão com a origem: "off", "partial", "full" ou "full_strict"
+	SSLMode     *string `json:"ssl_mode,omitempty" form:"ssl_mode" binding:"omitempty,oneof=off partial full full_strict"`
+	Compression *bool   `json:"compression,omitempty" form:"gzip_status"`
+}
+
+// DomainSettingsResponse representa a resposta com as configurações de um domínio
+type DomainSettingsResponse struct {
+	Status   bool           `json:"status"`
+	Domain   string         `json:"domain"`
+	Settings DomainSettings `json:"settings"`
 }
 
 // DomainInfo representa informações básicas de um domínio
@@ -22,3 +51,41 @@ type DomainListResponse struct {
 		AutoDiscovery map[string]interface{} `json:"auto_discovery"`
 	} `json:"response"`
 }
+
+// Tipos de item removidos junto com um domínio
+const (
+	DomainItemRule     = "rule"
+	DomainItemRedirect = "redirect"
+	DomainItemDNS      = "dns"
+	DomainItemMapping  = "mapping"
+	DomainItemDomain   = "domain"
+)
+
+// DomainDeletionItem é um item removido (ou que seria removido) junto com o domínio
+type DomainDeletionItem struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	Deleted     bool   `json:"deleted"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DomainDeletionSummary totaliza os itens por tipo
+type DomainDeletionSummary struct {
+	Rules     int `json:"rules"`
+	Redirects int `json:"redirects"`
+	DNS       int `json:"dns"`
+	Mappings  int `json:"mappings"`
+	Failed    int `json:"failed"`
+}
+
+// DomainDeletionResponse representa o resultado (ou a simulação) da remoção de um domínio
+type DomainDeletionResponse struct {
+	Status  bool                  `json:"status"`
+	Domain  string                `json:"domain"`
+	DryRun  bool                  `json:"dry_run"`
+	Deleted bool                  `json:"deleted"`
+	Error   string                `json:"error,omitempty"`
+	Summary DomainDeletionSummary `json:"summary"`
+	Items   []DomainDeletionItem  `json:"items"`
+}
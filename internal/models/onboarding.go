@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// OnboardingStatus é a situação de um onboarding de domínio
+type OnboardingStatus string
+
+// Situações de um onboarding
+const (
+	OnboardingRunning        OnboardingStatus = "running"
+	OnboardingCompleted      OnboardingStatus = "completed"
+	OnboardingRolledBack     OnboardingStatus = "rolled_back"
+	OnboardingRollbackFailed OnboardingStatus = "rollback_failed"
+	OnboardingInterrupted    OnboardingStatus = "interrupted"
+)
+
+// OnboardingStepStatus é a situação de uma etapa do onboarding
+type OnboardingStepStatus string
+
+// Situações de uma etapa do onboarding
+const (
+	OnboardingStepPending            OnboardingStepStatus = "pending"
+	OnboardingStepRunning            OnboardingStepStatus = "running"
+	OnboardingStepCompleted          OnboardingStepStatus = "completed"
+	OnboardingStepFailed             OnboardingStepStatus = "failed"
+	OnboardingStepCompensated        OnboardingStepStatus = "compensated"
+	OnboardingStepCompensationFailed OnboardingStepStatus = "compensation_failed"
+)
+
+// Etapas do onboarding, na ordem de execução
+const (
+	OnboardingStepDomain = "domain"
+	OnboardingStepDNS    = "dns"
+	OnboardingStepRule   = "rule"
+)
+
+// OnboardingRule configura a Smart Rule criada no onboarding (mesma regra de
+// /rules/{domain}/simplified)
+type OnboardingRule struct {
+	// Host atendido pela regra; se vazio, usa o nome completo do registro DNS
+	Host      string `json:"host,omitempty"`
+	BucketURL string `json:"bucket_url" binding:"required"`
+	AccountID string `json:"account_id" binding:"required"`
+}
+
+// OnboardingRequest representa a requisição para cadastrar um domínio com o registro DNS
+// e a regra de redirecionamento de uma landing page
+type OnboardingRequest struct {
+	Domain      string           `json:"domain" binding:"required"`
+	Origin      string           `json:"origin" binding:"required"`
+	Description string           `json:"description,omitempty"`
+	DNS         DNSCreateRequest `json:"dns"`
+	Rule        OnboardingRule   `json:"rule"`
+}
+
+// OnboardingStep registra a execução de uma etapa. ResourceID identifica o que foi criado
+// (domínio, ID do registro DNS ou ID da regra) e é usado para desfazer a etapa.
+type OnboardingStep struct {
+	Name              string               `json:"name"`
+	Status            OnboardingStepStatus `json:"status"`
+	ResourceID        string               `json:"resource_id,omitempty"`
+	Attempts          int                  `json:"attempts"`
+	Error             string               `json:"error,omitempty"`
+	CompensationError string               `json:"compensation_error,omitempty"`
+	StartedAt         *time.Time           `json:"started_at,omitempty"`
+	FinishedAt        *time.Time           `json:"finished_at,omitempty"`
+}
+
+// OnboardingWorkflow é o estado de um onboarding, gravado a cada etapa
+type OnboardingWorkflow struct {
+	ID          string            `json:"id"`
+	Domain      string            `json:"domain"`
+	Status      OnboardingStatus  `json:"status"`
+	Request     OnboardingRequest `json:"request"`
+	Steps       []OnboardingStep  `json:"steps"`
+	Error       string            `json:"error,omitempty"`
+	RequestedBy string            `json:"requested_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// OnboardingResponse representa a resposta com o estado de um onboarding
+type OnboardingResponse struct {
+	Status   bool               `json:"status"`
+	Error    string             `json:"error,omitempty"`
+	Workflow OnboardingWorkflow `json:"workflow"`
+}
+
+// OnboardingListResponse representa a resposta da listagem de onboardings
+type OnboardingListResponse struct {
+	Status    bool                 `json:"status"`
+	Workflows []OnboardingWorkflow `json:"workflows"`
+	Total     int                  `json:"total"`
+}
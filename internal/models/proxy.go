@@ -1,9 +1,92 @@
 package models
 
+import "time"
+
+// MappingMode define como as requisições de um domínio mapeado são atendidas
+type MappingMode string
+
+// Modos de mapeamento suportados
+const (
+	// MappingModeRedirect301 redireciona permanentemente para o destino (padrão)
+	MappingModeRedirect301 MappingMode = "redirect301"
+	// MappingModeRedirect302 redireciona temporariamente para o destino
+	MappingModeRedirect302 MappingMode = "redirect302"
+	// MappingModeProxy busca o conteúdo no destino e o entrega sob o domínio mapeado
+	MappingModeProxy MappingMode = "proxy"
+)
+
+// Valid indica se o modo é suportado; o modo vazio equivale a redirect301
+func (m MappingMode) Valid() bool {
+	switch m {
+	case "", MappingModeRedirect301, MappingModeRedirect302, MappingModeProxy:
+		return true
+	}
+	return false
+}
+
+// PathMatchType define como o path de uma regra é comparado com o da requisição
+type PathMatchType string
+
+// Tipos de comparação de path suportados
+const (
+	// PathMatchPrefix casa o path e tudo abaixo dele ("/checkout" casa "/checkout/passo-1");
+	// o restante do path é anexado ao destino
+	PathMatchPrefix PathMatchType = "prefix"
+	// PathMatchExact casa apenas o path informado
+	PathMatchExact PathMatchType = "exact"
+	// PathMatchRegex casa o path inteiro com uma expressão regular; os grupos capturados podem
+	// ser usados no destino como $1 ou ${nome}
+	PathMatchRegex PathMatchType = "regex"
+)
+
+// PathRule direciona os paths de um domínio mapeado para um destino próprio
+type PathRule struct {
+	Match       PathMatchType `json:"match" binding:"required,oneof=prefix exact regex"`
+	Path        string        `json:"path" binding:"required"`
+	Destination string        `json:"destination" binding:"required"`
+	// Mode, se vazio, herda o modo do mapeamento
+	Mode MappingMode `json:"mode,omitempty" binding:"omitempty,oneof=redirect301 redirect302 proxy"`
+}
+
 // DomainMapping armazena o mapeamento entre domu00ednios e seus destinos
 type DomainMapping struct {
-	Domain      string `json:"domain" binding:"required"`
-	Destination string `json:"destination" binding:"required"`
+	Domain      string      `json:"domain" binding:"required"`
+	Destination string      `json:"destination" binding:"required"`
+	Mode        MappingMode `json:"mode,omitempty" binding:"omitempty,oneof=redirect301 redirect302 proxy"`
+	// Fallbacks são destinos alternativos, em ordem de preferência, usados quando o
+	// Destination principal falha na verificação de saúde
+	Fallbacks []string `json:"fallbacks,omitempty"`
+	// Rules são avaliadas em ordem e a primeira que casar com o path vence; sem nenhuma,
+	// vale Destination
+	Rules []PathRule `json:"rules,omitempty" binding:"omitempty,dive"`
+	// Version é incrementada a cada alteração e usada como ETag no controle de concorrência
+	Version int64 `json:"version,omitempty"`
+}
+
+// DomainMappingUpdateRequest representa a requisição para substituir um mapeamento existente.
+// Version, se informada, deve ser a versão atual (equivale ao header If-Match).
+type DomainMappingUpdateRequest struct {
+	Destination string      `json:"destination" binding:"required"`
+	Mode        MappingMode `json:"mode,omitempty" binding:"omitempty,oneof=redirect301 redirect302 proxy"`
+	Fallbacks   []string    `json:"fallbacks,omitempty"`
+	Rules       []PathRule  `json:"rules,omitempty" binding:"omitempty,dive"`
+	Version     int64       `json:"version,omitempty"`
+}
+
+// MappingImportError descreve um item rejeitado na importação em lote
+type MappingImportError struct {
+	Line   int    `json:"line"`
+	Domain string `json:"domain,omitempty"`
+	Error  string `json:"error"`
+}
+
+// MappingImportResponse representa o resultado de uma importação em lote de mapeamentos.
+// A importação é atômica: se houver erros, nenhum mapeamento é gravado.
+type MappingImportResponse struct {
+	Success bool                 `json:"success"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Errors  []MappingImportError `json:"errors,omitempty"`
 }
 
 // DomainMappingResponse representa a resposta da API para operau00e7u00f5es de mapeamento de domu00ednio
@@ -18,5 +101,34 @@ type DomainMappingsListResponse struct {
 	Success   bool            `json:"success"`
 	Mappings  []DomainMapping `json:"mappings"`
 	Total     int             `json:"total"`
+	Page      int             `json:"page,omitempty"`
+	PageSize  int             `json:"page_size,omitempty"`
 	Error     string          `json:"error,omitempty"`
 }
+
+// TargetHealth é o estado de saúde de um destino de mapeamento
+type TargetHealth struct {
+	URL string `json:"url"`
+	// Role é "primary" ou "fallback"
+	Role       string     `json:"role"`
+	Healthy    bool       `json:"healthy"`
+	StatusCode int        `json:"status_code,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	LatencyMS  int64      `json:"latency_ms,omitempty"`
+	CheckedAt  *time.Time `json:"checked_at,omitempty"`
+}
+
+// MappingHealth resume a saúde dos destinos de um mapeamento
+type MappingHealth struct {
+	Domain string `json:"domain"`
+	// Active é o destino usado no momento: o primeiro saudável na ordem principal, fallbacks
+	Active  string         `json:"active"`
+	Targets []TargetHealth `json:"targets"`
+}
+
+// ProxyHealthResponse representa a resposta da API com a saúde dos destinos dos mapeamentos
+type ProxyHealthResponse struct {
+	Success  bool            `json:"success"`
+	Enabled  bool            `json:"enabled"`
+	Mappings []MappingHealth `json:"mappings"`
+}
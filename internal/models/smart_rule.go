@@ -1,22 +1,57 @@
 package models
 
+import "encoding/json"
+
 // SmartRuleRewriteMatch representa as condições para ativar uma regra de redirecionamento
 type SmartRuleRewriteMatch struct {
-	RequestURI     string   `json:"request_uri,omitempty" form:"match[request_uri]"`
-	Request        string   `json:"request,omitempty" form:"match[request]"` // Mantido para compatibilidade
-	RequestMethods []string `json:"request_method,omitempty" form:"match[request_method]"`
-	DeviceTypes    []string `json:"device_type,omitempty" form:"match[device_type]"`
-	Host           string   `json:"host,omitempty" form:"match[host]"`
+	RequestURI     string   `json:"request_uri,omitempty" form:"request_uri,omitempty"`
+	Request        string   `json:"request,omitempty" form:"-"` // Mantido para compatibilidade, enviado como request_uri
+	RequestMethods []string `json:"request_method,omitempty" form:"request_method,omitempty,brackets"`
+	DeviceTypes    []string `json:"device_type,omitempty" form:"device_type,omitempty"`
+	Host           string   `json:"host,omitempty" form:"host,omitempty"`
 }
 
 // SmartRuleRewriteAction representa a ação a ser executada quando a regra de redirecionamento é ativada
 type SmartRuleRewriteAction struct {
-	RedirectType string `json:"redirect_type,omitempty" form:"action[redirect_type]"`
-	RedirectTo   string `json:"redirect_to,omitempty" form:"action[redirect_to]"`
-	RewriteURI   string `json:"rewrite_uri,omitempty" form:"action[rewrite_uri]"`
-	RewriteHost  string `json:"rewrite_host,omitempty" form:"action[rewrite_host]"`
-	Destination  string `json:"destination,omitempty" form:"action[destination]"`
-	CrossOrigin  string `json:"cross_origin,omitempty" form:"action[cross_origin]"`
+	RedirectType string `json:"redirect_type,omitempty" form:"redirect_type,omitempty"`
+	RedirectTo   string `json:"redirect_to,omitempty" form:"redirect_to,omitempty"`
+	RewriteURI   string `json:"rewrite_uri,omitempty" form:"set_uri,omitempty"`
+	RewriteHost  string `json:"rewrite_host,omitempty" form:"set_host,omitempty"`
+	Destination  string `json:"destination,omitempty" form:"backend,omitempty"`
+	CrossOrigin  string `json:"cross_origin,omitempty" form:"cors,omitempty"`
+	SSLMode      string `json:"ssl_mode,omitempty" form:"ssl_mode,omitempty"` // Na criação, o padrão é "partial"
+}
+
+// UnmarshalJSON aceita também os nomes usados no formulário da GoCache (set_uri, set_host,
+// backend e cors), que são os retornados na listagem de regras
+func (a *SmartRuleRewriteAction) UnmarshalJSON(data []byte) error {
+	type plain SmartRuleRewriteAction
+	var aux struct {
+		plain
+		SetURI  string `json:"set_uri"`
+		SetHost string `json:"set_host"`
+		Backend string `json:"backend"`
+		CORS    string `json:"cors"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*a = SmartRuleRewriteAction(aux.plain)
+	for _, field := range []struct {
+		target *string
+		alias  string
+	}{
+		{&a.RewriteURI, aux.SetURI},
+		{&a.RewriteHost, aux.SetHost},
+		{&a.Destination, aux.Backend},
+		{&a.CrossOrigin, aux.CORS},
+	} {
+		if *field.target == "" {
+			*field.target = field.alias
+		}
+	}
+	return nil
 }
 
 // SmartRuleRewriteMetadata representa metadados adicionais da regra de redirecionamento
@@ -35,9 +70,9 @@ type SmartRuleRewrite struct {
 
 // SmartRuleRewriteCreateRequest representa a requisição para criar uma nova regra de redirecionamento
 type SmartRuleRewriteCreateRequest struct {
-	Match  SmartRuleRewriteMatch  `json:"match"`
-	Action SmartRuleRewriteAction `json:"action"`
-	Domain string                 `json:"-"` // Campo para armazenar o domínio, não será serializado para JSON
+	Match  SmartRuleRewriteMatch  `json:"match" form:"match"`
+	Action SmartRuleRewriteAction `json:"action" form:"action"`
+	Domain string                 `json:"-" form:"-"` // Campo para armazenar o domínio, não será serializado para JSON
 }
 
 // SmartRuleRewriteCreateResponse representa a resposta da API para criação de regra de redirecionamento
@@ -0,0 +1,208 @@
+// Package proxy atende as requisições dos domínios mapeados: resolve o mapeamento pelo
+// host, monta a URL de destino e redireciona ou faz proxy reverso conforme o modo.
+// É montado tanto pela API (cmd/api) quanto pelo servidor de proxy (cmd/proxy).
+package proxy
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/renatoroquejani/poc-gocache/internal/httpcache"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+)
+
+// Resolver encontra o destino de uma requisição pelo host e pelo path; implementado por
+// services.ProxyService
+type Resolver interface {
+	Resolve(host string, requestURL *url.URL) (services.Route, error)
+}
+
+// TagRecorder recebe as cache-tags das respostas do modo proxy; implementado por
+// services.TagService e services.RemoteTagRegistrar
+type TagRecorder interface {
+	RegisterTags(ctx context.Context, url string, tags []string) error
+}
+
+// Handler é o http.Handler dos domínios mapeados
+type Handler struct {
+	resolver  Resolver
+	transport http.RoundTripper
+
+	// Tags, quando definido, recebe o header Cache-Tag das respostas do modo proxy,
+	// associado à URL pública. O header é sempre removido da resposta ao visitante.
+	Tags TagRecorder
+
+	// TrustForwardedProto faz a URL pública (chave do cache local e do índice de tags) usar
+	// o esquema de X-Forwarded-Proto. Só deve ser ativado quando o acesso ao proxy passa
+	// obrigatoriamente por um proxy confiável, como a Gocache, que define o header; caso
+	// contrário o visitante escolheria a chave do cache.
+	TrustForwardedProto bool
+
+	tagOnce  sync.Once
+	tagQueue chan tagRegistration
+}
+
+// tagQueueSize limita quantos registros de tags aguardam o TagRecorder; com a fila cheia
+// os novos são descartados, e a URL é registrada de novo na próxima resposta
+const tagQueueSize = 1000
+
+// tagRegistration são as tags de uma resposta aguardando o TagRecorder
+type tagRegistration struct {
+	url  string
+	tags []string
+}
+
+// NewHandler cria o handler. O transport é usado no modo proxy para buscar o conteúdo no
+// destino (ex: um httpcache.Transport); se nil, usa http.DefaultTransport.
+func NewHandler(resolver Resolver, transport http.RoundTripper) *Handler {
+	return &Handler{
+		resolver:  resolver,
+		transport: transport,
+	}
+}
+
+// ServeHTTP atende a requisição do domínio mapeado ou responde 404 se o host não tiver mapeamento
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.Serve(w, r) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"domínio não configurado"}`))
+	}
+}
+
+// Serve atende a requisição se o host tiver mapeamento, indicando se o fez. Quando retorna
+// false nada foi escrito em w.
+func (h *Handler) Serve(w http.ResponseWriter, r *http.Request) bool {
+	log.Printf("Recebida requisição para host: %s, path: %s", r.Host, r.URL.Path)
+
+	// O host é normalizado pelo resolver (porta, IPv6, IDN), que também aplica as regras de path
+	route, err := h.resolver.Resolve(r.Host, r.URL)
+	if err != nil {
+		return false
+	}
+
+	target := Target(route.Destination, route.Path, r.URL.RawQuery)
+	switch route.Mode {
+	case models.MappingModeProxy:
+		h.serveReverseProxy(w, r, target)
+	case models.MappingModeRedirect302:
+		log.Printf("Redirecionando %s%s para: %s (302)", r.Host, r.URL.Path, target)
+		http.Redirect(w, r, target, http.StatusFound)
+	default:
+		log.Printf("Redirecionando %s%s para: %s", r.Host, r.URL.Path, target)
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	}
+	return true
+}
+
+// Middleware monta o handler na frente das rotas do gin: requisições de hosts mapeados são
+// atendidas e as demais seguem para as rotas registradas. Paths com um dos prefixos
+// informados (ex: "/api/") nunca são tratados como domínio mapeado.
+func (h *Handler) Middleware(skipPrefixes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, prefix := range skipPrefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		if h.Serve(c.Writer, c.Request) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// serveReverseProxy repassa a requisição para o destino. O header Host passa a ser o do
+// destino (necessário para S3 e virtual hosts) e o host original segue em X-Forwarded-Host.
+func (h *Handler) serveReverseProxy(w http.ResponseWriter, r *http.Request, target string) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		http.Error(w, "destino do mapeamento inválido", http.StatusBadGateway)
+		return
+	}
+
+	key := publicURL(r, h.TrustForwardedProto)
+	proxy := &httputil.ReverseProxy{
+		Transport: h.transport,
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL = targetURL
+			pr.Out.Host = ""
+			pr.SetXForwarded()
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			h.recordTags(resp, key)
+			resp.Header.Del("Cache-Tag")
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Printf("Erro no proxy de %s%s para %s: %v", r.Host, r.URL.Path, target, err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+
+	// O cache local indexa a resposta pela URL vista pelo visitante, não pela do destino,
+	// para que as expirações usem as mesmas URLs enviadas à Gocache
+	r = r.WithContext(httpcache.WithKey(r.Context(), key))
+
+	log.Printf("Proxy de %s%s para: %s", r.Host, r.URL.Path, target)
+	proxy.ServeHTTP(w, r)
+}
+
+// recordTags envia as tags de uma resposta GET bem-sucedida ao TagRecorder, em segundo
+// plano por um único worker. Respostas sem Cache-Tag também são enviadas, para que URLs que
+// perderam as tags saiam do índice.
+func (h *Handler) recordTags(resp *http.Response, key string) {
+	if h.Tags == nil || resp.Request == nil || resp.Request.Method != http.MethodGet || resp.StatusCode >= 300 {
+		return
+	}
+	h.tagOnce.Do(func() {
+		h.tagQueue = make(chan tagRegistration, tagQueueSize)
+		go h.registerTags()
+	})
+
+	select {
+	case h.tagQueue <- tagRegistration{url: key, tags: services.ParseCacheTags(resp.Header.Get("Cache-Tag"))}:
+	default:
+		log.Printf("Fila de cache-tags cheia, registro de %s descartado", key)
+	}
+}
+
+// registerTags é o worker que repassa as tags enfileiradas ao TagRecorder
+func (h *Handler) registerTags() {
+	for registration := range h.tagQueue {
+		if err := h.Tags.RegisterTags(context.Background(), registration.url, registration.tags); err != nil {
+			log.Printf("Erro ao registrar cache-tags de %s: %v", registration.url, err)
+		}
+	}
+}
+
+// publicURL reconstrói a URL requisitada pelo visitante, sem porta. Atrás da Gocache a
+// conexão chega em HTTP, então o esquema original vem de X-Forwarded-Proto quando
+// trustProto é verdadeiro.
+func publicURL(r *http.Request, trustProto bool) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	} else if proto := r.Header.Get("X-Forwarded-Proto"); trustProto && (proto == "https" || proto == "http") {
+		scheme = proto
+	}
+	host, err := services.NormalizeHost(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return scheme + "://" + host + r.URL.RequestURI()
+}
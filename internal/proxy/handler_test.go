@@ -0,0 +1,271 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/services"
+	"github.com/renatoroquejani/poc-gocache/internal/store"
+)
+
+func TestTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		requestURI  string
+		want        string
+	}{
+		{"raiz usa o destino", "https://dest.com/index.html", "/", "https://dest.com/index.html"},
+		{"path anexado", "https://dest.com", "/a/b", "https://dest.com/a/b"},
+		{"sem barra duplicada", "https://dest.com/base/", "/a", "https://dest.com/base/a"},
+		{"query preservada", "https://dest.com", "/a?x=1&y=2", "https://dest.com/a?x=1&y=2"},
+		{"query na raiz", "https://dest.com/index.html", "/?x=1", "https://dest.com/index.html?x=1"},
+		{"query somada à do destino", "https://dest.com/p?src=gc", "/?x=1", "https://dest.com/p?src=gc&x=1"},
+		{"path com query no destino", "https://dest.com/p/?src=gc", "/a?x=1", "https://dest.com/p/a?src=gc&x=1"},
+		{"path escapado", "https://dest.com", "/a%20b/c%2Fd", "https://dest.com/a%20b/c%2Fd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL, err := url.ParseRequestURI(tt.requestURI)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Target(tt.destination, requestURL.EscapedPath(), requestURL.RawQuery); got != tt.want {
+				t.Errorf("Target(%q, %q) = %q, esperado %q", tt.destination, tt.requestURI, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHandler executa os mesmos casos com o handler montado como na API (middleware do gin)
+// e como no cmd/proxy (NoRoute), garantindo que os dois binários se comportam igual
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/down/") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprintf(w, "%s %s host=%s fwd=%s", r.Method, r.URL.RequestURI(), r.Host, r.Header.Get("X-Forwarded-Host"))
+	}))
+	defer origin.Close()
+	originHost := origin.Listener.Addr().String()
+
+	mappingStore, err := store.New(store.KindMemory, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := services.NewProxyService(mappingStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mapping := range []models.DomainMapping{
+		{Domain: "site.com", Destination: "https://dest.com/index.html"},
+		{Domain: "path.com", Destination: "https://dest.com/base/"},
+		{Domain: "temp.com", Destination: "https://dest.com", Mode: models.MappingModeRedirect302},
+		{Domain: "*.wild.com", Destination: "https://wild.dest.com"},
+		{Domain: "proxied.com", Destination: origin.URL + "/bucket/", Mode: models.MappingModeProxy},
+		{Domain: "funil.com", Destination: "https://s3.com/pages/home/index.html", Rules: []models.PathRule{
+			{Match: models.PathMatchExact, Path: "/obrigado", Destination: "https://s3.com/pages/obrigado/index.html"},
+			{Match: models.PathMatchPrefix, Path: "/checkout", Destination: "https://s3.com/pages/checkout", Mode: models.MappingModeRedirect302},
+			{Match: models.PathMatchRegex, Path: `/produto/(?P<slug>[a-z-]+)`, Destination: "https://s3.com/pages/${slug}/index.html"},
+			{Match: models.PathMatchPrefix, Path: "/assets/", Destination: origin.URL + "/static/", Mode: models.MappingModeProxy},
+		}},
+		{Domain: "failover.com", Destination: origin.URL + "/down/", Mode: models.MappingModeProxy,
+			Fallbacks: []string{origin.URL + "/down/b/", origin.URL + "/backup/"}},
+		{Domain: "semsaude.com", Destination: origin.URL + "/down/a/", Mode: models.MappingModeProxy,
+			Fallbacks: []string{origin.URL + "/down/b/"}},
+	} {
+		if _, err := service.AddMapping(mapping); err != nil {
+			t.Fatal(err)
+		}
+	}
+	services.NewHealthChecker(service, services.HealthCheckOptions{}).CheckAll(context.Background())
+
+	handler := NewHandler(service, nil)
+
+	api := gin.New()
+	api.Use(handler.Middleware("/api/"))
+	api.GET("/api/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	proxyRouter := gin.New()
+	proxyRouter.GET("/api/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	proxyRouter.NoRoute(gin.WrapH(handler))
+
+	tests := []struct {
+		name         string
+		method       string
+		host         string
+		requestURI   string
+		wantStatus   int
+		wantLocation string
+		wantBody     string
+	}{
+		{"redirect na raiz", "GET", "site.com", "/", http.StatusMovedPermanently, "https://dest.com/index.html", ""},
+		{"redirect com query", "GET", "site.com", "/?utm=1", http.StatusMovedPermanently, "https://dest.com/index.html?utm=1", ""},
+		{"path anexado", "GET", "path.com", "/a/b?x=1&y=2", http.StatusMovedPermanently, "https://dest.com/base/a/b?x=1&y=2", ""},
+		{"host com porta e maiúsculas", "GET", "Site.COM:8080", "/x", http.StatusMovedPermanently, "https://dest.com/index.html/x", ""},
+		{"redirect 302", "POST", "temp.com", "/form?id=3", http.StatusFound, "https://dest.com/form?id=3", ""},
+		{"wildcard", "GET", "a.b.wild.com", "/p", http.StatusMovedPermanently, "https://wild.dest.com/p", ""},
+		{"proxy com path e query", "GET", "proxied.com", "/img/a.png?w=10", http.StatusOK, "",
+			"GET /bucket/img/a.png?w=10 host=" + originHost + " fwd=proxied.com"},
+		{"regra exata", "GET", "funil.com", "/obrigado?pedido=7", http.StatusMovedPermanently, "https://s3.com/pages/obrigado/index.html?pedido=7", ""},
+		{"regra de prefixo com restante do path", "GET", "funil.com", "/checkout/passo-2", http.StatusFound, "https://s3.com/pages/checkout/passo-2", ""},
+		{"regra de prefixo no próprio path", "GET", "funil.com", "/checkout", http.StatusFound, "https://s3.com/pages/checkout", ""},
+		{"prefixo respeita segmentos", "GET", "funil.com", "/checkouts", http.StatusMovedPermanently, "https://s3.com/pages/home/index.html/checkouts", ""},
+		{"regra regex com captura", "GET", "funil.com", "/produto/bolo-brigadeiro", http.StatusMovedPermanently, "https://s3.com/pages/bolo-brigadeiro/index.html", ""},
+		{"regex casa o path inteiro", "GET", "funil.com", "/produto/bolo/extra", http.StatusMovedPermanently, "https://s3.com/pages/home/index.html/produto/bolo/extra", ""},
+		{"sem regra usa o destino padrão", "GET", "funil.com", "/", http.StatusMovedPermanently, "https://s3.com/pages/home/index.html", ""},
+		{"regra em modo proxy", "GET", "funil.com", "/assets/app.js?v=2", http.StatusOK, "",
+			"GET /static/app.js?v=2 host=" + originHost + " fwd=funil.com"},
+		{"failover para o primeiro destino saudável", "GET", "failover.com", "/p?x=1", http.StatusOK, "",
+			"GET /backup/p?x=1 host=" + originHost + " fwd=failover.com"},
+		{"sem destino saudável mantém o principal", "GET", "semsaude.com", "/p", http.StatusServiceUnavailable, "", ""},
+		{"rota da API não é mapeada", "GET", "site.com", "/api/ping", http.StatusOK, "", "pong"},
+		{"host sem mapeamento", "GET", "unknown.com", "/", http.StatusNotFound, "", ""},
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	for _, mount := range []struct {
+		name   string
+		router http.Handler
+	}{
+		{"api", api},
+		{"proxy", proxyRouter},
+	} {
+		server := httptest.NewServer(mount.router)
+		defer server.Close()
+
+		for _, tt := range tests {
+			t.Run(mount.name+"/"+tt.name, func(t *testing.T) {
+				req, err := http.NewRequest(tt.method, server.URL+tt.requestURI, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Host = tt.host
+
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer resp.Body.Close()
+				body, _ := io.ReadAll(resp.Body)
+
+				if resp.StatusCode != tt.wantStatus {
+					t.Fatalf("status = %d, esperado %d (corpo: %s)", resp.StatusCode, tt.wantStatus, body)
+				}
+				if location := resp.Header.Get("Location"); location != tt.wantLocation {
+					t.Errorf("Location = %q, esperado %q", location, tt.wantLocation)
+				}
+				if tt.wantBody != "" && string(body) != tt.wantBody {
+					t.Errorf("corpo = %q, esperado %q", body, tt.wantBody)
+				}
+			})
+		}
+	}
+}
+
+// tagRecorder guarda as tags recebidas do Handler
+type tagRecorder chan []string
+
+func (r tagRecorder) RegisterTags(_ context.Context, url string, tags []string) error {
+	r <- append([]string{url}, tags...)
+	return nil
+}
+
+// TestHandlerCacheTags verifica que o header Cache-Tag do destino é enviado ao TagRecorder
+// com a URL pública e não chega ao visitante
+func TestHandlerCacheTags(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Tag", "Product-123, home,product-123")
+		fmt.Fprint(w, "ok")
+	}))
+	defer origin.Close()
+
+	mappingStore, err := store.New(store.KindMemory, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := services.NewProxyService(mappingStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddMapping(models.DomainMapping{Domain: "loja.com", Destination: origin.URL, Mode: models.MappingModeProxy}); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := make(tagRecorder, 1)
+	handler := NewHandler(service, nil)
+	handler.Tags = recorder
+	handler.TrustForwardedProto = true
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/produto/123?cor=azul", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Host = "loja.com"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := resp.Header.Get("Cache-Tag"); got != "" {
+		t.Errorf("Cache-Tag repassado ao visitante: %q", got)
+	}
+	select {
+	case got := <-recorder:
+		want := []string{"https://loja.com/produto/123?cor=azul", "home", "product-123"}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("tags registradas = %q, esperado %q", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("tags não registradas")
+	}
+}
+
+func TestPublicURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       string
+		proto      string
+		trustProto bool
+		want       string
+	}{
+		{"sem header", "Loja.com:8082", "", false, "http://loja.com/p?a=1"},
+		{"header ignorado por padrão", "loja.com", "https", false, "http://loja.com/p?a=1"},
+		{"header confiável", "loja.com", "https", true, "https://loja.com/p?a=1"},
+		{"valor inválido", "loja.com", "ftp", true, "http://loja.com/p?a=1"},
+		{"IPv6", "[::1]:8082", "", false, "http://[::1]/p?a=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/p?a=1", nil)
+			req.Host = tt.host
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			if got := publicURL(req, tt.trustProto); got != tt.want {
+				t.Errorf("publicURL() = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
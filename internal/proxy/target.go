@@ -0,0 +1,34 @@
+package proxy
+
+import "strings"
+
+// Target monta a URL de destino de uma requisição. O path (já escapado) é anexado ao
+// destino, exceto na raiz, para que destinos que apontam para um arquivo (ex: index.html)
+// funcionem. A query string da requisição é preservada e somada à do destino, se houver.
+func Target(destination, path, rawQuery string) string {
+	target := destination
+	query := ""
+	if base, rawQuery, ok := strings.Cut(destination, "?"); ok {
+		target, query = base, rawQuery
+	}
+
+	if path != "/" && path != "" {
+		// Remove a barra inicial do path se o destino já terminar com barra
+		if strings.HasSuffix(target, "/") && strings.HasPrefix(path, "/") {
+			path = path[1:]
+		}
+		target += path
+	}
+
+	if rawQuery != "" {
+		if query == "" {
+			query = rawQuery
+		} else {
+			query += "&" + rawQuery
+		}
+	}
+	if query != "" {
+		target += "?" + query
+	}
+	return target
+}
@@ -0,0 +1,461 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// BackupService exporta as configurações de uma conta GoCache (domínios, registros DNS,
+// redirecionamentos e Smart Rules) em um arquivo versionado e o restaura na conta do
+// cliente configurado; para restaurar em outra conta, é preciso outra instância da API
+type BackupService struct {
+	Domains   *DomainService
+	DNS       *DNSService
+	Redirects *RedirectService
+	Rules     *SmartRuleRewriteService
+	// Source identifica a conta no arquivo de backup (ex: o endereço da API)
+	Source string
+}
+
+// restoreOp é um item da restauração e a ação que o aplica; apply é nil quando não há
+// nada a fazer
+type restoreOp struct {
+	item  models.BackupRestoreItem
+	apply func(ctx context.Context) error
+}
+
+// NewBackupService cria uma nova instância de BackupService
+func NewBackupService(domains *DomainService, dns *DNSService, redirects *RedirectService, rules *SmartRuleRewriteService) *BackupService {
+	return &BackupService{
+		Domains:   domains,
+		DNS:       dns,
+		Redirects: redirects,
+		Rules:     rules,
+	}
+}
+
+// Backup lê da GoCache as configurações dos domínios informados, ou de todos se vazio
+func (s *BackupService) Backup(ctx context.Context, domains []string) (*models.BackupArchive, error) {
+	names, err := s.selectDomains(ctx, domains)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &models.BackupArchive{
+		Version:   models.BackupFormatVersion,
+		CreatedAt: time.Now().UTC(),
+		Source:    s.Source,
+		Domains:   make([]models.DomainBackup, 0, len(names)),
+	}
+	for _, name := range names {
+		backup, err := s.backupDomain(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("erro no backup do domínio %s: %w", name, err)
+		}
+		archive.Domains = append(archive.Domains, *backup)
+	}
+	log.Printf("Backup de %d domínio(s) concluído", len(archive.Domains))
+	return archive, nil
+}
+
+func (s *BackupService) backupDomain(ctx context.Context, name string) (*models.DomainBackup, error) {
+	settings, err := s.Domains.GetDomainSettings(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.DNS.ListDNS(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	redirects, err := s.Redirects.ListRedirects(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.Rules.ListRewriteRules(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DomainBackup{
+		Name:      name,
+		Settings:  *settings,
+		DNS:       append([]models.DNSRecord{}, records.Response.Records...),
+		Redirects: append([]models.RedirectRule{}, redirects.Response...),
+		Rules:     append([]models.SmartRuleRewrite{}, rules.Response.Rules...),
+	}, nil
+}
+
+// selectDomains retorna, ordenados, os domínios da conta que estão na lista (ou todos)
+func (s *BackupService) selectDomains(ctx context.Context, domains []string) ([]string, error) {
+	list, err := s.Domains.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	available := make([]string, 0, len(list.Response.Domains))
+	for _, name := range list.Response.Domains {
+		available = append(available, normalizeDomain(name))
+	}
+	sort.Strings(available)
+	if len(domains) == 0 {
+		return available, nil
+	}
+
+	var selected []string
+	for _, name := range domains {
+		name = normalizeDomain(name)
+		if !slices.Contains(available, name) {
+			return nil, fmt.Errorf("%w: domínio %s não existe na conta", ErrNotFound, name)
+		}
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+// Restore recria na conta atual o conteúdo do backup. Nada é removido: itens que só
+// existem no destino são mantidos. Itens que existem com outro conteúdo seguem a política
+// de conflito: skip mantém o destino, overwrite aplica o backup e fail cancela tudo antes
+// de qualquer alteração. Com DryRun, apenas retorna o que seria feito.
+func (s *BackupService) Restore(ctx context.Context, req models.BackupRestoreRequest) (*models.BackupRestoreResponse, error) {
+	if req.Archive.Version < 1 || req.Archive.Version > models.BackupFormatVersion {
+		return nil, fmt.Errorf("%w: versão do backup não suportada: %d (suportada: até %d)",
+			ErrInvalidRequest, req.Archive.Version, models.BackupFormatVersion)
+	}
+	conflict := req.Conflict
+	if conflict == "" {
+		conflict = models.BackupConflictSkip
+	}
+	if !slices.Contains([]string{models.BackupConflictSkip, models.BackupConflictOverwrite, models.BackupConflictFail}, conflict) {
+		return nil, fmt.Errorf("%w: política de conflito inválida: %q", ErrInvalidRequest, conflict)
+	}
+
+	var filter []string
+	for _, name := range req.Domains {
+		filter = append(filter, normalizeDomain(name))
+	}
+	var backups []models.DomainBackup
+	for _, backup := range req.Archive.Domains {
+		backup.Name = normalizeDomain(backup.Name)
+		if len(filter) == 0 || slices.Contains(filter, backup.Name) {
+			backups = append(backups, backup)
+		}
+	}
+	for _, name := range filter {
+		if !slices.ContainsFunc(backups, func(backup models.DomainBackup) bool { return backup.Name == name }) {
+			return nil, fmt.Errorf("%w: domínio %s não está no backup", ErrNotFound, name)
+		}
+	}
+
+	existing, err := s.Domains.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existingNames := make([]string, 0, len(existing.Response.Domains))
+	for _, name := range existing.Response.Domains {
+		existingNames = append(existingNames, normalizeDomain(name))
+	}
+
+	plans := make([][]restoreOp, 0, len(backups))
+	for _, backup := range backups {
+		ops, err := s.planDomain(ctx, backup, slices.Contains(existingNames, backup.Name), conflict == models.BackupConflictOverwrite)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao comparar o domínio %s: %w", backup.Name, err)
+		}
+		plans = append(plans, ops)
+	}
+
+	response := &models.BackupRestoreResponse{
+		Status:   true,
+		DryRun:   req.DryRun,
+		Conflict: conflict,
+		Items:    []models.BackupRestoreItem{},
+	}
+	conflicts := 0
+	for _, ops := range plans {
+		for _, op := range ops {
+			if op.item.Conflict {
+				conflicts++
+			}
+		}
+	}
+	if conflict == models.BackupConflictFail && conflicts > 0 {
+		for _, ops := range plans {
+			for _, op := range ops {
+				response.Items = append(response.Items, op.item)
+			}
+		}
+		response.Summary = summarizeRestore(response.Items)
+		response.Status = false
+		response.Error = fmt.Sprintf("%d item(ns) do backup já existem com outro conteúdo", conflicts)
+		return response, fmt.Errorf("%w: %s", ErrConflict, response.Error)
+	}
+
+	var failure error
+	for _, ops := range plans {
+		// Se o domínio não puder ser criado, os itens dele não são aplicados
+		var domainErr error
+		for i, op := range ops {
+			item := op.item
+			switch {
+			case req.DryRun || op.apply == nil:
+			case domainErr != nil:
+				item.Error = "não aplicado: falha no domínio: " + domainErr.Error()
+			default:
+				if err := op.apply(ctx); err != nil {
+					log.Printf("Erro ao restaurar %s %s do domínio %s: %v", item.Type, item.Key, item.Domain, err)
+					item.Error = err.Error()
+					if failure == nil {
+						failure = err
+					}
+					if i == 0 {
+						domainErr = err
+					}
+				} else {
+					item.Applied = true
+				}
+			}
+			response.Items = append(response.Items, item)
+		}
+	}
+	response.Summary = summarizeRestore(response.Items)
+
+	if failure != nil {
+		response.Status = false
+		response.Error = fmt.Sprintf("restauração incompleta: %d item(ns) com erro", response.Summary.Failed)
+		return response, fmt.Errorf("restauração incompleta: %w", failure)
+	}
+	if !req.DryRun {
+		log.Printf("Backup restaurado: %d criado(s), %d atualizado(s), %d mantido(s)",
+			response.Summary.Create, response.Summary.Update, response.Summary.Unchanged+response.Summary.Skip)
+	}
+	return response, nil
+}
+
+// planDomain compara o backup do domínio com a conta atual. O primeiro item é sempre o do
+// próprio domínio, seguido dos registros DNS, redirecionamentos e regras.
+func (s *BackupService) planDomain(ctx context.Context, backup models.DomainBackup, exists, overwrite bool) ([]restoreOp, error) {
+	name := backup.Name
+	newItem := func(itemType, key, action string) models.BackupRestoreItem {
+		return models.BackupRestoreItem{Domain: name, Type: itemType, Key: key, Action: action}
+	}
+	// conflictOp decide, pela política, entre aplicar o backup e manter o destino
+	conflictOp := func(itemType, key string, apply func(ctx context.Context) error) restoreOp {
+		if !overwrite {
+			item := newItem(itemType, key, models.BackupActionSkip)
+			item.Conflict = true
+			return restoreOp{item: item}
+		}
+		item := newItem(itemType, key, models.BackupActionUpdate)
+		item.Conflict = true
+		return restoreOp{item: item, apply: apply}
+	}
+
+	var ops []restoreOp
+	var currentRecords []models.DNSRecord
+	var currentRedirects []models.RedirectRule
+	var currentRules []models.SmartRuleRewrite
+
+	if !exists {
+		ops = append(ops, restoreOp{
+			item: newItem(models.DomainItemDomain, name, models.BackupActionCreate),
+			apply: func(ctx context.Context) error {
+				_, err := s.Domains.CreateDomain(ctx, domainCreateRequestFromSettings(name, backup.Settings))
+				return err
+			},
+		})
+	} else {
+		settings, err := s.Domains.GetDomainSettings(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if domainSettingsMatch(backup.Settings, *settings) {
+			ops = append(ops, restoreOp{item: newItem(models.DomainItemDomain, name, models.BackupActionUnchanged)})
+		} else {
+			ops = append(ops, conflictOp(models.DomainItemDomain, name, func(ctx context.Context) error {
+				_, err := s.Domains.UpdateDomainSettings(ctx, name, backup.Settings)
+				return err
+			}))
+		}
+
+		records, err := s.DNS.ListDNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		redirects, err := s.Redirects.ListRedirects(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		rules, err := s.Rules.ListRewriteRules(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		currentRecords, currentRedirects, currentRules = records.Response.Records, redirects.Response, rules.Response.Rules
+	}
+
+	// Registros DNS: mesmo pareamento por nome, tipo e conteúdo da sincronização de DNS
+	desired := make([]DesiredRecord, 0, len(backup.DNS))
+	for _, record := range backup.DNS {
+		record.Name = relativeRecordName(record.Name, name)
+		record.Type = record.Type.Normalize()
+		if isApexNS(record.Name, record.Type) {
+			continue
+		}
+		record.RecordID = ""
+		desired = append(desired, DesiredRecord{DNSRecord: record})
+	}
+	for _, change := range diffRecords(name, currentRecords, desired, true) {
+		if change.Desired == nil {
+			// Registros que só existem no destino são mantidos
+			continue
+		}
+		key := fmt.Sprintf("%s %s %s", change.Desired.Name, change.Desired.Type, change.Desired.Content)
+		apply := func(ctx context.Context) error {
+			return s.DNS.applyChange(ctx, name, &change)
+		}
+		switch change.Action {
+		case models.DNSChangeCreate:
+			ops = append(ops, restoreOp{item: newItem(models.DomainItemDNS, key, models.BackupActionCreate), apply: apply})
+		case models.DNSChangeUpdate:
+			ops = append(ops, conflictOp(models.DomainItemDNS, key, apply))
+		default:
+			ops = append(ops, restoreOp{item: newItem(models.DomainItemDNS, key, models.BackupActionUnchanged)})
+		}
+	}
+
+	// Redirecionamentos: identificados pela origem
+	for _, redirect := range backup.Redirects {
+		redirect.Domain = name
+		create := func(ctx context.Context) error {
+			_, err := s.Redirects.CreateRedirect(ctx, &models.RedirectCreateRequest{
+				Domain:      name,
+				Source:      redirect.Source,
+				Destination: redirect.Destination,
+				Type:        redirect.Type,
+			})
+			return err
+		}
+		index := slices.IndexFunc(currentRedirects, func(current models.RedirectRule) bool { return current.Source == redirect.Source })
+		switch {
+		case index < 0:
+			ops = append(ops, restoreOp{item: newItem(models.DomainItemRedirect, redirect.Source, models.BackupActionCreate), apply: create})
+		case currentRedirects[index].Destination == redirect.Destination && currentRedirects[index].Type == redirect.Type:
+			ops = append(ops, restoreOp{item: newItem(models.DomainItemRedirect, redirect.Source, models.BackupActionUnchanged)})
+		default:
+			current := currentRedirects[index]
+			ops = append(ops, conflictOp(models.DomainItemRedirect, redirect.Source, func(ctx context.Context) error {
+				// A GoCache não altera redirecionamentos; o atual é removido e recriado. Se a
+				// criação falhar, o original é recriado para a origem não ficar sem redirecionamento.
+				if _, err := s.Redirects.DeleteRedirect(ctx, name, current.ID); err != nil {
+					return err
+				}
+				err := create(ctx)
+				if err == nil {
+					return nil
+				}
+				if _, restoreErr := s.Redirects.CreateRedirect(ctx, &models.RedirectCreateRequest{
+					Domain:      name,
+					Source:      current.Source,
+					Destination: current.Destination,
+					Type:        current.Type,
+				}); restoreErr != nil {
+					return fmt.Errorf("%w; o redirecionamento original também não foi recriado: %v", err, restoreErr)
+				}
+				return fmt.Errorf("%w (redirecionamento original mantido)", err)
+			}))
+		}
+	}
+
+	// Smart Rules: identificadas pelo host e request_uri da condição
+	for _, rule := range backup.Rules {
+		request := &models.SmartRuleRewriteCreateRequest{Domain: name, Match: rule.Match, Action: rule.Action}
+		key := rule.Match.Host + rule.Match.RequestURI
+		index := slices.IndexFunc(currentRules, func(current models.SmartRuleRewrite) bool {
+			return current.Match.Host == rule.Match.Host && current.Match.RequestURI == rule.Match.RequestURI
+		})
+		switch {
+		case index < 0:
+			ops = append(ops, restoreOp{item: newItem(models.DomainItemRule, key, models.BackupActionCreate), apply: func(ctx context.Context) error {
+				_, err := s.Rules.CreateRewriteRule(ctx, request)
+				return err
+			}})
+		case reflect.DeepEqual(currentRules[index].Match, rule.Match) && reflect.DeepEqual(currentRules[index].Action, rule.Action):
+			ops = append(ops, restoreOp{item: newItem(models.DomainItemRule, key, models.BackupActionUnchanged)})
+		default:
+			currentID := currentRules[index].ID
+			ops = append(ops, conflictOp(models.DomainItemRule, key, func(ctx context.Context) error {
+				_, err := s.Rules.UpdateRewriteRule(ctx, name, currentID, request)
+				return err
+			}))
+		}
+	}
+	return ops, nil
+}
+
+// domainCreateRequestFromSettings monta a criação de um domínio com as configurações do backup
+func domainCreateRequestFromSettings(name string, settings models.DomainSettings) models.DomainCreateRequest {
+	req := models.DomainCreateRequest{
+		Name:        name,
+		Enabled:     settings.Enabled,
+		CacheTTL:    settings.CacheTTL,
+		WAFStatus:   settings.WAFStatus,
+		CDNMode:     settings.CDNMode,
+		SSLMode:     settings.SSLMode,
+		Compression: settings.Compression,
+	}
+	if settings.Origin != nil {
+		req.Origin = *settings.Origin
+	}
+	if settings.Description != nil {
+		req.Description = *settings.Description
+	}
+	return req
+}
+
+// domainSettingsMatch indica se as configurações informadas no backup já valem no destino
+func domainSettingsMatch(backup, current models.DomainSettings) bool {
+	return samePointer(backup.Origin, current.Origin) &&
+		samePointer(backup.Description, current.Description) &&
+		samePointer(backup.Enabled, current.Enabled) &&
+		samePointer(backup.CacheTTL, current.CacheTTL) &&
+		samePointer(backup.WAFStatus, current.WAFStatus) &&
+		samePointer(backup.CDNMode, current.CDNMode) &&
+		samePointer(backup.SSLMode, current.SSLMode) &&
+		samePointer(backup.Compression, current.Compression)
+}
+
+// samePointer compara valores opcionais; want nil significa "não informado" e sempre confere
+func samePointer[T comparable](want, have *T) bool {
+	return want == nil || (have != nil && *want == *have)
+}
+
+// summarizeRestore totaliza os itens por ação; os que falharam contam apenas como falha
+func summarizeRestore(items []models.BackupRestoreItem) models.BackupRestoreSummary {
+	var summary models.BackupRestoreSummary
+	for _, item := range items {
+		if item.Conflict {
+			summary.Conflicts++
+		}
+		switch {
+		case item.Error != "":
+			summary.Failed++
+		case item.Action == models.BackupActionCreate:
+			summary.Create++
+		case item.Action == models.BackupActionUpdate:
+			summary.Update++
+		case item.Action == models.BackupActionSkip:
+			summary.Skip++
+		default:
+			summary.Unchanged++
+		}
+	}
+	return summary
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+func TestBackupRestoreOverwriteRedirect(t *testing.T) {
+	tests := []struct {
+		name string
+		// failures é quantas criações de redirecionamento a Gocache rejeita
+		failures        int
+		wantDestination string
+		wantErr         bool
+	}{
+		{name: "sobrescrito", wantDestination: "/nova"},
+		{name: "criação falha: original recriado", failures: 1, wantDestination: "/atual", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newFakeGocache(t)
+			srv.AddDomain("a.com", nil)
+			srv.AddRedirect(gocachetest.Redirect{Domain: "a.com", Source: "/antiga", Destination: "/atual", Type: 301})
+			if tt.failures > 0 {
+				srv.Fail(gocachetest.Failure{Method: http.MethodPost, Path: "/redirects/a.com", Status: http.StatusTooManyRequests, Times: tt.failures})
+			}
+			service := NewBackupService(NewDomainService(client), NewDNSService(client), NewRedirectService(client), NewSmartRuleRewriteService(client))
+
+			response, err := service.Restore(context.Background(), models.BackupRestoreRequest{
+				Archive: models.BackupArchive{
+					Version: models.BackupFormatVersion,
+					Domains: []models.DomainBackup{{
+						Name:      "a.com",
+						Redirects: []models.RedirectRule{{Source: "/antiga", Destination: "/nova", Type: 301}},
+					}},
+				},
+				Conflict: models.BackupConflictOverwrite,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Restore() erro = %v, esperado erro = %v (resposta %+v)", err, tt.wantErr, response)
+			}
+
+			redirects := srv.Redirects("a.com")
+			if len(redirects) != 1 || redirects[0].Source != "/antiga" || redirects[0].Destination != tt.wantDestination {
+				t.Errorf("redirecionamentos = %+v, esperado /antiga -> %s", redirects, tt.wantDestination)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// EdgePurger expira o cache local do servidor de proxy (a nossa borda, na frente da
+// origem), para que uma única chamada de expiração limpe a Gocache e o proxy
+type EdgePurger interface {
+	PurgeAll(ctx context.Context, domain string) error
+	PurgeURLs(ctx context.Context, domain string, urls []string) error
+}
+
+// ProxyCachePurger implementa EdgePurger chamando os endpoints de cache do cmd/proxy
+type ProxyCachePurger struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewProxyCachePurger cria o cliente para o proxy em baseURL (ex: http://localhost:8082).
+// token é enviado como Bearer e deve ser o mesmo PROXY_PURGE_TOKEN configurado no proxy.
+func NewProxyCachePurger(baseURL, token string) *ProxyCachePurger {
+	return &ProxyCachePurger{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// PurgeAll expira todo o cache do domínio no proxy
+func (p *ProxyCachePurger) PurgeAll(ctx context.Context, domain string) error {
+	return p.do(ctx, "/api/cache/purge-all/"+url.PathEscape(domain), nil)
+}
+
+// PurgeURLs expira URLs específicas no proxy, com os mesmos wildcards aceitos pela Gocache
+func (p *ProxyCachePurger) PurgeURLs(ctx context.Context, domain string, urls []string) error {
+	body, err := json.Marshal(map[string]interface{}{"domain": domain, "urls": urls})
+	if err != nil {
+		return err
+	}
+	return p.do(ctx, "/api/cache/purge-urls", body)
+}
+
+func (p *ProxyCachePurger) do(ctx context.Context, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, p.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao expirar cache do proxy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("erro ao expirar cache do proxy: status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
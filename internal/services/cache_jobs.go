@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/jsonfile"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+const (
+	// DefaultMaxCacheJobs é o número de jobs de expiração mantidos em memória; ao passar do
+	// limite, os mais antigos são descartados
+	DefaultMaxCacheJobs = 1000
+	// cacheJobsSaveDelay agrupa as alterações dos jobs em uma única gravação do arquivo
+	cacheJobsSaveDelay = 2 * time.Second
+)
+
+type requesterContextKey struct{}
+
+// WithRequester associa ao contexto quem pediu a operação (usuário, sistema ou IP). Fica
+// registrado no histórico dos jobs de expiração de cache.
+func WithRequester(ctx context.Context, requester string) context.Context {
+	return context.WithValue(ctx, requesterContextKey{}, requester)
+}
+
+func requesterFromContext(ctx context.Context) string {
+	requester, _ := ctx.Value(requesterContextKey{}).(string)
+	return requester
+}
+
+// CacheJobFilter filtra a listagem de jobs de expiração
+type CacheJobFilter struct {
+	Domain string
+	Status models.CacheJobStatus
+	Limit  int
+}
+
+// cacheJobs guarda os jobs de expiração de cache, em ordem de criação. Quando path está
+// definido, os jobs são gravados até cacheJobsSaveDelay depois de serem criados ou de
+// terminarem, e em close, fora do mutex, para que as expirações não esperem pelo disco.
+type cacheJobs struct {
+	max       int
+	path      string
+	mutex     sync.RWMutex
+	jobs      map[string]*models.CachePurgeJob
+	order     []string
+	dirty     bool
+	saveTimer *time.Timer
+	// saveMutex impede que duas gravações do arquivo se sobreponham
+	saveMutex sync.Mutex
+}
+
+func newCacheJobs(max int) *cacheJobs {
+	return &cacheJobs{max: max, jobs: make(map[string]*models.CachePurgeJob)}
+}
+
+// load carrega os jobs gravados em path e passa a gravá-los nele. A expiração é síncrona,
+// então um job ainda pendente ou em execução no arquivo foi interrompido pelo reinício e
+// é marcado como falho.
+func (j *cacheJobs) load(path string) error {
+	var stored []*models.CachePurgeJob
+	if err := jsonfile.Load(path, &stored); err != nil {
+		return fmt.Errorf("erro ao carregar jobs de expiração: %w", err)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.path = path
+	j.jobs = make(map[string]*models.CachePurgeJob, len(stored))
+	j.order = j.order[:0]
+	for _, job := range stored {
+		if job.Status == models.CacheJobPending || job.Status == models.CacheJobRunning {
+			now := time.Now().UTC()
+			job.Status = models.CacheJobFailed
+			job.Error = "job interrompido pelo reinício da API"
+			job.UpdatedAt = now
+			job.CompletedAt = &now
+			job.History = append(job.History, models.CacheJobEvent{At: now, Status: job.Status, Message: job.Error})
+		}
+		j.jobs[job.ID] = job
+		j.order = append(j.order, job.ID)
+	}
+	j.trimLocked()
+	return nil
+}
+
+// create registra um novo job pendente. Total é o número de itens a expirar: as URLs, ou
+// 1 na expiração de todo o domínio, que a Gocache trata como um único pedido.
+func (j *cacheJobs) create(ctx context.Context, jobType, domain string, urls []string) (string, error) {
+	id, err := newID()
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar ID do job: %w", err)
+	}
+
+	total := len(urls)
+	if jobType == models.CacheJobTypeAll {
+		total = 1
+	}
+
+	now := time.Now().UTC()
+	job := &models.CachePurgeJob{
+		ID:          id,
+		Type:        jobType,
+		Domain:      domain,
+		URLs:        append([]string(nil), urls...),
+		RequestedBy: requesterFromContext(ctx),
+		Status:      models.CacheJobPending,
+		Progress:    models.CacheStatus{Total: total, Pending: total},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	message := "job criado"
+	if job.RequestedBy != "" {
+		message = "job criado por " + job.RequestedBy
+	}
+	job.History = []models.CacheJobEvent{{At: now, Status: job.Status, Message: message}}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.jobs[id] = job
+	j.order = append(j.order, id)
+	j.trimLocked()
+	j.scheduleSaveLocked()
+	return id, nil
+}
+
+// trimLocked descarta os jobs mais antigos além do limite. Deve ser chamada com j.mutex travado.
+func (j *cacheJobs) trimLocked() {
+	for len(j.order) > j.max {
+		delete(j.jobs, j.order[0])
+		j.order = j.order[1:]
+	}
+}
+
+// scheduleSaveLocked marca os jobs como alterados e agenda a gravação, se ainda não houver
+// uma agendada. Deve ser chamada com j.mutex travado.
+func (j *cacheJobs) scheduleSaveLocked() {
+	if j.path == "" {
+		return
+	}
+	j.dirty = true
+	if j.saveTimer != nil {
+		return
+	}
+	j.saveTimer = time.AfterFunc(cacheJobsSaveDelay, func() {
+		j.mutex.Lock()
+		j.saveTimer = nil
+		j.mutex.Unlock()
+		if err := j.save(); err != nil {
+			log.Printf("Erro ao gravar jobs de expiração: %v", err)
+		}
+	})
+}
+
+// save grava os jobs em j.path, se houver alterações. Os jobs são copiados com o mutex
+// travado e serializados fora dele. Em caso de erro os jobs continuam marcados como
+// alterados para a próxima gravação.
+func (j *cacheJobs) save() error {
+	j.saveMutex.Lock()
+	defer j.saveMutex.Unlock()
+
+	j.mutex.Lock()
+	if j.path == "" || !j.dirty {
+		j.mutex.Unlock()
+		return nil
+	}
+	path := j.path
+	jobs := make([]models.CachePurgeJob, len(j.order))
+	for i, id := range j.order {
+		jobs[i] = copyCacheJob(j.jobs[id])
+	}
+	j.dirty = false
+	j.mutex.Unlock()
+
+	if err := jsonfile.Save(path, jobs); err != nil {
+		j.mutex.Lock()
+		j.scheduleSaveLocked()
+		j.mutex.Unlock()
+		return fmt.Errorf("erro ao gravar jobs de expiração: %w", err)
+	}
+	return nil
+}
+
+// close grava os jobs ainda não gravados, cancelando a gravação agendada
+func (j *cacheJobs) close() error {
+	j.mutex.Lock()
+	if j.saveTimer != nil {
+		j.saveTimer.Stop()
+		j.saveTimer = nil
+	}
+	j.mutex.Unlock()
+	return j.save()
+}
+
+// update altera o job e registra a etapa no histórico
+func (j *cacheJobs) update(id string, status models.CacheJobStatus, message string, change func(job *models.CachePurgeJob)) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	job, ok := j.jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	job.Status = status
+	job.UpdatedAt = now
+	if change != nil {
+		change(job)
+	}
+	job.History = append(job.History, models.CacheJobEvent{At: now, Status: status, Message: message})
+	// As etapas intermediárias só ficam em memória; o arquivo é gravado ao terminar
+	if status == models.CacheJobCompleted || status == models.CacheJobFailed {
+		job.CompletedAt = &now
+		j.scheduleSaveLocked()
+	}
+}
+
+// finish encerra o job conforme o resultado da expiração na Gocache
+func (j *cacheJobs) finish(id string, result *models.CacheInvalidationResponse, err error) {
+	if err != nil {
+		j.update(id, models.CacheJobFailed, err.Error(), func(job *models.CachePurgeJob) {
+			job.Error = err.Error()
+		})
+		return
+	}
+
+	message := result.Message
+	if message == "" {
+		message = "expiração aceita pela Gocache"
+	}
+	j.update(id, models.CacheJobCompleted, message, func(job *models.CachePurgeJob) {
+		job.Message = result.Message
+		job.EdgeError = result.EdgeError
+		job.Progress.Processed = job.Progress.Total
+		job.Progress.Pending = 0
+	})
+}
+
+// get retorna uma cópia do job
+func (j *cacheJobs) get(id string) (models.CachePurgeJob, bool) {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	job, ok := j.jobs[id]
+	if !ok {
+		return models.CachePurgeJob{}, false
+	}
+	return copyCacheJob(job), true
+}
+
+// list retorna os jobs que atendem ao filtro, do mais recente para o mais antigo
+func (j *cacheJobs) list(filter CacheJobFilter) []models.CachePurgeJob {
+	domain := normalizeDomain(filter.Domain)
+
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	jobs := []models.CachePurgeJob{}
+	for i := len(j.order) - 1; i >= 0; i-- {
+		job := j.jobs[j.order[i]]
+		if domain != "" && job.Domain != domain {
+			continue
+		}
+		if filter.Status != "" && job.Status != filter.Status {
+			continue
+		}
+		jobs = append(jobs, copyCacheJob(job))
+		if filter.Limit > 0 && len(jobs) == filter.Limit {
+			break
+		}
+	}
+	return jobs
+}
+
+func copyCacheJob(job *models.CachePurgeJob) models.CachePurgeJob {
+	c := *job
+	c.URLs = append([]string(nil), job.URLs...)
+	c.History = append([]models.CacheJobEvent(nil), job.History...)
+	if job.CompletedAt != nil {
+		completedAt := *job.CompletedAt
+		c.CompletedAt = &completedAt
+	}
+	return c
+}
+
+// LoadJobs carrega os jobs de expiração gravados em path e passa a gravá-los nele, para que
+// o histórico sobreviva a reinícios da API. Sem LoadJobs, os jobs ficam só em memória.
+func (s *CacheService) LoadJobs(path string) error {
+	return s.jobs.load(path)
+}
+
+// Close grava os jobs de expiração ainda não gravados; é chamado ao encerrar a API
+func (s *CacheService) Close() error {
+	return s.jobs.close()
+}
+
+// GetJob retorna o job de expiração com o ID informado
+func (s *CacheService) GetJob(id string) (*models.CachePurgeJob, error) {
+	job, ok := s.jobs.get(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: job de expiração %q (inexistente ou descartado)", ErrNotFound, id)
+	}
+	return &job, nil
+}
+
+// ListJobs retorna o histórico de jobs de expiração, do mais recente para o mais antigo
+func (s *CacheService) ListJobs(filter CacheJobFilter) []models.CachePurgeJob {
+	return s.jobs.list(filter)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// PurgeByPrefix expira tudo o que começa com os prefixos informados. Cada prefixo é
+// convertido em wildcards para http e https (ex: /blog/ vira http://dominio/blog/* e
+// https://dominio/blog/*) e enviado em uma única expiração de URLs.
+func (s *CacheService) PurgeByPrefix(ctx context.Context, req models.CachePurgeByPrefixRequest) (*models.CacheInvalidationResponse, error) {
+	urls, err := prefixWildcards(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.PurgeUrls(ctx, models.CachePurgeRequest{Domain: req.Domain, URLs: urls})
+}
+
+// prefixWildcards valida os prefixos da requisição e monta os wildcards correspondentes,
+// sem repetições
+func prefixWildcards(req models.CachePurgeByPrefixRequest) ([]string, error) {
+	domain := normalizeDomain(req.Domain)
+	if domain == "" {
+		return nil, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+
+	prefixes := req.Prefixes
+	if req.Prefix != "" {
+		prefixes = append([]string{req.Prefix}, prefixes...)
+	}
+	if len(prefixes) == 0 {
+		return nil, fmt.Errorf("%w: informe ao menos um prefixo em prefix ou prefixes", ErrInvalidRequest)
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, prefix := range prefixes {
+		host, path, err := parsePurgePrefix(domain, strings.TrimSpace(prefix))
+		if err != nil {
+			return nil, err
+		}
+		for _, scheme := range []string{"http", "https"} {
+			wildcard := scheme + "://" + host + path + "*"
+			if !seen[wildcard] {
+				seen[wildcard] = true
+				urls = append(urls, wildcard)
+			}
+		}
+	}
+	return urls, nil
+}
+
+// parsePurgePrefix separa host e path de um prefixo. Um path usa o próprio domínio; uma
+// URL precisa ser do domínio ou de um subdomínio dele.
+func parsePurgePrefix(domain, prefix string) (string, string, error) {
+	if prefix == "" {
+		return "", "", fmt.Errorf("%w: prefixo vazio", ErrInvalidRequest)
+	}
+	if strings.ContainsAny(prefix, "*?#") {
+		return "", "", fmt.Errorf("%w: prefixo %q não pode conter *, ? ou # (o wildcard é adicionado automaticamente)", ErrInvalidRequest, prefix)
+	}
+
+	raw := prefix
+	switch {
+	case strings.HasPrefix(raw, "/"):
+		raw = "http://" + domain + raw
+	case !strings.Contains(raw, "://"):
+		raw = "http://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", "", fmt.Errorf("%w: prefixo inválido %q (use um path iniciado por / ou uma URL http(s))", ErrInvalidRequest, prefix)
+	}
+	if parsed.User != nil || parsed.Port() != "" {
+		return "", "", fmt.Errorf("%w: prefixo %q não pode ter usuário ou porta", ErrInvalidRequest, prefix)
+	}
+
+	host := normalizeDomain(parsed.Hostname())
+	if host != domain && !strings.HasSuffix(host, "."+domain) {
+		return "", "", fmt.Errorf("%w: prefixo %q não pertence ao domínio %s", ErrInvalidRequest, prefix, domain)
+	}
+
+	path := parsed.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return host, path, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+func TestPrefixWildcards(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.CachePurgeByPrefixRequest
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "path",
+			req:  models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "/blog/"},
+			want: []string{"http://a.com/blog/*", "https://a.com/blog/*"},
+		},
+		{
+			name: "URL do domínio",
+			req:  models.CachePurgeByPrefixRequest{Domain: "A.com", Prefix: "https://A.com"},
+			want: []string{"http://a.com/*", "https://a.com/*"},
+		},
+		{
+			name: "URL de subdomínio",
+			req:  models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "https://www.a.com/loja/"},
+			want: []string{"http://www.a.com/loja/*", "https://www.a.com/loja/*"},
+		},
+		{
+			name: "host sem esquema",
+			req:  models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "www.a.com/produto-"},
+			want: []string{"http://www.a.com/produto-*", "https://www.a.com/produto-*"},
+		},
+		{
+			name: "path com acento é escapado",
+			req:  models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "/café/"},
+			want: []string{"http://a.com/caf%C3%A9/*", "https://a.com/caf%C3%A9/*"},
+		},
+		{
+			name: "repetições entre prefix e prefixes",
+			req:  models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "/blog/", Prefixes: []string{" /blog/ ", "http://a.com/blog/", "/loja/"}},
+			want: []string{"http://a.com/blog/*", "https://a.com/blog/*", "http://a.com/loja/*", "https://a.com/loja/*"},
+		},
+		{name: "sem domínio", req: models.CachePurgeByPrefixRequest{Prefix: "/blog/"}, wantErr: true},
+		{name: "sem prefixo", req: models.CachePurgeByPrefixRequest{Domain: "a.com"}, wantErr: true},
+		{name: "prefixo vazio na lista", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefixes: []string{"/blog/", " "}}, wantErr: true},
+		{name: "outro domínio", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "https://outra.com/blog/"}, wantErr: true},
+		{name: "domínio com o mesmo final", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "https://xa.com/"}, wantErr: true},
+		{name: "wildcard", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "/blog/*"}, wantErr: true},
+		{name: "query string", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "/busca?q="}, wantErr: true},
+		{name: "fragmento", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "/blog#topo"}, wantErr: true},
+		{name: "porta", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "https://a.com:8443/blog/"}, wantErr: true},
+		{name: "usuário", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "https://admin@a.com/blog/"}, wantErr: true},
+		{name: "esquema não http", req: models.CachePurgeByPrefixRequest{Domain: "a.com", Prefix: "ftp://a.com/blog/"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prefixWildcards(tt.req)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRequest) {
+					t.Errorf("prefixWildcards() erro = %v, esperado ErrInvalidRequest", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("prefixWildcards() = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheServicePurgeByPrefix(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewCacheService(client)
+
+	if _, err := service.PurgeByPrefix(context.Background(), models.CachePurgeByPrefixRequest{Domain: "a.com", Prefixes: []string{"/blog/", "https://www.a.com/loja/"}}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"http://a.com/blog/*", "https://a.com/blog/*", "http://www.a.com/loja/*", "https://www.a.com/loja/*"}
+	if purges := srv.Purges(); len(purges) != 1 || !slices.Equal(purges[0].URLs, want) {
+		t.Errorf("expirações = %+v, esperado uma com %v", purges, want)
+	}
+
+	// Um prefixo inválido não envia nada à Gocache
+	if _, err := service.PurgeByPrefix(context.Background(), models.CachePurgeByPrefixRequest{Domain: "a.com", Prefixes: []string{"/blog/", "https://outra.com/"}}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("PurgeByPrefix() erro = %v, esperado ErrInvalidRequest", err)
+	}
+	if purges := srv.Purges(); len(purges) != 1 {
+		t.Errorf("expirações = %d, esperado 1", len(purges))
+	}
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/renatoroquejani/poc-gocache/internal/jsonfile"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+const (
+	// DefaultPurgeHookWindow é por quanto tempo uma expiração concluída é lembrada para
+	// descartar eventos anteriores a ela
+	DefaultPurgeHookWindow = 30 * time.Second
+	// maxPurgeHookResults é o número de expirações automáticas mantidas para consulta
+	maxPurgeHookResults = 100
+)
+
+// defaultPurgeHookPatterns são as URLs expiradas quando o domínio não define patterns
+var defaultPurgeHookPatterns = []string{"http://{host}{path}", "https://{host}{path}"}
+
+// CachePurgeHook assina o EventBus e coloca na PurgeQueue as URLs afetadas por cada
+// alteração. A fila agrupa as alterações em sequência e envia a expiração depois da última,
+// então o conteúdo cacheado entre duas alterações também é expirado. A configuração é por
+// domínio, com "*" como padrão. Eventos anteriores a uma expiração já concluída da mesma
+// URL são descartados.
+type CachePurgeHook struct {
+	queue  *PurgeQueue
+	window time.Duration
+	path   string
+
+	mutex   sync.Mutex
+	configs map[string]models.CachePurgeHookConfig
+	// recent guarda quando a expiração de cada URL foi concluída com sucesso
+	recent  map[string]time.Time
+	results []models.CachePurgeHookResult
+}
+
+// NewCachePurgeHook cria o assinante sobre a fila de expiração, registrando-se em
+// queue.OnBatch para saber quais URLs foram expiradas. Quando path é informado, as
+// configurações por domínio são gravadas nesse arquivo JSON e recarregadas ao iniciar.
+func NewCachePurgeHook(queue *PurgeQueue, window time.Duration, path string) (*CachePurgeHook, error) {
+	if window <= 0 {
+		window = DefaultPurgeHookWindow
+	}
+	h := &CachePurgeHook{
+		queue:   queue,
+		window:  window,
+		path:    path,
+		configs: map[string]models.CachePurgeHookConfig{"*": {Domain: "*", Enabled: true}},
+		recent:  make(map[string]time.Time),
+	}
+	queue.OnBatch = h.batchSent
+	if path == "" {
+		return h, nil
+	}
+
+	var configs []models.CachePurgeHookConfig
+	if err := jsonfile.Load(path, &configs); err != nil {
+		return nil, fmt.Errorf("erro ao carregar configuração de expiração automática: %w", err)
+	}
+	for _, config := range configs {
+		h.configs[config.Domain] = config
+	}
+	return h, nil
+}
+
+// Handle recebe um evento do EventBus. As URLs são calculadas na hora e colocadas na fila
+// de expiração, que as envia em segundo plano.
+func (h *CachePurgeHook) Handle(event Event) {
+	result := models.CachePurgeHookResult{
+		Event:    string(event.Type),
+		Domain:   normalizeDomain(event.Domain),
+		Host:     normalizeDomain(event.Host),
+		ObjectID: event.ID,
+		At:       event.At,
+	}
+	if result.Host == "" {
+		result.Host = result.Domain
+	}
+	if result.Domain == "" {
+		// Sem a zona (ex: mapeamento do proxy), usa o domínio registrável do host
+		zone, err := publicsuffix.EffectiveTLDPlusOne(strings.TrimPrefix(result.Host, "*."))
+		if err != nil {
+			result.Skipped = "domínio do evento desconhecido"
+			h.record(result)
+			return
+		}
+		result.Domain = zone
+	}
+
+	h.mutex.Lock()
+	config := h.configForLocked(result.Domain)
+	h.mutex.Unlock()
+
+	if len(config.Events) > 0 && !slices.Contains(config.Events, string(event.Type)) {
+		return
+	}
+	switch {
+	case !config.Enabled:
+		result.Skipped = "expiração automática desativada para o domínio"
+	case result.Host == "*" || strings.Contains(result.Host, "*"):
+		result.Skipped = "host com wildcard não pode ser expirado por URL"
+	}
+	if result.Skipped != "" {
+		h.record(result)
+		return
+	}
+
+	path := event.Path
+	if path == "" {
+		path = "/*"
+	} else if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	patterns := config.Patterns
+	if len(patterns) == 0 {
+		patterns = defaultPurgeHookPatterns
+	}
+	replacer := strings.NewReplacer("{host}", result.Host, "{domain}", result.Domain, "{path}", path)
+
+	h.mutex.Lock()
+	for _, pattern := range patterns {
+		url := replacer.Replace(pattern)
+		// Só descarta o evento se a URL já foi expirada depois da alteração
+		if purgedAt, ok := h.recent[result.Domain+"|"+url]; (ok && purgedAt.After(event.At)) || slices.Contains(result.URLs, url) {
+			continue
+		}
+		result.URLs = append(result.URLs, url)
+	}
+	h.mutex.Unlock()
+
+	if len(result.URLs) == 0 {
+		result.Skipped = "URLs já expiradas após a alteração"
+		h.record(result)
+		return
+	}
+
+	requester := "hook:" + string(event.Type)
+	if event.Requester != "" {
+		requester += " (" + event.Requester + ")"
+	}
+	if _, err := h.queue.Enqueue(WithRequester(context.Background(), requester), result.Domain, result.URLs); err != nil {
+		log.Printf("Erro na expiração automática de %s após %s: %v", result.Domain, event.Type, err)
+		result.Error = err.Error()
+	}
+	h.record(result)
+}
+
+// batchSent é chamado pela PurgeQueue após cada lote; as URLs só passam a contar como
+// expiradas quando a expiração foi concluída com sucesso
+func (h *CachePurgeHook) batchSent(batch models.PurgeBatchResult) {
+	if batch.Error != "" {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for key, at := range h.recent {
+		if batch.FlushedAt.Sub(at) >= h.window {
+			delete(h.recent, key)
+		}
+	}
+	for _, url := range batch.URLs {
+		h.recent[batch.Domain+"|"+url] = batch.FlushedAt
+	}
+}
+
+// Configs retorna as configurações por domínio, ordenadas, e as últimas expirações
+// automáticas, da mais recente para a mais antiga
+func (h *CachePurgeHook) Configs() ([]models.CachePurgeHookConfig, []models.CachePurgeHookResult) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	configs := make([]models.CachePurgeHookConfig, 0, len(h.configs))
+	for _, config := range h.configs {
+		configs = append(configs, config)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Domain < configs[j].Domain })
+
+	results := make([]models.CachePurgeHookResult, 0, len(h.results))
+	for i := len(h.results) - 1; i >= 0; i-- {
+		results = append(results, h.results[i])
+	}
+	return configs, results
+}
+
+// Config retorna a configuração aplicada ao domínio (a própria ou a de "*")
+func (h *CachePurgeHook) Config(domain string) models.CachePurgeHookConfig {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.configForLocked(normalizeDomain(domain))
+}
+
+// SetConfig cria ou substitui a configuração do domínio
+func (h *CachePurgeHook) SetConfig(domain string, req models.CachePurgeHookConfigRequest) (models.CachePurgeHookConfig, error) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return models.CachePurgeHookConfig{}, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+	for _, event := range req.Events {
+		if !slices.Contains(EventTypes, EventType(event)) {
+			return models.CachePurgeHookConfig{}, fmt.Errorf("%w: evento desconhecido %q", ErrInvalidRequest, event)
+		}
+	}
+	for _, pattern := range req.Patterns {
+		if !strings.HasPrefix(pattern, "http://") && !strings.HasPrefix(pattern, "https://") {
+			return models.CachePurgeHookConfig{}, fmt.Errorf("%w: pattern deve começar com http:// ou https://: %q", ErrInvalidRequest, pattern)
+		}
+	}
+
+	config := models.CachePurgeHookConfig{
+		Domain:   domain,
+		Enabled:  req.Enabled != nil && *req.Enabled,
+		Events:   req.Events,
+		Patterns: req.Patterns,
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.configs[domain] = config
+	return config, h.saveLocked()
+}
+
+// DeleteConfig remove a configuração do domínio, que volta a seguir a de "*". A
+// configuração "*" volta ao padrão (ativada para todos os eventos).
+func (h *CachePurgeHook) DeleteConfig(domain string) error {
+	domain = normalizeDomain(domain)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, ok := h.configs[domain]; !ok {
+		return fmt.Errorf("%w: configuração de expiração automática para %s", ErrNotFound, domain)
+	}
+	delete(h.configs, domain)
+	if domain == "*" {
+		h.configs["*"] = models.CachePurgeHookConfig{Domain: "*", Enabled: true}
+	}
+	return h.saveLocked()
+}
+
+func (h *CachePurgeHook) configForLocked(domain string) models.CachePurgeHookConfig {
+	if config, ok := h.configs[domain]; ok {
+		return config
+	}
+	return h.configs["*"]
+}
+
+func (h *CachePurgeHook) record(result models.CachePurgeHookResult) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.results = append(h.results, result)
+	if excess := len(h.results) - maxPurgeHookResults; excess > 0 {
+		h.results = append([]models.CachePurgeHookResult(nil), h.results[excess:]...)
+	}
+}
+
+func (h *CachePurgeHook) saveLocked() error {
+	if h.path == "" {
+		return nil
+	}
+	configs := make([]models.CachePurgeHookConfig, 0, len(h.configs))
+	for _, config := range h.configs {
+		configs = append(configs, config)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Domain < configs[j].Domain })
+
+	if err := jsonfile.Save(h.path, configs); err != nil {
+		return fmt.Errorf("erro ao gravar configuração de expiração automática: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+// newTestPurgeHook cria o hook sobre uma fila que só envia com Flush
+func newTestPurgeHook(t *testing.T) (*gocachetest.Server, *PurgeQueue, *CachePurgeHook) {
+	t.Helper()
+	srv, client := newFakeGocache(t)
+	queue := NewPurgeQueue(NewCacheService(client), PurgeQueueOptions{Debounce: time.Hour})
+	hook, err := NewCachePurgeHook(queue, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, queue, hook
+}
+
+func TestCachePurgeHookTrailingEdge(t *testing.T) {
+	srv, queue, hook := newTestPurgeHook(t)
+
+	// Duas alterações seguidas da mesma regra geram uma única expiração, enviada depois da última
+	for i := 0; i < 2; i++ {
+		hook.Handle(Event{Type: EventRuleUpdated, Domain: "a.com", Host: "a.com", Path: "/blog/*", At: time.Now()})
+	}
+	if purges := srv.Purges(); len(purges) != 0 {
+		t.Fatalf("expirações antes do envio da fila = %+v", purges)
+	}
+	queue.Flush("")
+
+	want := []string{"http://a.com/blog/*", "https://a.com/blog/*"}
+	purges := srv.Purges()
+	if len(purges) != 1 || !slices.Equal(purges[0].URLs, want) {
+		t.Fatalf("expirações = %+v, esperado uma com %v", purges, want)
+	}
+
+	// Uma alteração depois da expiração é expirada de novo, mesmo dentro da janela
+	hook.Handle(Event{Type: EventRuleUpdated, Domain: "a.com", Host: "a.com", Path: "/blog/*", At: time.Now()})
+	queue.Flush("")
+	if purges := srv.Purges(); len(purges) != 2 {
+		t.Errorf("expirações após nova alteração = %d, esperado 2", len(purges))
+	}
+
+	// Um evento anterior à última expiração concluída é descartado
+	hook.Handle(Event{Type: EventRuleUpdated, Domain: "a.com", Host: "a.com", Path: "/blog/*", At: time.Now().Add(-time.Second)})
+	_, results := hook.Configs()
+	if results[0].Skipped == "" {
+		t.Errorf("resultado = %+v, esperado descartado", results[0])
+	}
+}
+
+func TestCachePurgeHookFailedPurge(t *testing.T) {
+	srv, queue, hook := newTestPurgeHook(t)
+	srv.Fail(gocachetest.Failure{Method: http.MethodDelete, Path: "/cache/a.com", Status: http.StatusServiceUnavailable, Times: 1})
+
+	at := time.Now()
+	hook.Handle(Event{Type: EventRedirectChanged, Domain: "a.com", Host: "a.com", Path: "/antiga", At: at})
+	if batches := queue.Flush(""); len(batches) != 1 || batches[0].Error == "" {
+		t.Fatalf("lotes = %+v, esperado falha", batches)
+	}
+
+	// A expiração falhou, então o mesmo evento ainda dispara a expiração
+	hook.Handle(Event{Type: EventRedirectChanged, Domain: "a.com", Host: "a.com", Path: "/antiga", At: at})
+	if batches := queue.Flush(""); len(batches) != 1 || batches[0].Error != "" {
+		t.Errorf("lotes após a falha = %+v, esperado sucesso", batches)
+	}
+}
+
+func TestCachePurgeHookDNSEvents(t *testing.T) {
+	srv, queue, hook := newTestPurgeHook(t)
+	_, client := newFakeGocache(t)
+	dns := NewDNSService(client)
+	events := NewEventBus()
+	events.Subscribe(hook.Handle)
+	dns.Events = events
+	ctx := context.Background()
+
+	record, err := dns.CreateDNS(ctx, models.DNSCreateRequest{Domain: "a.com", Name: "www", Type: "A", Content: "1.2.3.4", TTL: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := record.Response.Records[0].RecordID.Int()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dns.UpdateDNS(ctx, id, models.DNSUpdateRequest{Domain: "a.com", Name: "@", Type: "A", Content: "5.6.7.8", TTL: 300}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dns.DeleteDNS(ctx, "a.com", id); err != nil {
+		t.Fatal(err)
+	}
+
+	_, results := hook.Configs()
+	wantHosts := []string{"a.com", "a.com", "www.a.com"}
+	for i, result := range results {
+		if result.Skipped != "" || result.Domain != "a.com" || result.Host != wantHosts[i] {
+			t.Errorf("resultado %d = %+v, esperado host %s", i, result, wantHosts[i])
+		}
+	}
+	queue.Flush("")
+	if purges := srv.Purges(); len(purges) != 1 || len(purges[0].URLs) != 4 {
+		t.Errorf("expirações = %+v", purges)
+	}
+}
+
+func TestCachePurgeHookDNSEventWithoutDomain(t *testing.T) {
+	srv, queue, hook := newTestPurgeHook(t)
+	srv.AddDNS("a.com", gocachetest.DNSRecord{ID: "10", Name: "www", Type: "A", Content: "1.1.1.1", TTL: "300"})
+	dns := NewDNSService(srv.Client())
+	events := NewEventBus()
+	events.Subscribe(hook.Handle)
+	dns.Events = events
+
+	// Sem a zona, a alteração é feita, mas a expiração automática é descartada
+	if _, err := dns.UpdateDNS(context.Background(), 10, models.DNSUpdateRequest{Name: "www", Type: "A", Content: "5.6.7.8", TTL: 300}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dns.DeleteDNS(context.Background(), "", 10); err != nil {
+		t.Fatal(err)
+	}
+
+	_, results := hook.Configs()
+	if len(results) != 2 {
+		t.Fatalf("resultados = %+v, esperado 2", results)
+	}
+	for _, result := range results {
+		if result.Skipped == "" {
+			t.Errorf("resultado = %+v, esperado descartado", result)
+		}
+	}
+	queue.Flush("")
+	if purges := srv.Purges(); len(purges) != 0 {
+		t.Errorf("expirações = %+v, esperado nenhuma", purges)
+	}
+}
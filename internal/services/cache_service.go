@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"log"
 
 	"github.com/renatoroquejani/poc-gocache/internal/models"
 	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
@@ -10,53 +12,115 @@ import (
 // CacheService fornece métodos para interagir com a API de cache da Gocache
 type CacheService struct {
 	client *gocache.Client
+
+	// Edge, quando definido, também é expirado a cada chamada (cache local do cmd/proxy)
+	Edge EdgePurger
+
+	jobs *cacheJobs
+}
+
+// cachePurgeForm representa o formulário enviado para a rota de expiração de URLs
+type cachePurgeForm struct {
+	ContentType string   `form:"content-type"`
+	URLs        []string `form:"urls"`
 }
 
 // NewCacheService cria uma nova instância de CacheService
 func NewCacheService(client *gocache.Client) *CacheService {
 	return &CacheService{
 		client: client,
+		jobs:   newCacheJobs(DefaultMaxCacheJobs),
 	}
 }
 
-// PurgeAllCache expira todo o cache de um domínio
-func (s *CacheService) PurgeAllCache(domain string) (*models.CacheInvalidationResponse, error) {
+// PurgeAllCache expira todo o cache de um domínio. A expiração fica registrada como um
+// job, consultável por GetJob.
+func (s *CacheService) PurgeAllCache(ctx context.Context, domain string) (*models.CacheInvalidationResponse, error) {
+	jobID, err := s.jobs.create(ctx, models.CacheJobTypeAll, normalizeDomain(domain), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// O proxy fica entre a Gocache e a origem, então é expirado primeiro; caso contrário a
+	// Gocache poderia buscar novamente a cópia antiga guardada nele
+	edgeErr := s.purgeEdge(jobID, func() error { return s.Edge.PurgeAll(ctx, domain) })
+
 	// Na API GoCache, usa-se a rota /cache/{dominio}/all para expurgar todo o cache
 	endpoint := fmt.Sprintf("/cache/%s/all", domain)
 	result := &models.CacheInvalidationResponse{}
 
 	// Para expurgar todo o cache, enviamos um DELETE sem body
-	_, err := s.client.DeleteSimple(endpoint, result)
+	s.jobs.update(jobID, models.CacheJobRunning, "expiração enviada à Gocache", nil)
+	_, err = s.client.DeleteSimpleContext(ctx, endpoint, result)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao expirar todo o cache: %w", err)
+		err = fmt.Errorf("erro ao expirar todo o cache (job %s): %w", jobID, err)
+		s.jobs.finish(jobID, nil, err)
+		return nil, err
 	}
 
+	setEdgeError(result, edgeErr)
+	result.JobID = jobID
+	s.jobs.finish(jobID, result, nil)
 	return result, nil
 }
 
-// PurgeUrls expira o cache para URLs específicas, podendo incluir máscaras/wildcards
-func (s *CacheService) PurgeUrls(req models.CachePurgeRequest) (*models.CacheInvalidationResponse, error) {
+// PurgeUrls expira o cache para URLs específicas, podendo incluir máscaras/wildcards. A
+// expiração fica registrada como um job, consultável por GetJob.
+func (s *CacheService) PurgeUrls(ctx context.Context, req models.CachePurgeRequest) (*models.CacheInvalidationResponse, error) {
+	jobID, err := s.jobs.create(ctx, models.CacheJobTypeURLs, normalizeDomain(req.Domain), req.URLs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Na API GoCache, o domínio é parte da URL
 	endpoint := fmt.Sprintf("/cache/%s", req.Domain)
 	result := &models.CacheInvalidationResponse{}
 
-	// Prepara os dados para a requisição
-	body := map[string]string{
-		"content-type": "*", // Por padrão, usamos wildcard para limpar todos os content-types
-	}
-	
-	// Adiciona cada URL como um item separado no formato urls[0], urls[1], etc.
-	// As URLs podem conter wildcards (ex: http://example.com/blog/*)
-	for i, url := range req.URLs {
-		body[fmt.Sprintf("urls[%d]", i)] = url
+	edgeErr := s.purgeEdge(jobID, func() error { return s.Edge.PurgeURLs(ctx, req.Domain, req.URLs) })
+
+	// As URLs são enviadas como urls[0], urls[1], etc. e podem conter wildcards
+	// (ex: http://example.com/blog/*). Por padrão, limpamos todos os content-types.
+	body := cachePurgeForm{
+		ContentType: "*",
+		URLs:        req.URLs,
 	}
 
-	_, err := s.client.Delete(endpoint, body, result)
+	s.jobs.update(jobID, models.CacheJobRunning, fmt.Sprintf("expiração de %d URL(s) enviada à Gocache", len(req.URLs)), nil)
+	_, err = s.client.DeleteContext(ctx, endpoint, body, result)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao expirar cache para URLs: %w", err)
+		err = fmt.Errorf("erro ao expirar cache para URLs (job %s): %w", jobID, err)
+		s.jobs.finish(jobID, nil, err)
+		return nil, err
 	}
 
+	setEdgeError(result, edgeErr)
+	result.JobID = jobID
+	s.jobs.finish(jobID, result, nil)
 	return result, nil
 }
 
+// purgeEdge expira o cache do proxy, se configurado, e registra a etapa no job
+func (s *CacheService) purgeEdge(jobID string, purge func() error) error {
+	if s.Edge == nil {
+		return nil
+	}
+	err := purge()
+	message := "cache local do proxy expirado"
+	if err != nil {
+		message = "falha ao expirar o cache local do proxy: " + err.Error()
+	}
+	s.jobs.update(jobID, models.CacheJobRunning, message, nil)
+	return err
+}
+
+// setEdgeError registra na resposta a falha ao expirar o cache do proxy. A expiração na
+// Gocache já foi feita, então a chamada não falha por causa do proxy.
+func setEdgeError(result *models.CacheInvalidationResponse, err error) {
+	if err == nil {
+		return
+	}
+	log.Printf("Cache da Gocache expirado, mas o cache do proxy não: %v", err)
+	result.EdgeError = err.Error()
+}
+
 
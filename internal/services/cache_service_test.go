@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+// fakeEdge registra as expirações pedidas ao cache local do proxy
+type fakeEdge struct {
+	all  []string
+	urls [][]string
+	err  error
+}
+
+func (e *fakeEdge) PurgeAll(_ context.Context, domain string) error {
+	e.all = append(e.all, domain)
+	return e.err
+}
+
+func (e *fakeEdge) PurgeURLs(_ context.Context, _ string, urls []string) error {
+	e.urls = append(e.urls, urls)
+	return e.err
+}
+
+func TestCacheServicePurge(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewCacheService(client)
+	edge := &fakeEdge{}
+	service.Edge = edge
+	ctx := WithRequester(context.Background(), "suporte")
+
+	all, err := service.PurgeAllCache(ctx, "a.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	urls, err := service.PurgeUrls(ctx, models.CachePurgeRequest{Domain: "a.com", URLs: []string{"https://a.com/x", "https://a.com/blog/*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	purges := srv.Purges()
+	if len(purges) != 2 || !purges[0].All || purges[0].Domain != "a.com" {
+		t.Fatalf("expirações = %+v", purges)
+	}
+	if purges[1].ContentType != "*" || len(purges[1].URLs) != 2 || purges[1].URLs[1] != "https://a.com/blog/*" {
+		t.Errorf("expiração de URLs = %+v", purges[1])
+	}
+	if len(edge.all) != 1 || len(edge.urls) != 1 {
+		t.Errorf("cache do proxy: all=%v urls=%v", edge.all, edge.urls)
+	}
+
+	for _, response := range []*models.CacheInvalidationResponse{all, urls} {
+		job, err := service.GetJob(response.JobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != models.CacheJobCompleted || job.RequestedBy != "suporte" || job.Progress.Pending != 0 {
+			t.Errorf("job = %+v", job)
+		}
+	}
+	if jobs := service.ListJobs(CacheJobFilter{Domain: "a.com"}); len(jobs) != 2 || jobs[0].ID != urls.JobID {
+		t.Errorf("ListJobs() = %+v", jobs)
+	}
+}
+
+func TestCacheServicePurgeFailure(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewCacheService(client)
+	srv.Fail(gocachetest.Failure{Path: "/cache/a.com", Status: http.StatusBadRequest, Message: "invalid url"})
+
+	if _, err := service.PurgeUrls(context.Background(), models.CachePurgeRequest{Domain: "a.com", URLs: []string{"x"}}); err == nil {
+		t.Fatal("PurgeUrls() não retornou erro")
+	}
+	jobs := service.ListJobs(CacheJobFilter{Status: models.CacheJobFailed})
+	if len(jobs) != 1 || jobs[0].Error == "" {
+		t.Errorf("jobs com falha = %+v", jobs)
+	}
+}
+
+func TestCacheServiceEdgeFailure(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewCacheService(client)
+	service.Edge = &fakeEdge{err: errors.New("proxy indisponível")}
+
+	// A falha do proxy é informada, mas não impede a expiração na Gocache
+	response, err := service.PurgeAllCache(context.Background(), "a.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.EdgeError == "" || len(srv.Purges()) != 1 {
+		t.Errorf("resposta = %+v, expirações = %+v", response, srv.Purges())
+	}
+}
+
+func TestCacheServiceLoadJobs(t *testing.T) {
+	_, client := newFakeGocache(t)
+	path := filepath.Join(t.TempDir(), "jobs", "cache-jobs.json")
+
+	service := NewCacheService(client)
+	if err := service.LoadJobs(path); err != nil {
+		t.Fatal(err)
+	}
+	done, err := service.PurgeAllCache(context.Background(), "a.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Simula um reinício no meio de uma expiração: o job fica gravado como pendente
+	pending, err := service.jobs.create(context.Background(), models.CacheJobTypeURLs, "a.com", []string{"https://a.com/x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A gravação é agrupada: o arquivo só aparece depois do atraso ou de Close
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("jobs gravados antes do atraso: %v", err)
+	}
+	if err := service.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := NewCacheService(client)
+	if err := restarted.LoadJobs(path); err != nil {
+		t.Fatal(err)
+	}
+	if job, err := restarted.GetJob(done.JobID); err != nil || job.Status != models.CacheJobCompleted {
+		t.Errorf("job concluído após reiniciar = %+v, %v", job, err)
+	}
+	job, err := restarted.GetJob(pending)
+	if err != nil || job.Status != models.CacheJobFailed || job.CompletedAt == nil {
+		t.Errorf("job interrompido após reiniciar = %+v, %v; esperado failed", job, err)
+	}
+	if jobs := restarted.ListJobs(CacheJobFilter{}); len(jobs) != 2 || jobs[0].ID != pending {
+		t.Errorf("ListJobs() = %+v", jobs)
+	}
+}
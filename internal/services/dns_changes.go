@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// DesiredRecord é um registro do estado desejado, com o nome relativo ao domínio.
+// Line indica a linha de origem quando o registro vem de um arquivo de zona.
+type DesiredRecord struct {
+	models.DNSRecord
+	Line int
+}
+
+// applyChanges aplica as alterações na ordem atualizações, exclusões e criações, para que
+// um nome possa trocar de tipo (ex: CNAME para A) sem conflito na Gocache. Dentro de cada
+// etapa até concurrency alterações são executadas em paralelo. Uma falha não interrompe
+// as demais; o resultado fica registrado em cada alteração.
+func (s *DNSService) applyChanges(ctx context.Context, domain string, changes []models.DNSRecordChange, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	for _, action := range []string{models.DNSChangeUpdate, models.DNSChangeDelete, models.DNSChangeCreate} {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, concurrency)
+
+		for i := range changes {
+			change := &changes[i]
+			if change.Action != action {
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				if err := s.applyChange(ctx, domain, change); err != nil {
+					change.Error = err.Error()
+					return
+				}
+				change.Applied = true
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func (s *DNSService) applyChange(ctx context.Context, domain string, change *models.DNSRecordChange) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	switch change.Action {
+	case models.DNSChangeCreate:
+		_, err := s.CreateDNS(ctx, models.DNSCreateRequest{
+			Domain:  domain,
+			Name:    absoluteRecordName(change.Desired.Name, domain),
+			Type:    change.Desired.Type,
+			Content: change.Desired.Content,
+			TTL:     change.Desired.TTL,
+			Cloud:   change.Desired.Cloud,
+		})
+		return err
+	case models.DNSChangeUpdate:
+		id, err := change.Current.RecordID.Int()
+		if err != nil {
+			return fmt.Errorf("record_id inválido %q", change.Current.RecordID)
+		}
+		_, err = s.UpdateDNS(ctx, id, models.DNSUpdateRequest{
+			Name:    absoluteRecordName(change.Desired.Name, domain),
+			Type:    change.Desired.Type,
+			Content: change.Desired.Content,
+			TTL:     change.Desired.TTL,
+			Cloud:   change.Desired.Cloud,
+			Domain:  domain,
+		})
+		return err
+	case models.DNSChangeDelete:
+		id, err := change.Current.RecordID.Int()
+		if err != nil {
+			return fmt.Errorf("record_id inválido %q", change.Current.RecordID)
+		}
+		_, err = s.DeleteDNS(ctx, domain, id)
+		return err
+	}
+	return nil
+}
+
+// summarizeChanges totaliza as alterações por ação; as que falharam contam apenas como falha
+func summarizeChanges(changes []models.DNSRecordChange) models.DNSChangeSummary {
+	var summary models.DNSChangeSummary
+	for _, change := range changes {
+		switch {
+		case change.Error != "":
+			summary.Failed++
+		case change.Action == models.DNSChangeCreate:
+			summary.Create++
+		case change.Action == models.DNSChangeUpdate:
+			summary.Update++
+		case change.Action == models.DNSChangeDelete:
+			summary.Delete++
+		default:
+			summary.Unchanged++
+		}
+	}
+	return summary
+}
+
+// diffRecords calcula as alterações necessárias para que os registros atuais fiquem iguais
+// aos desejados. Registros são agrupados por nome e tipo; dentro de um grupo, os de mesmo
+// conteúdo são pareados primeiro e os restantes viram atualizações, criações ou exclusões.
+// Quando compareCloud é falso (ex: arquivos de zona, que não têm essa informação), o
+// campo cloud dos registros atuais é preservado.
+func diffRecords(domain string, current []models.DNSRecord, desired []DesiredRecord, compareCloud bool) []models.DNSRecordChange {
+	currentByKey := make(map[string][]models.DNSRecord)
+	desiredByKey := make(map[string][]DesiredRecord)
+	var keys []string
+
+	for _, record := range current {
+		record.Name = relativeRecordName(record.Name, domain)
+		record.Type = record.Type.Normalize()
+		if isApexNS(record.Name, record.Type) {
+			continue
+		}
+		key := recordKey(record.Name, record.Type)
+		if _, ok := currentByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		currentByKey[key] = append(currentByKey[key], record)
+	}
+	for _, record := range desired {
+		key := recordKey(record.Name, record.Type)
+		if _, ok := currentByKey[key]; !ok {
+			if _, ok := desiredByKey[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		desiredByKey[key] = append(desiredByKey[key], record)
+	}
+	sort.Strings(keys)
+
+	changes := []models.DNSRecordChange{}
+	for _, key := range keys {
+		pending := append([]models.DNSRecord(nil), currentByKey[key]...)
+		var unmatched []DesiredRecord
+
+		for _, want := range desiredByKey[key] {
+			index := -1
+			for i, have := range pending {
+				if canonicalContent(have.Type, have.Content) == canonicalContent(want.Type, want.Content) {
+					index = i
+					break
+				}
+			}
+			if index < 0 {
+				unmatched = append(unmatched, want)
+				continue
+			}
+
+			have := pending[index]
+			pending = append(pending[:index], pending[index+1:]...)
+			if !compareCloud {
+				want.Cloud = have.Cloud
+			}
+			action := models.DNSChangeUnchanged
+			if have.TTL != want.TTL || have.Cloud != want.Cloud {
+				action = models.DNSChangeUpdate
+			}
+			changes = append(changes, recordChange(action, &have, &want))
+		}
+
+		for i, want := range unmatched {
+			if i < len(pending) {
+				have := pending[i]
+				if !compareCloud {
+					want.Cloud = have.Cloud
+				}
+				changes = append(changes, recordChange(models.DNSChangeUpdate, &have, &want))
+				continue
+			}
+			changes = append(changes, recordChange(models.DNSChangeCreate, nil, &want))
+		}
+		for i := len(unmatched); i < len(pending); i++ {
+			have := pending[i]
+			changes = append(changes, recordChange(models.DNSChangeDelete, &have, nil))
+		}
+	}
+	return changes
+}
+
+func recordChange(action string, current *models.DNSRecord, desired *DesiredRecord) models.DNSRecordChange {
+	change := models.DNSRecordChange{Action: action, Current: current}
+	if desired != nil {
+		record := desired.DNSRecord
+		change.Desired = &record
+		change.Line = desired.Line
+	}
+	return change
+}
+
+func recordKey(name string, recordType models.DNSRecordType) string {
+	return strings.ToLower(name) + "|" + string(recordType)
+}
+
+// isApexNS indica registros NS do apex, que são gerenciados pela própria Gocache
+func isApexNS(name string, recordType models.DNSRecordType) bool {
+	return name == "@" && recordType == models.DNSTypeNS
+}
+
+// canonicalContent normaliza o conteúdo de um registro para comparação
+func canonicalContent(recordType models.DNSRecordType, content string) string {
+	content = strings.TrimSpace(content)
+	switch recordType.Normalize() {
+	case models.DNSTypeA, models.DNSTypeAAAA:
+		if ip := net.ParseIP(content); ip != nil {
+			return ip.String()
+		}
+	case models.DNSTypeCNAME, models.DNSTypeNS:
+		return canonicalHost(content)
+	case models.DNSTypeMX:
+		fields := strings.Fields(content)
+		if len(fields) == 1 {
+			fields = []string{defaultMXPriority, fields[0]}
+		}
+		if len(fields) == 2 {
+			return fields[0] + " " + canonicalHost(fields[1])
+		}
+	case models.DNSTypeSRV:
+		fields := strings.Fields(content)
+		if len(fields) == 4 {
+			fields[3] = canonicalHost(fields[3])
+			return strings.Join(fields, " ")
+		}
+	case models.DNSTypeCAA:
+		fields := strings.SplitN(content, " ", 3)
+		if len(fields) == 3 {
+			return fields[0] + " " + strings.ToLower(fields[1]) + " " + strings.Trim(fields[2], `"`)
+		}
+	}
+	return content
+}
+
+func canonicalHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+func normalizeDomain(domain string) string {
+	return canonicalHost(strings.TrimSpace(domain))
+}
+
+// relativeRecordName converte o nome de um registro para o formato relativo ao domínio ("@", "www")
+func relativeRecordName(name, domain string) string {
+	name = canonicalHost(name)
+	switch {
+	case name == "" || name == "@" || name == domain:
+		return "@"
+	case strings.HasSuffix(name, "."+domain):
+		return strings.TrimSuffix(name, "."+domain)
+	default:
+		return name
+	}
+}
+
+// absoluteRecordName converte um nome relativo no nome completo enviado à Gocache; nomes
+// que já terminam no domínio são mantidos
+func absoluteRecordName(name, domain string) string {
+	name = strings.TrimSuffix(name, ".")
+	switch {
+	case name == "" || name == "@":
+		return domain
+	case name == domain || strings.HasSuffix(name, "."+domain):
+		return name
+	default:
+		return name + "." + domain
+	}
+}
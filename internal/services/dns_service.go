@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/renatoroquejani/poc-gocache/internal/models"
 	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
@@ -10,6 +12,9 @@ import (
 // DNSService fornece métodos para interagir com a API de domínios da Gocache
 type DNSService struct {
 	Client *gocache.Client
+
+	// Events, quando definido, recebe as alterações de registros DNS
+	Events *EventBus
 }
 
 // NewDNSService cria uma nova instância de DNSService
@@ -20,7 +25,7 @@ func NewDNSService(client *gocache.Client) *DNSService {
 }
 
 // ListDNS lista todos os domínios cadastrados para um domínio específico
-func (s *DNSService) ListDNS(domain string) (*models.DNSListResponse, error) {
+func (s *DNSService) ListDNS(ctx context.Context, domain string) (*models.DNSListResponse, error) {
 	if domain == "" {
 		return nil, fmt.Errorf("domínio não especificado")
 	}
@@ -29,7 +34,7 @@ func (s *DNSService) ListDNS(domain string) (*models.DNSListResponse, error) {
 	endpoint := fmt.Sprintf("/dns/%s", domain)
 	result := &models.DNSListResponse{}
 
-	_, err := s.Client.Get(endpoint, result)
+	_, err := s.Client.GetContext(ctx, endpoint, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao listar domínios: %w", err)
 	}
@@ -38,12 +43,12 @@ func (s *DNSService) ListDNS(domain string) (*models.DNSListResponse, error) {
 }
 
 // GetDNS obtém detalhes de um domínio específico pelo ID
-func (s *DNSService) GetDNS(id int) (*models.DNSCreateResponse, error) {
+func (s *DNSService) GetDNS(ctx context.Context, id int) (*models.DNSCreateResponse, error) {
 	// Endpoint correto conforme documentação da GoCache
 	endpoint := fmt.Sprintf("/dns/%d", id)
 	result := &models.DNSCreateResponse{}
 
-	_, err := s.Client.Get(endpoint, result)
+	_, err := s.Client.GetContext(ctx, endpoint, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao obter domínio: %w", err)
 	}
@@ -52,47 +57,86 @@ func (s *DNSService) GetDNS(id int) (*models.DNSCreateResponse, error) {
 }
 
 // CreateDNS cria um novo domínio
-func (s *DNSService) CreateDNS(req models.DNSCreateRequest) (*models.DNSCreateResponse, error) {
+func (s *DNSService) CreateDNS(ctx context.Context, req models.DNSCreateRequest) (*models.DNSCreateResponse, error) {
 	if req.Domain == "" {
 		return nil, fmt.Errorf("domínio não especificado")
 	}
 
+	req.Type = req.Type.Normalize()
+	if err := models.ValidateDNSContent(req.Type, req.Content); err != nil {
+		return nil, fmt.Errorf("%w: registro DNS inválido: %v", ErrInvalidRequest, err)
+	}
+
 	// Formata o endpoint conforme documentação: /v1/dns/{dominio}
 	endpoint := fmt.Sprintf("/dns/%s", req.Domain)
 	result := &models.DNSCreateResponse{}
 
-	_, err := s.Client.Post(endpoint, req, result)
+	_, err := s.Client.PostContext(ctx, endpoint, req, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar domínio: %w", err)
 	}
 
+	s.Events.Publish(Event{Type: EventDNSChanged, Domain: req.Domain, Host: absoluteRecordName(req.Name, normalizeDomain(req.Domain)), Requester: requesterFromContext(ctx)})
 	return result, nil
 }
 
-// UpdateDNS atualiza um domínio existente
-func (s *DNSService) UpdateDNS(id int, req models.DNSUpdateRequest) (*models.DNSUpdateResponse, error) {
+// UpdateDNS atualiza um registro existente. req.Domain é a zona do registro, opcional: como
+// o nome pode ser relativo (ex: "www" ou "@"), sem ela o evento de alteração não informa o
+// host e a expiração automática é descartada
+func (s *DNSService) UpdateDNS(ctx context.Context, id int, req models.DNSUpdateRequest) (*models.DNSUpdateResponse, error) {
+	req.Type = req.Type.Normalize()
+	if err := models.ValidateDNSContent(req.Type, req.Content); err != nil {
+		return nil, fmt.Errorf("%w: registro DNS inválido: %v", ErrInvalidRequest, err)
+	}
+
 	// Na API da GoCache, a atualização de DNS é feita pelo ID do registro
 	endpoint := fmt.Sprintf("/dns/%d", id)
 	result := &models.DNSUpdateResponse{}
 
-	_, err := s.Client.Put(endpoint, req, result)
+	_, err := s.Client.PutContext(ctx, endpoint, req, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao atualizar domínio: %w", err)
 	}
 
+	event := Event{Type: EventDNSChanged, Domain: normalizeDomain(req.Domain), ID: strconv.Itoa(id), Requester: requesterFromContext(ctx)}
+	if event.Domain != "" {
+		event.Host = absoluteRecordName(req.Name, event.Domain)
+	}
+	s.Events.Publish(event)
 	return result, nil
 }
 
-// DeleteDNS exclui um domínio pelo ID
-func (s *DNSService) DeleteDNS(id int) (*models.DNSDeleteResponse, error) {
+// DeleteDNS exclui um registro pelo ID. domain é a zona do registro, opcional, usada no
+// evento de alteração; sem ela o evento não informa o host.
+func (s *DNSService) DeleteDNS(ctx context.Context, domain string, id int) (*models.DNSDeleteResponse, error) {
+	domain = normalizeDomain(domain)
+
+	// O registro é consultado antes para que o evento informe o nome afetado; sem ele, o
+	// evento vale para o domínio todo
+	event := Event{Type: EventDNSChanged, Domain: domain, ID: strconv.Itoa(id), Requester: requesterFromContext(ctx)}
+	if s.Events != nil && domain != "" {
+		if current, err := s.GetDNS(ctx, id); err == nil && len(current.Response.Records) > 0 {
+			event.Host = absoluteRecordName(current.Response.Records[0].Name, domain)
+		}
+	}
+
+	result, err := s.deleteDNS(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Events.Publish(event)
+	return result, nil
+}
+
+// deleteDNS remove o registro sem consultá-lo antes nem publicar evento; usado na remoção
+// do domínio, que já listou os registros
+func (s *DNSService) deleteDNS(ctx context.Context, id int) (*models.DNSDeleteResponse, error) {
 	// Na API da GoCache, a exclusão de DNS é feita pelo ID do registro
 	endpoint := fmt.Sprintf("/dns/%d", id)
 	result := &models.DNSDeleteResponse{}
-
-	_, err := s.Client.DeleteSimple(endpoint, result)
-	if err != nil {
+	if _, err := s.Client.DeleteSimpleContext(ctx, endpoint, result); err != nil {
 		return nil, fmt.Errorf("erro ao excluir domínio: %w", err)
 	}
-
 	return result, nil
 }
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+// newFakeGocache inicia o servidor falso da Gocache e retorna um cliente apontando para ele
+func newFakeGocache(t *testing.T) (*gocachetest.Server, *gocache.Client) {
+	t.Helper()
+	srv := gocachetest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv, srv.Client(gocache.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+}
+
+func TestDNSServiceCRUD(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewDNSService(client)
+	ctx := context.Background()
+
+	created, err := service.CreateDNS(ctx, models.DNSCreateRequest{Domain: "a.com", Name: "www", Type: "a", Content: "1.2.3.4", TTL: 300, Cloud: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created.Response.Records) != 1 {
+		t.Fatalf("resposta da criação = %+v", created)
+	}
+	record := created.Response.Records[0]
+	if record.Type != "A" || record.TTL != 300 || record.Cloud != 1 {
+		t.Errorf("registro criado = %+v, esperado tipo normalizado, ttl 300 e cloud 1", record)
+	}
+
+	list, err := service.ListDNS(ctx, "a.com")
+	if err != nil || len(list.Response.Records) != 1 {
+		t.Fatalf("ListDNS() = %+v, %v", list, err)
+	}
+
+	id, err := record.RecordID.Int()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UpdateDNS(ctx, id, models.DNSUpdateRequest{Domain: "a.com", Name: "www", Type: "A", Content: "5.6.7.8", TTL: 600}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.DNS("a.com"); len(got) != 1 || got[0].Content != "5.6.7.8" || got[0].TTL != "600" {
+		t.Errorf("registros após a alteração = %+v", got)
+	}
+
+	got, err := service.GetDNS(ctx, id)
+	if err != nil || len(got.Response.Records) != 1 || got.Response.Records[0].Content != "5.6.7.8" {
+		t.Errorf("GetDNS() = %+v, %v", got, err)
+	}
+
+	if _, err := service.DeleteDNS(ctx, "a.com", id); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.DNS("a.com"); len(got) != 0 {
+		t.Errorf("registros após a remoção = %+v", got)
+	}
+
+	_, err = service.DeleteDNS(ctx, "a.com", id)
+	if !gocache.IsNotFound(err) {
+		t.Errorf("DeleteDNS() de registro removido = %v, esperado 404", err)
+	}
+}
+
+func TestDNSServiceValidation(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewDNSService(client)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"A com IPv6", func() error {
+			_, err := service.CreateDNS(ctx, models.DNSCreateRequest{Domain: "a.com", Name: "www", Type: "A", Content: "::1", TTL: 300})
+			return err
+		}},
+		{"MX apontando para IP", func() error {
+			_, err := service.CreateDNS(ctx, models.DNSCreateRequest{Domain: "a.com", Name: "@", Type: "MX", Content: "10 1.2.3.4", TTL: 300})
+			return err
+		}},
+		{"CNAME com IP na alteração", func() error {
+			_, err := service.UpdateDNS(ctx, 1, models.DNSUpdateRequest{Domain: "a.com", Name: "www", Type: "CNAME", Content: "1.2.3.4", TTL: 300})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrInvalidRequest) {
+				t.Errorf("erro = %v, esperado ErrInvalidRequest", err)
+			}
+		})
+	}
+
+	// Registros inválidos não chegam à Gocache
+	if got := len(srv.Requests()); got != 0 {
+		t.Errorf("requisições enviadas = %d, esperado 0", got)
+	}
+}
+
+func TestDNSServiceAPIError(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	srv.Fail(gocachetest.Failure{Method: http.MethodPost, Path: "/dns/a.com", Status: http.StatusUnprocessableEntity, Message: "record already exists"})
+
+	_, err := NewDNSService(client).CreateDNS(context.Background(), models.DNSCreateRequest{Domain: "a.com", Name: "www", Type: "A", Content: "1.2.3.4", TTL: 300})
+	apiErr, ok := gocache.AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Message != "record already exists" {
+		t.Errorf("erro = %v, esperado APIError 422", err)
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+const (
+	// DefaultSyncPlanTTL é o tempo de validade de um plano de sincronização
+	DefaultSyncPlanTTL = 15 * time.Minute
+	// DefaultSyncConcurrency é o número padrão de alterações executadas em paralelo
+	DefaultSyncConcurrency = 4
+)
+
+// DNSSyncService calcula e executa planos de sincronização declarativa de registros DNS.
+// Os planos ficam em memória até serem executados ou expirarem.
+type DNSSyncService struct {
+	DNS         *DNSService
+	PlanTTL     time.Duration
+	Concurrency int
+
+	mutex sync.Mutex
+	plans map[string]*syncPlan
+}
+
+// syncPlan guarda o plano retornado ao cliente e o estado desejado que o originou
+type syncPlan struct {
+	plan    models.DNSSyncPlan
+	desired []DesiredRecord
+	// applying indica que o plano está sendo conferido por um Apply em andamento
+	applying bool
+}
+
+// NewDNSSyncService cria uma nova instância de DNSSyncService
+func NewDNSSyncService(dns *DNSService) *DNSSyncService {
+	return &DNSSyncService{
+		DNS:         dns,
+		PlanTTL:     DefaultSyncPlanTTL,
+		Concurrency: DefaultSyncConcurrency,
+		plans:       make(map[string]*syncPlan),
+	}
+}
+
+// Plan compara o estado desejado com os registros atuais do domínio e guarda o plano
+// resultante para ser executado depois com Apply
+func (s *DNSSyncService) Plan(ctx context.Context, domain string, req models.DNSSyncRequest) (*models.DNSSyncPlan, error) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return nil, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+
+	desired, err := desiredFromSync(domain, req.Records)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.DNS.ListDNS(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar ID do plano: %w", err)
+	}
+
+	now := time.Now().UTC()
+	changes := diffRecords(domain, list.Response.Records, desired, true)
+	plan := models.DNSSyncPlan{
+		ID:        id,
+		Domain:    domain,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.PlanTTL),
+		Summary:   summarizeChanges(changes),
+		Changes:   changes,
+	}
+
+	s.mutex.Lock()
+	s.removeExpired(now)
+	s.plans[id] = &syncPlan{plan: plan, desired: desired}
+	s.mutex.Unlock()
+
+	return &plan, nil
+}
+
+// Apply executa um plano criado por Plan. Antes de aplicar, o diff é recalculado: se os
+// registros na Gocache mudaram desde o planejamento, o plano é recusado com ErrConflict.
+// Um plano só pode ser executado uma vez: ele é consumido quando a conferência passa ou
+// detecta conflito; se a consulta dos registros falhar, o plano continua disponível.
+func (s *DNSSyncService) Apply(ctx context.Context, domain string, req models.DNSSyncApplyRequest) (*models.DNSSyncApplyResponse, error) {
+	domain = normalizeDomain(domain)
+
+	s.mutex.Lock()
+	s.removeExpired(time.Now().UTC())
+	stored, ok := s.plans[req.PlanID]
+	if !ok || stored.plan.Domain != domain {
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("%w: plano %q para o domínio %s (inexistente ou expirado)", ErrNotFound, req.PlanID, domain)
+	}
+	if stored.applying {
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("%w: o plano %s já está sendo executado", ErrConflict, req.PlanID)
+	}
+	// Marca o plano para impedir execuções concorrentes enquanto os registros são conferidos
+	stored.applying = true
+	s.mutex.Unlock()
+
+	list, err := s.DNS.ListDNS(ctx, domain)
+	if err != nil {
+		s.mutex.Lock()
+		stored.applying = false
+		s.mutex.Unlock()
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	delete(s.plans, req.PlanID)
+	s.mutex.Unlock()
+
+	changes := diffRecords(domain, list.Response.Records, stored.desired, true)
+	if !sameChanges(changes, stored.plan.Changes) {
+		return nil, fmt.Errorf("%w: os registros de %s mudaram desde a criação do plano %s; gere um novo plano", ErrConflict, domain, req.PlanID)
+	}
+
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = s.Concurrency
+	}
+	s.DNS.applyChanges(ctx, domain, changes, concurrency)
+
+	return &models.DNSSyncApplyResponse{
+		PlanID:  req.PlanID,
+		Domain:  domain,
+		Summary: summarizeChanges(changes),
+		Changes: changes,
+	}, nil
+}
+
+// removeExpired descarta planos vencidos que não estejam em execução. Deve ser chamada
+// com s.mutex travado.
+func (s *DNSSyncService) removeExpired(now time.Time) {
+	for id, stored := range s.plans {
+		if !stored.applying && now.After(stored.plan.ExpiresAt) {
+			delete(s.plans, id)
+		}
+	}
+}
+
+// desiredFromSync valida os registros enviados e os converte para nomes relativos ao domínio
+func desiredFromSync(domain string, records []models.DNSSyncRecord) ([]DesiredRecord, error) {
+	desired := make([]DesiredRecord, 0, len(records))
+	seen := make(map[string]int)
+
+	for i, record := range records {
+		record.Type = record.Type.Normalize()
+		name := relativeRecordName(record.Name, domain)
+
+		if isApexNS(name, record.Type) {
+			return nil, fmt.Errorf("%w: records[%d]: registros NS do apex são gerenciados pela Gocache", ErrInvalidRequest, i)
+		}
+		if err := models.ValidateDNSContent(record.Type, record.Content); err != nil {
+			return nil, fmt.Errorf("%w: records[%d]: %v", ErrInvalidRequest, i, err)
+		}
+
+		key := recordKey(name, record.Type) + "|" + canonicalContent(record.Type, record.Content)
+		if first, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%w: records[%d] duplica records[%d]", ErrInvalidRequest, i, first)
+		}
+		seen[key] = i
+
+		desired = append(desired, DesiredRecord{DNSRecord: models.DNSRecord{
+			Name:    name,
+			Type:    record.Type,
+			Content: record.Content,
+			TTL:     record.TTL,
+			Cloud:   record.Cloud,
+		}})
+	}
+	return desired, nil
+}
+
+// sameChanges compara dois conjuntos de alterações, ignorando o resultado da execução
+func sameChanges(a, b []models.DNSRecordChange) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Action != b[i].Action || !sameRecord(a[i].Current, b[i].Current) || !sameRecord(a[i].Desired, b[i].Desired) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameRecord(a, b *models.DNSRecord) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+func TestDNSSyncServiceApply(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewDNSSyncService(NewDNSService(client))
+	srv.AddDNS("exemplo.com", gocachetest.DNSRecord{Name: "www", Type: "A", Content: "192.0.2.1", TTL: "300"})
+	ctx := context.Background()
+
+	request := models.DNSSyncRequest{Records: []models.DNSSyncRecord{
+		{Name: "www", Type: "A", Content: "192.0.2.2", TTL: 300},
+		{Name: "api", Type: "A", Content: "192.0.2.3", TTL: 300},
+	}}
+	plan, err := service.Plan(ctx, "exemplo.com", request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (models.DNSChangeSummary{Create: 1, Update: 1}); plan.Summary != want {
+		t.Errorf("resumo = %+v, esperado %+v", plan.Summary, want)
+	}
+
+	// Uma falha transitória na conferência não consome o plano
+	srv.Fail(gocachetest.Failure{Method: http.MethodGet, Path: "/dns", Status: http.StatusTooManyRequests, Times: 1})
+	if _, err := service.Apply(ctx, "exemplo.com", models.DNSSyncApplyRequest{PlanID: plan.ID}); err == nil {
+		t.Fatal("Apply() sem erro, esperado a falha da Gocache")
+	}
+
+	if _, err := service.Apply(ctx, "outro.com", models.DNSSyncApplyRequest{PlanID: plan.ID}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Apply() em outro domínio = %v, esperado ErrNotFound", err)
+	}
+
+	result, err := service.Apply(ctx, "exemplo.com", models.DNSSyncApplyRequest{PlanID: plan.ID})
+	if err != nil {
+		t.Fatalf("Apply() após a falha = %v", err)
+	}
+	if result.Summary.Failed != 0 || len(srv.DNS("exemplo.com")) != 2 {
+		t.Errorf("resultado = %+v, registros = %+v", result.Summary, srv.DNS("exemplo.com"))
+	}
+
+	if _, err := service.Apply(ctx, "exemplo.com", models.DNSSyncApplyRequest{PlanID: plan.ID}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("segundo Apply() = %v, esperado ErrNotFound", err)
+	}
+}
+
+func TestDNSSyncServiceApplyConflict(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewDNSSyncService(NewDNSService(client))
+	srv.AddDNS("exemplo.com", gocachetest.DNSRecord{Name: "www", Type: "A", Content: "192.0.2.1", TTL: "300"})
+	ctx := context.Background()
+
+	plan, err := service.Plan(ctx, "exemplo.com", models.DNSSyncRequest{Records: []models.DNSSyncRecord{
+		{Name: "www", Type: "A", Content: "192.0.2.2", TTL: 300},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.AddDNS("exemplo.com", gocachetest.DNSRecord{Name: "novo", Type: "A", Content: "192.0.2.9", TTL: "300"})
+	if _, err := service.Apply(ctx, "exemplo.com", models.DNSSyncApplyRequest{PlanID: plan.ID}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Apply() = %v, esperado ErrConflict", err)
+	}
+	for _, request := range srv.Requests() {
+		if request.Method != http.MethodGet {
+			t.Errorf("plano em conflito enviou %s %s", request.Method, request.Path)
+		}
+	}
+	if _, err := service.Apply(ctx, "exemplo.com", models.DNSSyncApplyRequest{PlanID: plan.ID}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Apply() após o conflito = %v, esperado ErrNotFound", err)
+	}
+}
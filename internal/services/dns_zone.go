@@ -0,0 +1,476 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+const (
+	// defaultZoneTTL é usado quando o arquivo de zona não define $TTL nem TTL explícito
+	defaultZoneTTL = 3600
+	// defaultMXPriority é assumida para registros MX cadastrados sem prioridade
+	defaultMXPriority = "10"
+	// maxTXTChunk é o tamanho máximo de cada string de um registro TXT (RFC 1035, seção 3.3)
+	maxTXTChunk = 255
+)
+
+// ExportZone gera o arquivo de zona (RFC 1035) com os registros DNS atuais do domínio
+func (s *DNSService) ExportZone(ctx context.Context, domain string) (string, error) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return "", fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+
+	list, err := s.ListDNS(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	return FormatZone(domain, list.Response.Records), nil
+}
+
+// ImportZone compara o arquivo de zona com os registros atuais do domínio. Em modo dry run
+// apenas o diff é retornado; caso contrário as criações, atualizações e exclusões são
+// aplicadas e o resultado de cada uma é registrado na resposta.
+func (s *DNSService) ImportZone(ctx context.Context, domain string, zone io.Reader, dryRun bool) (*models.DNSZoneImportResponse, error) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return nil, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+
+	desired, skipped, err := ParseZone(zone, domain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	list, err := s.ListDNS(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.DNSZoneImportResponse{
+		Domain:  domain,
+		DryRun:  dryRun,
+		Changes: diffRecords(domain, list.Response.Records, desired, false),
+		Skipped: skipped,
+	}
+
+	if !dryRun {
+		s.applyChanges(ctx, domain, result.Changes, 1)
+	}
+
+	result.Summary = summarizeChanges(result.Changes)
+	return result, nil
+}
+
+// FormatZone gera um arquivo de zona no formato de apresentação da RFC 1035
+func FormatZone(domain string, records []models.DNSRecord) string {
+	domain = normalizeDomain(domain)
+	sorted := make([]models.DNSRecord, len(records))
+	copy(sorted, records)
+	for i := range sorted {
+		sorted[i].Name = relativeRecordName(sorted[i].Name, domain)
+		sorted[i].Type = sorted[i].Type.Normalize()
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			// O apex vem sempre primeiro
+			return sorted[i].Name == "@" || (sorted[j].Name != "@" && sorted[i].Name < sorted[j].Name)
+		}
+		return sorted[i].Type < sorted[j].Type
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "; Zona %s exportada da Gocache\n", domain)
+	fmt.Fprintf(&b, "$ORIGIN %s.\n\n", domain)
+
+	w := tabwriter.NewWriter(&b, 0, 8, 1, '\t', 0)
+	for _, record := range sorted {
+		ttl := int(record.TTL)
+		if ttl <= 0 {
+			ttl = defaultZoneTTL
+		}
+		fmt.Fprintf(w, "%s\t%d\tIN\t%s\t%s\n", record.Name, ttl, record.Type, formatRData(record.Type, record.Content))
+	}
+	w.Flush()
+
+	return b.String()
+}
+
+// formatRData converte o conteúdo de um registro para o formato de apresentação
+func formatRData(recordType models.DNSRecordType, content string) string {
+	content = strings.TrimSpace(content)
+	switch recordType {
+	case models.DNSTypeCNAME, models.DNSTypeNS:
+		return fqdn(content)
+	case models.DNSTypeMX:
+		fields := strings.Fields(content)
+		if len(fields) == 1 {
+			return defaultMXPriority + " " + fqdn(fields[0])
+		}
+		if len(fields) == 2 {
+			return fields[0] + " " + fqdn(fields[1])
+		}
+	case models.DNSTypeSRV:
+		fields := strings.Fields(content)
+		if len(fields) == 4 {
+			fields[3] = fqdn(fields[3])
+			return strings.Join(fields, " ")
+		}
+	case models.DNSTypeTXT:
+		return quoteTXT(content)
+	case models.DNSTypeCAA:
+		fields := strings.SplitN(content, " ", 3)
+		if len(fields) == 3 && !strings.HasPrefix(fields[2], `"`) {
+			return fields[0] + " " + fields[1] + " " + strconv.Quote(fields[2])
+		}
+	}
+	return content
+}
+
+func fqdn(host string) string {
+	if host == "." || strings.HasSuffix(host, ".") {
+		return host
+	}
+	return host + "."
+}
+
+// quoteTXT divide o texto em strings de até 255 bytes, escapando aspas e barras
+func quoteTXT(content string) string {
+	var chunks []string
+	for len(content) > maxTXTChunk {
+		chunks = append(chunks, content[:maxTXTChunk])
+		content = content[maxTXTChunk:]
+	}
+	chunks = append(chunks, content)
+
+	for i, chunk := range chunks {
+		chunk = strings.ReplaceAll(chunk, `\`, `\\`)
+		chunks[i] = `"` + strings.ReplaceAll(chunk, `"`, `\"`) + `"`
+	}
+	return strings.Join(chunks, " ")
+}
+
+// zoneToken é uma palavra de uma linha do arquivo de zona
+type zoneToken struct {
+	text   string
+	quoted bool
+}
+
+// zoneEntry é uma linha lógica do arquivo de zona (parênteses podem juntar várias linhas físicas)
+type zoneEntry struct {
+	line          int
+	inheritsOwner bool
+	tokens        []zoneToken
+}
+
+// ParseZone lê um arquivo de zona no formato BIND. Os nomes dos registros retornados são
+// relativos ao domínio; tipos não suportados pela Gocache, NS do apex e nomes fora da zona
+// são ignorados e listados à parte. Erros de sintaxe ou de conteúdo indicam a linha.
+func ParseZone(r io.Reader, domain string) ([]DesiredRecord, []models.DNSZoneSkipped, error) {
+	domain = normalizeDomain(domain)
+	entries, err := scanZone(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	origin := domain
+	defaultTTL := -1
+	lastTTL := -1
+	lastOwner := ""
+	var records []DesiredRecord
+	skipped := []models.DNSZoneSkipped{}
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		tokens := entry.tokens
+
+		if first := tokens[0].text; !tokens[0].quoted && strings.HasPrefix(first, "$") {
+			switch strings.ToUpper(first) {
+			case "$ORIGIN":
+				if len(tokens) != 2 {
+					return nil, nil, fmt.Errorf("linha %d: $ORIGIN requer um nome", entry.line)
+				}
+				origin = canonicalHost(resolveZoneName(tokens[1].text, origin))
+			case "$TTL":
+				if len(tokens) != 2 {
+					return nil, nil, fmt.Errorf("linha %d: $TTL requer um valor", entry.line)
+				}
+				ttl, err := parseZoneTTL(tokens[1].text)
+				if err != nil {
+					return nil, nil, fmt.Errorf("linha %d: %v", entry.line, err)
+				}
+				defaultTTL = ttl
+			default:
+				return nil, nil, fmt.Errorf("linha %d: diretiva %s não suportada", entry.line, first)
+			}
+			continue
+		}
+
+		owner := lastOwner
+		if !entry.inheritsOwner {
+			owner = canonicalHost(resolveZoneName(tokens[0].text, origin))
+			tokens = tokens[1:]
+		}
+		if owner == "" {
+			return nil, nil, fmt.Errorf("linha %d: registro sem nome", entry.line)
+		}
+		lastOwner = owner
+
+		// TTL e classe são opcionais e podem aparecer em qualquer ordem antes do tipo
+		ttl := -1
+		for len(tokens) > 0 && !tokens[0].quoted {
+			if isZoneClass(tokens[0].text) {
+				tokens = tokens[1:]
+				continue
+			}
+			if ttl < 0 && len(tokens[0].text) > 0 && tokens[0].text[0] >= '0' && tokens[0].text[0] <= '9' {
+				value, err := parseZoneTTL(tokens[0].text)
+				if err != nil {
+					return nil, nil, fmt.Errorf("linha %d: %v", entry.line, err)
+				}
+				ttl = value
+				tokens = tokens[1:]
+				continue
+			}
+			break
+		}
+		if len(tokens) == 0 {
+			return nil, nil, fmt.Errorf("linha %d: tipo do registro ausente", entry.line)
+		}
+
+		recordType := models.DNSRecordType(tokens[0].text).Normalize()
+		rdata := tokens[1:]
+		switch {
+		case ttl >= 0:
+			lastTTL = ttl
+		case defaultTTL >= 0:
+			ttl = defaultTTL
+		case lastTTL >= 0:
+			ttl = lastTTL
+		default:
+			ttl = defaultZoneTTL
+		}
+
+		name := relativeRecordName(owner, domain)
+		if owner != domain && !strings.HasSuffix(owner, "."+domain) {
+			skipped = append(skipped, models.DNSZoneSkipped{Line: entry.line, Name: owner, Type: string(recordType), Reason: "nome fora da zona " + domain})
+			continue
+		}
+		if !recordType.Valid() {
+			skipped = append(skipped, models.DNSZoneSkipped{Line: entry.line, Name: name, Type: string(recordType), Reason: "tipo de registro não suportado pela Gocache"})
+			continue
+		}
+		if isApexNS(name, recordType) {
+			skipped = append(skipped, models.DNSZoneSkipped{Line: entry.line, Name: name, Type: string(recordType), Reason: "registros NS do apex são gerenciados pela Gocache"})
+			continue
+		}
+
+		content, err := zoneContent(recordType, rdata, origin)
+		if err != nil {
+			return nil, nil, fmt.Errorf("linha %d: %v", entry.line, err)
+		}
+		if err := models.ValidateDNSName(name); err != nil {
+			return nil, nil, fmt.Errorf("linha %d: %v", entry.line, err)
+		}
+		if err := models.ValidateDNSContent(recordType, content); err != nil {
+			return nil, nil, fmt.Errorf("linha %d: %v", entry.line, err)
+		}
+
+		// Entradas repetidas no arquivo são consideradas uma só
+		key := recordKey(name, recordType) + "|" + canonicalContent(recordType, content)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		records = append(records, DesiredRecord{
+			DNSRecord: models.DNSRecord{
+				Name:    name,
+				Type:    recordType,
+				Content: content,
+				TTL:     models.FlexInt(ttl),
+			},
+			Line: entry.line,
+		})
+	}
+
+	return records, skipped, nil
+}
+
+// zoneContent converte os dados (RDATA) de um registro para o formato de conteúdo da Gocache
+func zoneContent(recordType models.DNSRecordType, rdata []zoneToken, origin string) (string, error) {
+	texts := make([]string, len(rdata))
+	for i, token := range rdata {
+		texts[i] = token.text
+	}
+
+	want := map[models.DNSRecordType]int{
+		models.DNSTypeA: 1, models.DNSTypeAAAA: 1, models.DNSTypeCNAME: 1, models.DNSTypeNS: 1,
+		models.DNSTypeMX: 2, models.DNSTypeCAA: 3, models.DNSTypeSRV: 4,
+	}
+	if n, ok := want[recordType]; ok && len(rdata) != n {
+		return "", fmt.Errorf("registro %s requer %d campo(s) de dados, encontrado(s) %d", recordType, n, len(rdata))
+	}
+	if recordType == models.DNSTypeTXT && len(rdata) == 0 {
+		return "", fmt.Errorf("registro TXT sem conteúdo")
+	}
+
+	switch recordType {
+	case models.DNSTypeCNAME, models.DNSTypeNS:
+		return canonicalHost(resolveZoneName(texts[0], origin)), nil
+	case models.DNSTypeMX:
+		return texts[0] + " " + canonicalHost(resolveZoneName(texts[1], origin)), nil
+	case models.DNSTypeSRV:
+		if texts[3] != "." {
+			texts[3] = canonicalHost(resolveZoneName(texts[3], origin))
+		}
+		return strings.Join(texts, " "), nil
+	case models.DNSTypeTXT:
+		// Strings múltiplas de um TXT são concatenadas (RFC 7208, seção 3.3)
+		return strings.Join(texts, ""), nil
+	case models.DNSTypeCAA:
+		return texts[0] + " " + strings.ToLower(texts[1]) + " " + strconv.Quote(texts[2]), nil
+	default:
+		return texts[0], nil
+	}
+}
+
+// resolveZoneName completa nomes relativos com a origem atual
+func resolveZoneName(name, origin string) string {
+	switch {
+	case name == "@":
+		return origin + "."
+	case strings.HasSuffix(name, "."):
+		return name
+	default:
+		return name + "." + origin + "."
+	}
+}
+
+func isZoneClass(token string) bool {
+	switch strings.ToUpper(token) {
+	case "IN", "CH", "HS", "CS":
+		return true
+	}
+	return false
+}
+
+// parseZoneTTL aceita segundos ("3600") ou o formato com unidades do BIND ("1h30m", "1d")
+func parseZoneTTL(value string) (int, error) {
+	if ttl, err := strconv.Atoi(value); err == nil {
+		if ttl < 0 {
+			return 0, fmt.Errorf("TTL inválido %q", value)
+		}
+		return ttl, nil
+	}
+
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total, number := 0, ""
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+		multiplier, ok := units[c|0x20]
+		if !ok || number == "" {
+			return 0, fmt.Errorf("TTL inválido %q", value)
+		}
+		n, _ := strconv.Atoi(number)
+		total += n * multiplier
+		number = ""
+	}
+	if number != "" {
+		return 0, fmt.Errorf("TTL inválido %q", value)
+	}
+	return total, nil
+}
+
+// scanZone divide o arquivo em linhas lógicas, tratando comentários, aspas e parênteses
+func scanZone(r io.Reader) ([]zoneEntry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var entries []zoneEntry
+	var current *zoneEntry
+	depth := 0
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		if depth == 0 {
+			current = &zoneEntry{
+				line:          lineNumber,
+				inheritsOwner: len(line) > 0 && (line[0] == ' ' || line[0] == '\t'),
+			}
+		}
+
+		for i := 0; i < len(line); {
+			c := line[i]
+			switch {
+			case c == ';':
+				i = len(line)
+			case c == ' ' || c == '\t' || c == '\r':
+				i++
+			case c == '(':
+				depth++
+				i++
+			case c == ')':
+				if depth == 0 {
+					return nil, fmt.Errorf("linha %d: parêntese fechado sem abertura", lineNumber)
+				}
+				depth--
+				i++
+			case c == '"':
+				var b strings.Builder
+				i++
+				closed := false
+				for i < len(line) {
+					if line[i] == '\\' && i+1 < len(line) {
+						b.WriteByte(line[i+1])
+						i += 2
+						continue
+					}
+					if line[i] == '"' {
+						closed = true
+						i++
+						break
+					}
+					b.WriteByte(line[i])
+					i++
+				}
+				if !closed {
+					return nil, fmt.Errorf("linha %d: aspas não fechadas", lineNumber)
+				}
+				current.tokens = append(current.tokens, zoneToken{text: b.String(), quoted: true})
+			default:
+				start := i
+				for i < len(line) && !strings.ContainsRune(" \t\r;()\"", rune(line[i])) {
+					i++
+				}
+				current.tokens = append(current.tokens, zoneToken{text: line[start:i]})
+			}
+		}
+
+		if depth == 0 && len(current.tokens) > 0 {
+			entries = append(entries, *current)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de zona: %v", err)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("linha %d: parêntese aberto sem fechamento", current.line)
+	}
+	return entries, nil
+}
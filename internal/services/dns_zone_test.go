@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+// zoneRecord resume um registro para comparação nos testes
+func zoneRecord(name string, recordType models.DNSRecordType, content string, ttl int) models.DNSRecord {
+	return models.DNSRecord{Name: name, Type: recordType, Content: content, TTL: models.FlexInt(ttl)}
+}
+
+func desiredRecords(records []DesiredRecord) []models.DNSRecord {
+	out := make([]models.DNSRecord, len(records))
+	for i, record := range records {
+		out[i] = record.DNSRecord
+	}
+	return out
+}
+
+func TestParseZone(t *testing.T) {
+	zone := `; zona de teste
+$ORIGIN exemplo.com.
+$TTL 1h
+@               IN  NS     ns1.gocache.com.br.
+@               IN  SOA    ns1.gocache.com.br. admin.exemplo.com. ( 2024010101 7200 3600
+                           1209600 300 )
+@           300 IN  A      192.0.2.1
+                IN  MX     10 mail
+                IN  MX     20 mail.outro.com.
+www         IN  600     CNAME  @
+www.exemplo.com.    A      192.0.2.9
+txt             TXT    "v=spf1 include:_spf.exemplo.com" " -all"
+aspas           TXT    "diz \"oi\"; fim"
+_sip._tcp       SRV    10 5 5060 sip
+caa             CAA    0 ISSUE "letsencrypt.org"
+fora.net.       A      192.0.2.2
+$ORIGIN app.exemplo.com.
+api             A      192.0.2.3
+`
+	records, skipped, err := ParseZone(strings.NewReader(zone), "Exemplo.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.DNSRecord{
+		zoneRecord("@", "A", "192.0.2.1", 300),
+		zoneRecord("@", "MX", "10 mail.exemplo.com", 3600),
+		zoneRecord("@", "MX", "20 mail.outro.com", 3600),
+		zoneRecord("www", "CNAME", "exemplo.com", 600),
+		zoneRecord("www", "A", "192.0.2.9", 3600),
+		zoneRecord("txt", "TXT", "v=spf1 include:_spf.exemplo.com -all", 3600),
+		zoneRecord("aspas", "TXT", `diz "oi"; fim`, 3600),
+		zoneRecord("_sip._tcp", "SRV", "10 5 5060 sip.exemplo.com", 3600),
+		zoneRecord("caa", "CAA", `0 issue "letsencrypt.org"`, 3600),
+		zoneRecord("api.app", "A", "192.0.2.3", 3600),
+	}
+	if got := desiredRecords(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("registros:\n%+v\nesperado:\n%+v", got, want)
+	}
+
+	reasons := map[string]string{}
+	for _, s := range skipped {
+		reasons[s.Type+" "+s.Name] = s.Reason
+	}
+	for _, key := range []string{"NS @", "SOA @", "A fora.net"} {
+		if reasons[key] == "" {
+			t.Errorf("entrada %q não foi ignorada; ignoradas: %+v", key, skipped)
+		}
+	}
+}
+
+func TestParseZoneErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		zone string
+		want string
+	}{
+		{"aspas não fechadas", "txt TXT \"aberto\n", "linha 1: aspas não fechadas"},
+		{"parêntese sem fechamento", "@ SOA a. b. (1 2\n3 4\n", "linha 1: parêntese aberto"},
+		{"parêntese sem abertura", "@ A 192.0.2.1 )\n", "linha 1: parêntese fechado"},
+		{"diretiva desconhecida", "$INCLUDE outro.zone\n", "linha 1: diretiva $INCLUDE"},
+		{"TTL inválido", "$TTL 1x\n", "linha 1: TTL inválido"},
+		{"conteúdo inválido", "\n\nwww A 999.0.0.1\n", "linha 3: registro A requer um endereço IPv4"},
+		{"campos de dados", "@ MX mail\n", "linha 1: registro MX requer 2 campo(s)"},
+		{"sem tipo", "www 300\n", "linha 1: tipo do registro ausente"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseZone(strings.NewReader(tt.zone), "exemplo.com")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseZone() = %v, esperado erro contendo %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestZoneRoundTrip(t *testing.T) {
+	records := []models.DNSRecord{
+		zoneRecord("exemplo.com", "A", "192.0.2.1", 300),
+		zoneRecord("www.exemplo.com", "CNAME", "exemplo.com", 0),
+		zoneRecord("@", "MX", "mail.exemplo.com", 3600),
+		zoneRecord("@", "AAAA", "2001:db8::1", 3600),
+		zoneRecord("txt", "TXT", `v=spf1 "aspas" \ barra `+strings.Repeat("x", 300), 3600),
+		zoneRecord("caa", "CAA", "0 issue letsencrypt.org", 3600),
+		zoneRecord("_sip._tcp", "SRV", "10 5 5060 sip.exemplo.com", 3600),
+		zoneRecord("sub", "NS", "ns.outro.com", 3600),
+	}
+
+	zone := FormatZone("exemplo.com", records)
+	if !strings.Contains(zone, "$ORIGIN exemplo.com.\n") {
+		t.Errorf("zona sem $ORIGIN:\n%s", zone)
+	}
+	parsed, skipped, err := ParseZone(strings.NewReader(zone), "exemplo.com")
+	if err != nil {
+		t.Fatalf("ParseZone() = %v\n%s", err, zone)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("entradas ignoradas: %+v", skipped)
+	}
+
+	// O MX ganha a prioridade padrão, TTL zero vira o padrão e o CAA ganha aspas
+	want := []models.DNSRecord{
+		zoneRecord("@", "A", "192.0.2.1", 300),
+		zoneRecord("@", "AAAA", "2001:db8::1", 3600),
+		zoneRecord("@", "MX", "10 mail.exemplo.com", 3600),
+		zoneRecord("_sip._tcp", "SRV", "10 5 5060 sip.exemplo.com", 3600),
+		zoneRecord("caa", "CAA", `0 issue "letsencrypt.org"`, 3600),
+		zoneRecord("sub", "NS", "ns.outro.com", 3600),
+		zoneRecord("txt", "TXT", records[4].Content, 3600),
+		zoneRecord("www", "CNAME", "exemplo.com", defaultZoneTTL),
+	}
+	if got := desiredRecords(parsed); !reflect.DeepEqual(got, want) {
+		t.Errorf("registros:\n%+v\nesperado:\n%+v\nzona:\n%s", got, want, zone)
+	}
+}
+
+func TestImportZoneDryRun(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewDNSService(client)
+	srv.AddDNS("exemplo.com",
+		gocachetest.DNSRecord{Name: "exemplo.com", Type: "NS", Content: "ns1.gocache.com.br", TTL: "3600"},
+		gocachetest.DNSRecord{Name: "www", Type: "A", Content: "192.0.2.1", TTL: "300"},
+		gocachetest.DNSRecord{Name: "mail", Type: "A", Content: "192.0.2.2", TTL: "300"},
+		gocachetest.DNSRecord{Name: "antigo", Type: "TXT", Content: "remover", TTL: "300"},
+	)
+	zone := `$TTL 300
+www   A      192.0.2.1
+mail  A      192.0.2.3
+blog  CNAME  www
+`
+
+	result, err := service.ImportZone(context.Background(), "exemplo.com", strings.NewReader(zone), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.DNSChangeSummary{Create: 1, Update: 1, Delete: 1, Unchanged: 1}
+	if result.Summary != want {
+		t.Errorf("resumo = %+v, esperado %+v", result.Summary, want)
+	}
+	for _, request := range srv.Requests() {
+		if request.Method != http.MethodGet {
+			t.Errorf("dry run enviou %s %s", request.Method, request.Path)
+		}
+	}
+
+	if _, err := service.ImportZone(context.Background(), "exemplo.com", strings.NewReader(zone), false); err != nil {
+		t.Fatal(err)
+	}
+	exported, err := service.ExportZone(context.Background(), "exemplo.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"blog\t300\tIN\tCNAME\twww.exemplo.com.", "mail\t300\tIN\tA\t192.0.2.3", "@\t3600\tIN\tNS\tns1.gocache.com.br."} {
+		if !strings.Contains(exported, line) {
+			t.Errorf("zona exportada não contém %q:\n%s", line, exported)
+		}
+	}
+	if strings.Contains(exported, "antigo") {
+		t.Errorf("registro removido ainda exportado:\n%s", exported)
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// DomainDeletionService remove um domínio junto com tudo o que foi configurado para ele:
+// Smart Rules, redirecionamentos, registros DNS e os mapeamentos locais do proxy
+type DomainDeletionService struct {
+	Domains   *DomainService
+	DNS       *DNSService
+	Rules     *SmartRuleRewriteService
+	Redirects *RedirectService
+	// Proxy é opcional; sem ele, os mapeamentos locais não são removidos
+	Proxy *ProxyService
+}
+
+// domainDeletionTask é um item a remover e a ação que o remove
+type domainDeletionTask struct {
+	item   models.DomainDeletionItem
+	delete func(ctx context.Context) error
+}
+
+// NewDomainDeletionService cria uma nova instância de DomainDeletionService
+func NewDomainDeletionService(domains *DomainService, dns *DNSService, rules *SmartRuleRewriteService, redirects *RedirectService, proxy *ProxyService) *DomainDeletionService {
+	return &DomainDeletionService{
+		Domains:   domains,
+		DNS:       dns,
+		Rules:     rules,
+		Redirects: redirects,
+		Proxy:     proxy,
+	}
+}
+
+// DeleteDomain lista e remove as regras, redirecionamentos, registros DNS e mapeamentos do
+// domínio e, por fim, o próprio domínio. Um item que falhe não impede a remoção dos demais,
+// mas o domínio só é removido se todos tiverem sido removidos. Com dryRun, apenas retorna o
+// que seria removido.
+func (s *DomainDeletionService) DeleteDomain(ctx context.Context, name string, dryRun bool) (*models.DomainDeletionResponse, error) {
+	name = normalizeDomain(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+	if _, err := s.Domains.GetDomainSettings(ctx, name); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.plan(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.DomainDeletionResponse{
+		Status: true,
+		Domain: name,
+		DryRun: dryRun,
+		Items:  make([]models.DomainDeletionItem, 0, len(tasks)+1),
+	}
+	var failure error
+	for _, task := range tasks {
+		item := task.item
+		if !dryRun {
+			if err := task.delete(ctx); err != nil {
+				log.Printf("Erro ao remover %s %s do domínio %s: %v", item.Type, item.ID, name, err)
+				item.Error = err.Error()
+				if failure == nil {
+					failure = err
+				}
+			} else {
+				item.Deleted = true
+			}
+		}
+		countDeletionItem(&response.Summary, item)
+		response.Items = append(response.Items, item)
+	}
+
+	domainItem := models.DomainDeletionItem{Type: models.DomainItemDomain, ID: name}
+	switch {
+	case dryRun:
+	case failure != nil:
+		domainItem.Error = "não removido: há itens do domínio que não puderam ser removidos"
+	default:
+		if err := s.Domains.DeleteDomainByName(ctx, name); err != nil {
+			domainItem.Error = err.Error()
+			failure = err
+		} else {
+			domainItem.Deleted = true
+			response.Deleted = true
+		}
+	}
+	response.Items = append(response.Items, domainItem)
+
+	if failure != nil {
+		response.Status = false
+		response.Error = fmt.Sprintf("remoção do domínio %s incompleta: %d item(ns) com erro", name, response.Summary.Failed)
+		return response, fmt.Errorf("remoção do domínio %s incompleta: %w", name, failure)
+	}
+	if !dryRun {
+		log.Printf("Domínio %s removido com %d regra(s), %d redirecionamento(s), %d registro(s) DNS e %d mapeamento(s)",
+			name, response.Summary.Rules, response.Summary.Redirects, response.Summary.DNS, response.Summary.Mappings)
+	}
+	return response, nil
+}
+
+// plan lista o que será removido, na ordem de remoção: regras, redirecionamentos, registros
+// DNS e mapeamentos locais. Os itens já listados são removidos pelas variantes que não os
+// consultam de novo nem publicam eventos, já que o cache de um domínio removido não precisa
+// ser expirado item a item.
+func (s *DomainDeletionService) plan(ctx context.Context, name string) ([]domainDeletionTask, error) {
+	var tasks []domainDeletionTask
+
+	rules, err := s.Rules.ListRewriteRules(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules.Response.Rules {
+		id := rule.ID
+		description := strings.TrimSpace(rule.Match.Host + rule.Match.RequestURI)
+		tasks = append(tasks, domainDeletionTask{
+			item: models.DomainDeletionItem{Type: models.DomainItemRule, ID: id, Description: description},
+			delete: func(ctx context.Context) error {
+				_, err := s.Rules.deleteRule(ctx, name, id)
+				return err
+			},
+		})
+	}
+
+	redirects, err := s.Redirects.ListRedirects(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, redirect := range redirects.Response {
+		id := redirect.ID
+		tasks = append(tasks, domainDeletionTask{
+			item: models.DomainDeletionItem{Type: models.DomainItemRedirect, ID: strconv.Itoa(id),
+				Description: redirect.Source + " -> " + redirect.Destination},
+			delete: func(ctx context.Context) error {
+				_, err := s.Redirects.deleteRedirect(ctx, name, id)
+				return err
+			},
+		})
+	}
+
+	records, err := s.DNS.ListDNS(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records.Response.Records {
+		if isApexNS(relativeRecordName(record.Name, name), record.Type.Normalize()) {
+			// Os NS do apex não são removidos, como na sincronização de DNS
+			continue
+		}
+		recordID := record.RecordID
+		tasks = append(tasks, domainDeletionTask{
+			item: models.DomainDeletionItem{Type: models.DomainItemDNS, ID: string(recordID),
+				Description: fmt.Sprintf("%s %s %s", record.Name, record.Type, record.Content)},
+			delete: func(ctx context.Context) error {
+				id, err := recordID.Int()
+				if err != nil {
+					return fmt.Errorf("ID de registro DNS inválido %q", recordID)
+				}
+				_, err = s.DNS.deleteDNS(ctx, id)
+				return err
+			},
+		})
+	}
+
+	if s.Proxy != nil {
+		for _, mapping := range s.Proxy.GetAllMappings() {
+			host := strings.TrimPrefix(mapping.Domain, "*.")
+			if host != name && !strings.HasSuffix(host, "."+name) {
+				continue
+			}
+			domain := mapping.Domain
+			tasks = append(tasks, domainDeletionTask{
+				item: models.DomainDeletionItem{Type: models.DomainItemMapping, ID: domain,
+					Description: string(mapping.Mode) + " -> " + mapping.Destination},
+				delete: func(context.Context) error {
+					return s.Proxy.deleteMapping(domain, 0)
+				},
+			})
+		}
+	}
+	return tasks, nil
+}
+
+// countDeletionItem soma o item ao resumo
+func countDeletionItem(summary *models.DomainDeletionSummary, item models.DomainDeletionItem) {
+	if item.Error != "" {
+		summary.Failed++
+	}
+	switch item.Type {
+	case models.DomainItemRule:
+		summary.Rules++
+	case models.DomainItemRedirect:
+		summary.Redirects++
+	case models.DomainItemDNS:
+		summary.DNS++
+	case models.DomainItemMapping:
+		summary.Mappings++
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/store"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+// TestDomainDeletionCascade verifica que a remoção do domínio não consulta de novo cada item
+// nem publica eventos de expiração para ele
+func TestDomainDeletionCascade(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	srv.AddDomain("a.com", nil)
+	for i := 0; i < 3; i++ {
+		srv.AddRule("a.com", gocachetest.Rule{Match: url.Values{"match[host]": {"a.com"}}})
+		srv.AddRedirect(gocachetest.Redirect{Domain: "a.com", Source: "/antiga", Destination: "/nova"})
+		srv.AddDNS("a.com", gocachetest.DNSRecord{Name: "www", Type: "A", Content: "1.1.1.1", TTL: "300"})
+	}
+
+	mappingStore, err := store.New(store.KindMemory, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := NewProxyService(mappingStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := proxy.AddMapping(models.DomainMapping{Domain: "www.a.com", Destination: "https://origem.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []Event
+	bus := NewEventBus()
+	bus.Subscribe(func(event Event) { events = append(events, event) })
+	dns, rules, redirects := NewDNSService(client), NewSmartRuleRewriteService(client), NewRedirectService(client)
+	dns.Events, rules.Events, redirects.Events, proxy.Events = bus, bus, bus, bus
+
+	service := NewDomainDeletionService(NewDomainService(client), dns, rules, redirects, proxy)
+	response, err := service.DeleteDomain(context.Background(), "a.com", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.Deleted || response.Summary.Rules != 3 || response.Summary.Redirects != 3 || response.Summary.DNS != 3 || response.Summary.Mappings != 1 {
+		t.Errorf("resposta = %+v", response)
+	}
+	if len(events) != 0 {
+		t.Errorf("eventos publicados = %+v, esperado nenhum", events)
+	}
+
+	// Uma listagem por tipo de item e uma remoção por item, sem consultas repetidas
+	gets := 0
+	for _, request := range srv.Requests() {
+		if request.Method == http.MethodGet {
+			gets++
+		}
+	}
+	if gets != 4 {
+		t.Errorf("consultas à Gocache = %d, esperado 4 (domínio, regras, redirecionamentos e DNS)", gets)
+	}
+}
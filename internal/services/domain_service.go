@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/renatoroquejani/poc-gocache/internal/models"
@@ -17,41 +18,41 @@ func NewDomainService(client *gocache.Client) *DomainService {
 	return &DomainService{client: client}
 }
 
-// CreateDomain creates a new domain in GoCache
-func (s *DomainService) CreateDomain(req models.DomainCreateRequest) (map[string]interface{}, error) {
+// CreateDomain creates a new domain in GoCache. Every request field is sent; settings
+// that are not provided fall back to DefaultDomainSettings.
+func (s *DomainService) CreateDomain(ctx context.Context, req models.DomainCreateRequest) (map[string]interface{}, error) {
 	var result map[string]interface{}
 	endpoint := fmt.Sprintf("/domain/%s", req.Name)
 
-	formData := map[string]string{
-		"cache_ttl":  "86400",
-		"waf_status": "false",
-		"cdn_mode":   "cname",
+	settings := createDomainSettings(req)
+	if err := validateDomainSettings(settings); err != nil {
+		return nil, err
 	}
 
-	_, err := s.client.Post(endpoint, formData, &result)
+	_, err := s.client.PostContext(ctx, endpoint, settings, &result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create domain: %w", err)
 	}
 	return result, nil
 }
 
-// DeleteDomain deletes a domain in GoCache
-func (s *DomainService) DeleteDomain(domainID int) error {
+// DeleteDomainByName deletes a domain in GoCache by name
+func (s *DomainService) DeleteDomainByName(ctx context.Context, name string) error {
 	var result map[string]interface{}
-	endpoint := fmt.Sprintf("/domains/%d", domainID)
-	_, err := s.client.DeleteSimple(endpoint, &result)
+	endpoint := fmt.Sprintf("/domain/%s", name)
+	_, err := s.client.DeleteSimpleContext(ctx, endpoint, &result)
 	if err != nil {
-		return fmt.Errorf("failed to delete domain: %w", err)
+		return fmt.Errorf("falha ao remover domínio %s: %w", name, err)
 	}
 	return nil
 }
 
 // ListDomains lista todos os domínios disponíveis na GoCache
-func (s *DomainService) ListDomains() (*models.DomainListResponse, error) {
+func (s *DomainService) ListDomains(ctx context.Context) (*models.DomainListResponse, error) {
 	var response models.DomainListResponse
 	endpoint := "/domain"
 	
-	_, err := s.client.Get(endpoint, &response)
+	_, err := s.client.GetContext(ctx, endpoint, &response)
 	if err != nil {
 		return nil, fmt.Errorf("falha ao listar domínios: %w", err)
 	}
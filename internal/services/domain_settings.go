@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// Valores padrão das configurações de um domínio novo
+const (
+	DefaultDomainCacheTTL = 86400
+	DefaultDomainCDNMode  = "cname"
+)
+
+var (
+	domainCDNModes = []string{"cname", "ns"}
+	domainSSLModes = []string{"off", "partial", "full", "full_strict"}
+)
+
+// DefaultDomainSettings retorna as configurações usadas na criação de um domínio quando a
+// requisição não as informa
+func DefaultDomainSettings() models.DomainSettings {
+	enabled, waf := true, false
+	ttl, cdnMode := DefaultDomainCacheTTL, DefaultDomainCDNMode
+	return models.DomainSettings{
+		Enabled:   &enabled,
+		CacheTTL:  &ttl,
+		WAFStatus: &waf,
+		CDNMode:   &cdnMode,
+	}
+}
+
+// GetDomainSettings retorna as configurações atuais do domínio na GoCache
+func (s *DomainService) GetDomainSettings(ctx context.Context, name string) (*models.DomainSettings, error) {
+	name = normalizeDomain(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+
+	var response struct {
+		Response map[string]interface{} `json:"response"`
+	}
+	endpoint := fmt.Sprintf("/domain/%s", name)
+	if _, err := s.client.GetContext(ctx, endpoint, &response); err != nil {
+		return nil, fmt.Errorf("falha ao consultar configurações do domínio %s: %w", name, err)
+	}
+
+	settings, err := domainSettingsFromResponse(response.Response)
+	if err != nil {
+		return nil, fmt.Errorf("configurações do domínio %s inválidas: %w", name, err)
+	}
+	return settings, nil
+}
+
+// UpdateDomainSettings altera apenas as configurações informadas e retorna as configurações
+// resultantes
+func (s *DomainService) UpdateDomainSettings(ctx context.Context, name string, settings models.DomainSettings) (*models.DomainSettings, error) {
+	name = normalizeDomain(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+	if settings == (models.DomainSettings{}) {
+		return nil, fmt.Errorf("%w: nenhuma configuração informada", ErrInvalidRequest)
+	}
+	if err := validateDomainSettings(settings); err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	endpoint := fmt.Sprintf("/domain/%s", name)
+	if _, err := s.client.PutContext(ctx, endpoint, settings, &result); err != nil {
+		return nil, fmt.Errorf("falha ao alterar configurações do domínio %s: %w", name, err)
+	}
+	return s.GetDomainSettings(ctx, name)
+}
+
+// createDomainSettings monta as configurações enviadas na criação do domínio a partir da
+// requisição, completando com os padrões
+func createDomainSettings(req models.DomainCreateRequest) models.DomainSettings {
+	settings := DefaultDomainSettings()
+	if req.Origin != "" {
+		settings.Origin = &req.Origin
+	}
+	if req.Description != "" {
+		settings.Description = &req.Description
+	}
+	if req.Enabled != nil {
+		settings.Enabled = req.Enabled
+	}
+	if req.CacheTTL != nil {
+		settings.CacheTTL = req.CacheTTL
+	}
+	if req.WAFStatus != nil {
+		settings.WAFStatus = req.WAFStatus
+	}
+	if req.CDNMode != nil {
+		settings.CDNMode = req.CDNMode
+	}
+	settings.SSLMode = req.SSLMode
+	settings.Compression = req.Compression
+	return settings
+}
+
+// validateDomainSettings valida os valores informados; a mesma validação das tags de
+// binding, para chamadas que não passam pelos handlers (ex: onboarding)
+func validateDomainSettings(settings models.DomainSettings) error {
+	switch {
+	case settings.CacheTTL != nil && *settings.CacheTTL < 0:
+		return fmt.Errorf("%w: cache_ttl não pode ser negativo", ErrInvalidRequest)
+	case settings.CDNMode != nil && !slices.Contains(domainCDNModes, *settings.CDNMode):
+		return fmt.Errorf("%w: cdn_mode deve ser um de %s", ErrInvalidRequest, strings.Join(domainCDNModes, ", "))
+	case settings.SSLMode != nil && !slices.Contains(domainSSLModes, *settings.SSLMode):
+		return fmt.Errorf("%w: ssl_mode deve ser um de %s", ErrInvalidRequest, strings.Join(domainSSLModes, ", "))
+	}
+	return nil
+}
+
+// domainSettingsFromResponse lê as configurações da resposta da GoCache, que pode trazer
+// números e booleanos como texto
+func domainSettingsFromResponse(response map[string]interface{}) (*models.DomainSettings, error) {
+	settings := &models.DomainSettings{}
+	text := func(key string) (string, bool) {
+		value, ok := response[key]
+		if !ok || value == nil {
+			return "", false
+		}
+		return strings.TrimSpace(fmt.Sprint(value)), true
+	}
+	flag := func(key string) (*bool, error) {
+		value, ok := text(key)
+		if !ok || value == "" {
+			return nil, nil
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("valor inválido para %s: %q", key, value)
+		}
+		return &parsed, nil
+	}
+
+	if value, ok := text("origin"); ok {
+		settings.Origin = &value
+	}
+	if value, ok := text("description"); ok {
+		settings.Description = &value
+	}
+	if value, ok := text("cdn_mode"); ok && value != "" {
+		settings.CDNMode = &value
+	}
+	if value, ok := text("ssl_mode"); ok && value != "" {
+		settings.SSLMode = &value
+	}
+	if value, ok := text("cache_ttl"); ok && value != "" {
+		ttl, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("valor inválido para cache_ttl: %q", value)
+		}
+		seconds := int(ttl)
+		settings.CacheTTL = &seconds
+	}
+
+	var err error
+	if settings.Enabled, err = flag("enabled"); err != nil {
+		return nil, err
+	}
+	if settings.WAFStatus, err = flag("waf_status"); err != nil {
+		return nil, err
+	}
+	if settings.Compression, err = flag("gzip_status"); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
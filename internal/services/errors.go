@@ -0,0 +1,14 @@
+package services
+
+import "errors"
+
+var (
+	// ErrInvalidRequest indica que a requisição foi rejeitada pelo serviço antes de chegar à Gocache
+	ErrInvalidRequest = errors.New("requisição inválida")
+	// ErrNotFound indica que um recurso mantido pelo próprio serviço não existe
+	ErrNotFound = errors.New("não encontrado")
+	// ErrConflict indica que a operação conflita com o estado atual do recurso
+	ErrConflict = errors.New("conflito")
+	// ErrPreconditionFailed indica que a versão informada (If-Match) não é a versão atual do recurso
+	ErrPreconditionFailed = errors.New("versão desatualizada")
+)
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifica uma alteração de configuração que pode deixar conteúdo desatualizado
+// no cache
+type EventType string
+
+// Eventos emitidos pelos serviços
+const (
+	EventRuleCreated     EventType = "rule.created"
+	EventRuleUpdated     EventType = "rule.updated"
+	EventRuleDeleted     EventType = "rule.deleted"
+	EventRedirectChanged EventType = "redirect.changed"
+	EventDNSChanged      EventType = "dns.changed"
+	EventMappingChanged  EventType = "mapping.changed"
+)
+
+// EventTypes lista todos os eventos emitidos pelos serviços
+var EventTypes = []EventType{
+	EventRuleCreated, EventRuleUpdated, EventRuleDeleted,
+	EventRedirectChanged, EventDNSChanged, EventMappingChanged,
+}
+
+// Event descreve uma alteração feita por um serviço
+type Event struct {
+	Type EventType
+	// Domain é o domínio (zona) da Gocache; vazio quando o serviço não o conhece, como na
+	// alteração de um mapeamento do proxy
+	Domain string
+	// Host é o host afetado (ex: o host de uma Smart Rule ou o nome de um registro DNS)
+	Host string
+	// Path é o padrão de path afetado (ex: o request_uri de uma regra); vazio afeta o host todo
+	Path string
+	// ID identifica o objeto alterado (regra, registro ou mapeamento)
+	ID        string
+	Requester string
+	At        time.Time
+}
+
+// EventBus entrega os eventos dos serviços aos assinantes. Um *EventBus nil descarta os
+// eventos, então os serviços podem publicar sem verificar se há assinantes.
+type EventBus struct {
+	mutex       sync.RWMutex
+	subscribers []func(Event)
+}
+
+// NewEventBus cria um EventBus sem assinantes
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registra uma função chamada a cada evento. As funções são chamadas na goroutine
+// de quem publica e não devem bloquear.
+func (b *EventBus) Subscribe(fn func(Event)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.subscribers = append(b.subscribers, fn)
+}
+
+// Publish entrega o evento a todos os assinantes
+func (b *EventBus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+
+	b.mutex.RLock()
+	subscribers := b.subscribers
+	b.mutex.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
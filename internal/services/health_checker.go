@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// Padrões da verificação de saúde dos destinos dos mapeamentos
+const (
+	DefaultHealthInterval = 30 * time.Second
+	DefaultHealthTimeout  = 5 * time.Second
+)
+
+// StatusRange é um intervalo fechado de status HTTP considerados saudáveis
+type StatusRange struct {
+	Min, Max int
+}
+
+// HealthCheckOptions configura o HealthChecker
+type HealthCheckOptions struct {
+	Interval time.Duration
+	Timeout  time.Duration
+	// ExpectedStatus lista os status aceitos; vazio aceita 200-399
+	ExpectedStatus []StatusRange
+}
+
+// HealthCheckOptionsFromEnv lê PROXY_HEALTH_INTERVAL (0 desativa a verificação),
+// PROXY_HEALTH_TIMEOUT e PROXY_HEALTH_EXPECTED_STATUS (ex: "200-299,301")
+func HealthCheckOptionsFromEnv() (HealthCheckOptions, error) {
+	opts := HealthCheckOptions{Interval: DefaultHealthInterval, Timeout: DefaultHealthTimeout}
+
+	if value := os.Getenv("PROXY_HEALTH_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return opts, fmt.Errorf("valor inválido para PROXY_HEALTH_INTERVAL: %w", err)
+		}
+		opts.Interval = interval
+	}
+	if value := os.Getenv("PROXY_HEALTH_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return opts, fmt.Errorf("valor inválido para PROXY_HEALTH_TIMEOUT: %q", value)
+		}
+		opts.Timeout = timeout
+	}
+	if value := os.Getenv("PROXY_HEALTH_EXPECTED_STATUS"); value != "" {
+		ranges, err := ParseStatusRanges(value)
+		if err != nil {
+			return opts, fmt.Errorf("valor inválido para PROXY_HEALTH_EXPECTED_STATUS: %w", err)
+		}
+		opts.ExpectedStatus = ranges
+	}
+	return opts, nil
+}
+
+// ParseStatusRanges interpreta uma lista de status e intervalos, ex: "200-299,301,302"
+func ParseStatusRanges(value string) ([]StatusRange, error) {
+	var ranges []StatusRange
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		low, high, isRange := strings.Cut(part, "-")
+		if !isRange {
+			high = low
+		}
+		minStatus, err1 := strconv.Atoi(strings.TrimSpace(low))
+		maxStatus, err2 := strconv.Atoi(strings.TrimSpace(high))
+		if err1 != nil || err2 != nil || minStatus < 100 || maxStatus > 599 || minStatus > maxStatus {
+			return nil, fmt.Errorf("status inválido %q", part)
+		}
+		ranges = append(ranges, StatusRange{Min: minStatus, Max: maxStatus})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("nenhum status informado")
+	}
+	return ranges, nil
+}
+
+// HealthChecker verifica periodicamente os destinos principais e alternativos dos
+// mapeamentos. O ProxyService usa o resultado para escolher o primeiro destino saudável.
+type HealthChecker struct {
+	service *ProxyService
+	options HealthCheckOptions
+	client  *http.Client
+
+	mutex   sync.RWMutex
+	targets map[string]models.TargetHealth // chave: URL do destino
+}
+
+// NewHealthChecker cria o verificador e o associa ao serviço de proxy
+func NewHealthChecker(service *ProxyService, opts HealthCheckOptions) *HealthChecker {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultHealthInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultHealthTimeout
+	}
+	if len(opts.ExpectedStatus) == 0 {
+		opts.ExpectedStatus = []StatusRange{{Min: 200, Max: 399}}
+	}
+
+	checker := &HealthChecker{
+		service: service,
+		options: opts,
+		client: &http.Client{
+			Timeout: opts.Timeout,
+			// O status do próprio destino é o que interessa, sem seguir redirecionamentos
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		targets: make(map[string]models.TargetHealth),
+	}
+
+	service.mutex.Lock()
+	service.health = checker
+	service.mutex.Unlock()
+	return checker
+}
+
+// Start verifica os destinos imediatamente e depois a cada intervalo, até o contexto ser cancelado
+func (h *HealthChecker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(h.options.Interval)
+		defer ticker.Stop()
+		for {
+			h.CheckAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// CheckAll verifica em paralelo todos os destinos dos mapeamentos e descarta o estado dos
+// destinos que não são mais usados
+func (h *HealthChecker) CheckAll(ctx context.Context) {
+	urls := make(map[string]bool)
+	for _, mapping := range h.service.GetAllMappings() {
+		for _, target := range mappingTargets(mapping) {
+			urls[target] = true
+		}
+	}
+
+	results := make(chan models.TargetHealth, len(urls))
+	var wg sync.WaitGroup
+	for target := range urls {
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+			results <- h.check(ctx, target)
+		}(target)
+	}
+	wg.Wait()
+	close(results)
+
+	targets := make(map[string]models.TargetHealth, len(urls))
+	for result := range results {
+		targets[result.URL] = result
+	}
+
+	h.mutex.Lock()
+	for target, previous := range h.targets {
+		if current, ok := targets[target]; ok && current.Healthy != previous.Healthy {
+			if current.Healthy {
+				log.Printf("Destino %s voltou a responder", target)
+			} else {
+				log.Printf("Destino %s falhou na verificação de saúde: %s", target, healthProblem(current))
+			}
+		}
+	}
+	h.targets = targets
+	h.mutex.Unlock()
+}
+
+// check faz um GET no destino e compara o status com os esperados
+func (h *HealthChecker) check(ctx context.Context, target string) models.TargetHealth {
+	result := models.TargetHealth{URL: target}
+	start := time.Now()
+	defer func() {
+		now := time.Now()
+		result.CheckedAt = &now
+		result.LatencyMS = now.Sub(start).Milliseconds()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("User-Agent", "poc-gocache-health-check")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	result.StatusCode = resp.StatusCode
+	for _, expected := range h.options.ExpectedStatus {
+		if resp.StatusCode >= expected.Min && resp.StatusCode <= expected.Max {
+			result.Healthy = true
+		}
+	}
+	return result
+}
+
+// Healthy indica se o destino está saudável. Destinos ainda não verificados são
+// considerados saudáveis, para não desviar tráfego antes da primeira verificação.
+func (h *HealthChecker) Healthy(target string) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	state, ok := h.targets[target]
+	return !ok || state.Healthy
+}
+
+// Report retorna a saúde dos destinos de cada mapeamento e o destino ativo de cada um
+func (h *HealthChecker) Report() []models.MappingHealth {
+	mappings := h.service.GetAllMappings()
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	report := make([]models.MappingHealth, 0, len(mappings))
+	for _, mapping := range mappings {
+		item := models.MappingHealth{Domain: mapping.Domain, Active: h.activeLocked(mapping)}
+		for i, target := range mappingTargets(mapping) {
+			state, ok := h.targets[target]
+			if !ok {
+				state = models.TargetHealth{URL: target, Healthy: true}
+			}
+			state.Role = "fallback"
+			if i == 0 {
+				state.Role = "primary"
+			}
+			item.Targets = append(item.Targets, state)
+		}
+		report = append(report, item)
+	}
+	return report
+}
+
+// HealthReport retorna a saúde dos destinos de cada mapeamento. enabled é false quando
+// nenhum HealthChecker foi associado ao serviço.
+func (s *ProxyService) HealthReport() (report []models.MappingHealth, enabled bool) {
+	s.mutex.RLock()
+	health := s.health
+	s.mutex.RUnlock()
+
+	if health == nil {
+		return nil, false
+	}
+	return health.Report(), true
+}
+
+// active retorna o primeiro destino saudável do mapeamento
+func (h *HealthChecker) active(mapping models.DomainMapping) string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.activeLocked(mapping)
+}
+
+// activeLocked retorna o primeiro destino saudável; se nenhum estiver, mantém o principal
+func (h *HealthChecker) activeLocked(mapping models.DomainMapping) string {
+	for _, target := range mappingTargets(mapping) {
+		if state, ok := h.targets[target]; !ok || state.Healthy {
+			return target
+		}
+	}
+	return mapping.Destination
+}
+
+// mappingTargets retorna o destino principal seguido dos alternativos
+func mappingTargets(mapping models.DomainMapping) []string {
+	return append([]string{mapping.Destination}, mapping.Fallbacks...)
+}
+
+func healthProblem(state models.TargetHealth) string {
+	if state.Error != "" {
+		return state.Error
+	}
+	return fmt.Sprintf("status %d", state.StatusCode)
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID gera um ID aleatório de 32 caracteres hexadecimais, usado nos planos de
+// sincronização de DNS, nos jobs de expiração e nos onboardings
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// FallbackDomain é o domínio do mapeamento usado quando nenhum outro corresponde ao host
+const FallbackDomain = "*"
+
+// NormalizeHost converte o host de uma requisição para a forma usada nas buscas: sem porta,
+// em letras minúsculas, sem ponto final e com nomes internacionalizados em punycode.
+// Endereços IPv6 são aceitos com ou sem colchetes ("[::1]:8080", "::1").
+func NormalizeHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	} else if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+
+	host = strings.TrimSuffix(host, ".")
+	if host == "" {
+		return "", fmt.Errorf("host vazio")
+	}
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", fmt.Errorf("host inválido %q: %v", host, err)
+	}
+	return ascii, nil
+}
+
+// normalizeMappingDomain normaliza o domínio de um mapeamento, preservando o prefixo
+// de wildcard ("*.exemplo.com") e o mapeamento padrão ("*")
+func normalizeMappingDomain(domain string) (string, error) {
+	domain = strings.TrimSpace(domain)
+	if domain == FallbackDomain {
+		return domain, nil
+	}
+	if suffix, ok := strings.CutPrefix(domain, "*."); ok {
+		normalized, err := NormalizeHost(suffix)
+		if err != nil {
+			return "", err
+		}
+		return "*." + normalized, nil
+	}
+	if strings.Contains(domain, "*") {
+		return "", fmt.Errorf("wildcard só é permitido no início do domínio (ex: *.exemplo.com): %q", domain)
+	}
+	return NormalizeHost(domain)
+}
+
+// mappingEntry é um mapeamento da tabela com as expressões das regras regex já compiladas
+type mappingEntry struct {
+	mapping models.DomainMapping
+	// patterns tem o mesmo índice de mapping.Rules; nil nas regras que não são regex
+	patterns []*regexp.Regexp
+}
+
+// newMappingEntry compila as regras regex do mapeamento. Uma expressão inválida (gravada
+// por outro processo sem validação) é registrada no log e a regra é ignorada.
+func newMappingEntry(mapping models.DomainMapping) mappingEntry {
+	entry := mappingEntry{mapping: mapping}
+	for i, rule := range mapping.Rules {
+		if rule.Match != models.PathMatchRegex {
+			continue
+		}
+		compiled, err := compilePathPattern(rule.Path)
+		if err != nil {
+			log.Printf("Regra %d do mapeamento de %s ignorada: expressão regular inválida: %v", i+1, mapping.Domain, err)
+			continue
+		}
+		if entry.patterns == nil {
+			entry.patterns = make([]*regexp.Regexp, len(mapping.Rules))
+		}
+		entry.patterns[i] = compiled
+	}
+	return entry
+}
+
+// pattern retorna a expressão compilada da regra i, ou nil
+func (e mappingEntry) pattern(i int) *regexp.Regexp {
+	if i < len(e.patterns) {
+		return e.patterns[i]
+	}
+	return nil
+}
+
+// mappingTable indexa os mapeamentos por host. A precedência é determinística: o domínio
+// exato vence, depois o wildcard de sufixo mais específico e, por fim, o mapeamento padrão.
+type mappingTable struct {
+	exact    map[string]mappingEntry
+	wildcard map[string]mappingEntry // chave: sufixo sem o "*."
+	fallback *mappingEntry
+}
+
+func newMappingTable() *mappingTable {
+	return &mappingTable{
+		exact:    make(map[string]mappingEntry),
+		wildcard: make(map[string]mappingEntry),
+	}
+}
+
+// put adiciona ou substitui um mapeamento cujo domínio já está normalizado
+func (t *mappingTable) put(mapping models.DomainMapping) {
+	entry := newMappingEntry(mapping)
+	switch {
+	case mapping.Domain == FallbackDomain:
+		t.fallback = &entry
+	case strings.HasPrefix(mapping.Domain, "*."):
+		t.wildcard[strings.TrimPrefix(mapping.Domain, "*.")] = entry
+	default:
+		t.exact[mapping.Domain] = entry
+	}
+}
+
+// remove exclui o mapeamento do domínio normalizado, indicando se ele existia
+func (t *mappingTable) remove(domain string) bool {
+	switch {
+	case domain == FallbackDomain:
+		existed := t.fallback != nil
+		t.fallback = nil
+		return existed
+	case strings.HasPrefix(domain, "*."):
+		suffix := strings.TrimPrefix(domain, "*.")
+		_, existed := t.wildcard[suffix]
+		delete(t.wildcard, suffix)
+		return existed
+	default:
+		_, existed := t.exact[domain]
+		delete(t.exact, domain)
+		return existed
+	}
+}
+
+// lookup busca o mapeamento de um host normalizado. O custo é proporcional ao número de
+// labels do host, não ao número de mapeamentos.
+func (t *mappingTable) lookup(host string) (mappingEntry, bool) {
+	if entry, ok := t.exact[host]; ok {
+		return entry, true
+	}
+
+	// Wildcards não se aplicam a IPs
+	if net.ParseIP(host) == nil {
+		for suffix := host; ; {
+			dot := strings.IndexByte(suffix, '.')
+			if dot < 0 {
+				break
+			}
+			suffix = suffix[dot+1:]
+			if entry, ok := t.wildcard[suffix]; ok {
+				return entry, true
+			}
+		}
+	}
+
+	if t.fallback != nil {
+		return *t.fallback, true
+	}
+	return mappingEntry{}, false
+}
+
+// list retorna os mapeamentos ordenados por domínio
+func (t *mappingTable) list() []models.DomainMapping {
+	mappings := make([]models.DomainMapping, 0, len(t.exact)+len(t.wildcard)+1)
+	for _, entry := range t.exact {
+		mappings = append(mappings, entry.mapping)
+	}
+	for _, entry := range t.wildcard {
+		mappings = append(mappings, entry.mapping)
+	}
+	if t.fallback != nil {
+		mappings = append(mappings, t.fallback.mapping)
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Domain < mappings[j].Domain })
+	return mappings
+}
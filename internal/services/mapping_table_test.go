@@ -0,0 +1,129 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{host: "Loja.COM", want: "loja.com"},
+		{host: "loja.com.", want: "loja.com"},
+		{host: "loja.com:8080", want: "loja.com"},
+		{host: " loja.com ", want: "loja.com"},
+		{host: "ÁÇ.com.", want: "xn--1cam.com"},
+		{host: "münchen.de:443", want: "xn--mnchen-3ya.de"},
+		{host: "xn--mnchen-3ya.de", want: "xn--mnchen-3ya.de"},
+		{host: "[::1]:80", want: "::1"},
+		{host: "[::1]", want: "::1"},
+		{host: "::1", want: "::1"},
+		{host: "[2001:DB8::1]:8443", want: "2001:db8::1"},
+		{host: "10.0.0.1:80", want: "10.0.0.1"},
+		{host: "", wantErr: true},
+		{host: ".", wantErr: true},
+		{host: "loja com.br", wantErr: true},
+		{host: "*.loja.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			got, err := NormalizeHost(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeHost(%q) erro = %v, esperado erro = %v", tt.host, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeHost(%q) = %q, esperado %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeMappingDomain(t *testing.T) {
+	tests := []struct {
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{domain: "*", want: "*"},
+		{domain: "WWW.Loja.com.", want: "www.loja.com"},
+		{domain: "*.Loja.COM.", want: "*.loja.com"},
+		{domain: "*.ÁÇ.com", want: "*.xn--1cam.com"},
+		{domain: "www.*.loja.com", wantErr: true},
+		{domain: "loja.*", wantErr: true},
+		{domain: "*loja.com", wantErr: true},
+		{domain: "*.*.loja.com", wantErr: true},
+		{domain: "**", wantErr: true},
+		{domain: "*.", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			got, err := normalizeMappingDomain(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeMappingDomain(%q) erro = %v, esperado erro = %v", tt.domain, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeMappingDomain(%q) = %q, esperado %q", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappingTableLookup(t *testing.T) {
+	domains := []string{"a.b.loja.com", "*.b.loja.com", "*.loja.com", "*"}
+	tests := []struct {
+		host string
+		want string
+	}{
+		// O domínio exato vence os wildcards que também o cobririam
+		{"a.b.loja.com", "a.b.loja.com"},
+		// O wildcard mais longo vence o mais curto
+		{"x.b.loja.com", "*.b.loja.com"},
+		{"c.x.b.loja.com", "*.b.loja.com"},
+		{"x.loja.com", "*.loja.com"},
+		// O wildcard não cobre o próprio sufixo
+		{"b.loja.com", "*.loja.com"},
+		{"loja.com", "*"},
+		{"outra.com", "*"},
+		// Wildcards não se aplicam a IPs
+		{"10.0.0.1", "*"},
+	}
+
+	// A precedência não depende da ordem de cadastro
+	orders := map[string][]string{
+		"mais específico primeiro": domains,
+		"padrão primeiro":          {domains[3], domains[2], domains[1], domains[0]},
+	}
+	for name, order := range orders {
+		table := newMappingTable()
+		for _, domain := range order {
+			table.put(models.DomainMapping{Domain: domain, Destination: "https://" + domain})
+		}
+		for _, tt := range tests {
+			t.Run(name+" "+tt.host, func(t *testing.T) {
+				entry, ok := table.lookup(tt.host)
+				if !ok || entry.mapping.Domain != tt.want {
+					t.Errorf("lookup(%q) = %q, %v, esperado %q", tt.host, entry.mapping.Domain, ok, tt.want)
+				}
+			})
+		}
+	}
+
+	// Sem o mapeamento padrão, um host sem correspondência não é encontrado
+	table := newMappingTable()
+	table.put(models.DomainMapping{Domain: "*.loja.com"})
+	if entry, ok := table.lookup("outra.com"); ok {
+		t.Errorf("lookup(outra.com) = %q, esperado nenhum", entry.mapping.Domain)
+	}
+	if !table.remove("*.loja.com") || table.remove("*.loja.com") {
+		t.Error("remove(*.loja.com) deveria indicar a remoção só na primeira vez")
+	}
+	if _, ok := table.lookup("x.loja.com"); ok {
+		t.Error("lookup(x.loja.com) encontrou mapeamento removido")
+	}
+}
@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/jsonfile"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// DefaultMaxOnboardings é o número de onboardings mantidos; ao passar do limite, os mais
+// antigos já encerrados são descartados
+const DefaultMaxOnboardings = 500
+
+// onboardingStepTimeout limita cada etapa e cada compensação da saga
+const onboardingStepTimeout = 2 * time.Minute
+
+// OnboardingService cadastra um domínio com o registro DNS e a Smart Rule de uma landing
+// page como uma saga: as etapas são executadas em ordem e, se uma falhar, as já concluídas
+// são desfeitas em ordem inversa. O estado de cada etapa é gravado para que um onboarding
+// interrompido ou desfeito possa ser retomado.
+type OnboardingService struct {
+	Domains *DomainService
+	DNS     *DNSService
+	Rules   *SmartRuleRewriteService
+
+	path string
+
+	mutex     sync.Mutex
+	workflows map[string]*models.OnboardingWorkflow
+	order     []string
+	running   map[string]bool // domínios com onboarding em execução neste processo
+}
+
+// onboardingStep é uma etapa da saga e a ação que a desfaz
+type onboardingStep struct {
+	run        func(ctx context.Context, wf models.OnboardingWorkflow) (string, error)
+	compensate func(ctx context.Context, wf models.OnboardingWorkflow, resourceID string) error
+}
+
+// NewOnboardingService cria o serviço de onboarding. Quando path é informado, os
+// onboardings são gravados nesse arquivo JSON a cada etapa e recarregados ao iniciar; os
+// que estavam em execução ficam como interrupted.
+func NewOnboardingService(domains *DomainService, dns *DNSService, rules *SmartRuleRewriteService, path string) (*OnboardingService, error) {
+	s := &OnboardingService{
+		Domains:   domains,
+		DNS:       dns,
+		Rules:     rules,
+		path:      path,
+		workflows: make(map[string]*models.OnboardingWorkflow),
+		running:   make(map[string]bool),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	var workflows []models.OnboardingWorkflow
+	if err := jsonfile.Load(path, &workflows); err != nil {
+		return nil, fmt.Errorf("erro ao carregar onboardings: %w", err)
+	}
+	for i := range workflows {
+		wf := &workflows[i]
+		if wf.Status == models.OnboardingRunning {
+			wf.Status = models.OnboardingInterrupted
+			wf.Error = "execução interrompida antes do fim"
+			for j := range wf.Steps {
+				if wf.Steps[j].Status == models.OnboardingStepRunning {
+					wf.Steps[j].Status = models.OnboardingStepFailed
+					wf.Steps[j].Error = wf.Error
+				}
+			}
+		}
+		s.workflows[wf.ID] = wf
+		s.order = append(s.order, wf.ID)
+	}
+	return s, nil
+}
+
+// Start valida a requisição, registra o onboarding e executa as etapas. Se alguma falhar,
+// as anteriores são desfeitas e o erro da etapa é retornado junto com o estado final.
+func (s *OnboardingService) Start(ctx context.Context, req models.OnboardingRequest) (*models.OnboardingWorkflow, error) {
+	req.Domain = normalizeDomain(req.Domain)
+	if req.Domain == "" {
+		return nil, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+	req.DNS.Domain = req.Domain
+	req.DNS.Type = req.DNS.Type.Normalize()
+	if err := models.ValidateDNSContent(req.DNS.Type, req.DNS.Content); err != nil {
+		return nil, fmt.Errorf("%w: registro DNS inválido: %v", ErrInvalidRequest, err)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar ID do onboarding: %w", err)
+	}
+	now := time.Now().UTC()
+	wf := &models.OnboardingWorkflow{
+		ID:          id,
+		Domain:      req.Domain,
+		Status:      models.OnboardingRunning,
+		Request:     req,
+		RequestedBy: requesterFromContext(ctx),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, name := range []string{models.OnboardingStepDomain, models.OnboardingStepDNS, models.OnboardingStepRule} {
+		wf.Steps = append(wf.Steps, models.OnboardingStep{Name: name, Status: models.OnboardingStepPending})
+	}
+
+	s.mutex.Lock()
+	if s.running[wf.Domain] {
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("%w: já existe um onboarding em execução para %s", ErrConflict, wf.Domain)
+	}
+	s.running[wf.Domain] = true
+	s.workflows[id] = wf
+	s.order = append(s.order, id)
+	s.pruneLocked()
+	s.saveLocked()
+	s.mutex.Unlock()
+
+	return s.execute(ctx, id)
+}
+
+// Resume retoma um onboarding interrompido ou desfeito. As etapas cujo recurso ainda existe
+// (concluídas ou que não puderam ser desfeitas) são mantidas; as demais são executadas
+// novamente.
+func (s *OnboardingService) Resume(ctx context.Context, id string) (*models.OnboardingWorkflow, error) {
+	s.mutex.Lock()
+	wf, ok := s.workflows[id]
+	if !ok {
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("%w: onboarding %q", ErrNotFound, id)
+	}
+	switch {
+	case wf.Status == models.OnboardingCompleted:
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("%w: onboarding %s já foi concluído", ErrConflict, id)
+	case s.running[wf.Domain]:
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("%w: já existe um onboarding em execução para %s", ErrConflict, wf.Domain)
+	}
+	s.running[wf.Domain] = true
+	wf.Status = models.OnboardingRunning
+	wf.Error = ""
+	wf.UpdatedAt = time.Now().UTC()
+	for i := range wf.Steps {
+		step := &wf.Steps[i]
+		if step.Status == models.OnboardingStepCompensationFailed {
+			// O recurso não foi removido e continua valendo
+			step.Status = models.OnboardingStepCompleted
+		}
+		if step.Status != models.OnboardingStepCompleted {
+			step.Status = models.OnboardingStepPending
+			step.ResourceID = ""
+		}
+		step.Error = ""
+		step.CompensationError = ""
+	}
+	s.saveLocked()
+	s.mutex.Unlock()
+
+	return s.execute(ctx, id)
+}
+
+// Get retorna o onboarding com o ID informado
+func (s *OnboardingService) Get(id string) (*models.OnboardingWorkflow, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	wf, ok := s.workflows[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: onboarding %q", ErrNotFound, id)
+	}
+	c := copyOnboarding(wf)
+	return &c, nil
+}
+
+// List retorna os onboardings, do mais recente para o mais antigo, opcionalmente filtrados
+// pelo domínio
+func (s *OnboardingService) List(domain string, limit int) []models.OnboardingWorkflow {
+	domain = normalizeDomain(domain)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	workflows := []models.OnboardingWorkflow{}
+	for i := len(s.order) - 1; i >= 0; i-- {
+		wf := s.workflows[s.order[i]]
+		if domain != "" && wf.Domain != domain {
+			continue
+		}
+		workflows = append(workflows, copyOnboarding(wf))
+		if limit > 0 && len(workflows) == limit {
+			break
+		}
+	}
+	return workflows
+}
+
+// execute roda as etapas pendentes e, na primeira falha, desfaz as concluídas. A saga não
+// herda o cancelamento de ctx: se o cliente desconectar no meio, as etapas seguem (ou são
+// desfeitas) até o fim, cada uma limitada por onboardingStepTimeout.
+func (s *OnboardingService) execute(ctx context.Context, id string) (*models.OnboardingWorkflow, error) {
+	ctx = context.WithoutCancel(ctx)
+	defer func() {
+		s.mutex.Lock()
+		delete(s.running, s.workflows[id].Domain)
+		s.mutex.Unlock()
+	}()
+
+	steps := s.steps()
+	failedAt := -1
+	var failure error
+	for i := range steps {
+		wf := s.snapshot(id)
+		if wf.Steps[i].Status == models.OnboardingStepCompleted {
+			continue
+		}
+
+		s.updateStep(id, i, func(step *models.OnboardingStep) {
+			now := time.Now().UTC()
+			step.Status = models.OnboardingStepRunning
+			step.Attempts++
+			step.StartedAt = &now
+			step.FinishedAt = nil
+		})
+		stepCtx, cancel := context.WithTimeout(ctx, onboardingStepTimeout)
+		resourceID, err := steps[i].run(stepCtx, wf)
+		cancel()
+		s.updateStep(id, i, func(step *models.OnboardingStep) {
+			now := time.Now().UTC()
+			step.FinishedAt = &now
+			step.ResourceID = resourceID
+			if err != nil {
+				step.Status = models.OnboardingStepFailed
+				step.Error = err.Error()
+				return
+			}
+			step.Status = models.OnboardingStepCompleted
+		})
+		if err != nil {
+			log.Printf("Onboarding %s de %s: etapa %s falhou: %v", id, wf.Domain, wf.Steps[i].Name, err)
+			failedAt, failure = i, err
+			break
+		}
+	}
+
+	if failure == nil {
+		s.finish(id, models.OnboardingCompleted, "")
+		wf := s.snapshot(id)
+		log.Printf("Onboarding %s de %s concluído", id, wf.Domain)
+		return &wf, nil
+	}
+
+	// Desfaz as etapas concluídas, da mais recente para a mais antiga
+	status := models.OnboardingRolledBack
+	for i := failedAt - 1; i >= 0; i-- {
+		wf := s.snapshot(id)
+		if wf.Steps[i].Status != models.OnboardingStepCompleted {
+			continue
+		}
+		stepCtx, cancel := context.WithTimeout(ctx, onboardingStepTimeout)
+		err := steps[i].compensate(stepCtx, wf, wf.Steps[i].ResourceID)
+		cancel()
+		s.updateStep(id, i, func(step *models.OnboardingStep) {
+			if err != nil {
+				step.Status = models.OnboardingStepCompensationFailed
+				step.CompensationError = err.Error()
+				return
+			}
+			step.Status = models.OnboardingStepCompensated
+		})
+		if err != nil {
+			log.Printf("Onboarding %s de %s: erro ao desfazer a etapa %s: %v", id, wf.Domain, wf.Steps[i].Name, err)
+			status = models.OnboardingRollbackFailed
+		}
+	}
+
+	wf := s.snapshot(id)
+	message := fmt.Sprintf("etapa %s falhou: %v", wf.Steps[failedAt].Name, failure)
+	s.finish(id, status, message)
+	wf = s.snapshot(id)
+	return &wf, fmt.Errorf("onboarding de %s não concluído: %w", wf.Domain, failure)
+}
+
+// steps retorna as etapas do onboarding, na mesma ordem de OnboardingWorkflow.Steps
+func (s *OnboardingService) steps() []onboardingStep {
+	return []onboardingStep{
+		{
+			run: func(ctx context.Context, wf models.OnboardingWorkflow) (string, error) {
+				_, err := s.Domains.CreateDomain(ctx, models.DomainCreateRequest{
+					Name:        wf.Domain,
+					Origin:      wf.Request.Origin,
+					Description: wf.Request.Description,
+				})
+				if err != nil {
+					return "", err
+				}
+				return wf.Domain, nil
+			},
+			compensate: func(ctx context.Context, _ models.OnboardingWorkflow, name string) error {
+				return s.Domains.DeleteDomainByName(ctx, name)
+			},
+		},
+		{
+			run: func(ctx context.Context, wf models.OnboardingWorkflow) (string, error) {
+				req := wf.Request.DNS
+				req.Domain = wf.Domain
+				response, err := s.DNS.CreateDNS(ctx, req)
+				if err != nil {
+					return "", err
+				}
+				if len(response.Response.Records) > 0 {
+					return string(response.Response.Records[0].RecordID), nil
+				}
+				return "", nil
+			},
+			compensate: s.deleteOnboardingRecord,
+		},
+		{
+			run: func(ctx context.Context, wf models.OnboardingWorkflow) (string, error) {
+				response, err := s.Rules.CreateSimplifiedRule(ctx, &models.SmartRuleSimplifiedRequest{
+					Domain:       onboardingRuleHost(wf),
+					ParentDomain: wf.Domain,
+					BucketURL:    wf.Request.Rule.BucketURL,
+					AccountID:    wf.Request.Rule.AccountID,
+				})
+				if err != nil {
+					return "", err
+				}
+				return response.Response.ID, nil
+			},
+			compensate: func(ctx context.Context, wf models.OnboardingWorkflow, id string) error {
+				_, err := s.Rules.DeleteRewriteRule(ctx, wf.Domain, id)
+				return err
+			},
+		},
+	}
+}
+
+// deleteOnboardingRecord remove o registro DNS criado no onboarding. Se a Gocache não tiver
+// retornado o ID na criação, o registro é procurado pelo nome, tipo e conteúdo.
+func (s *OnboardingService) deleteOnboardingRecord(ctx context.Context, wf models.OnboardingWorkflow, recordID string) error {
+	if recordID == "" {
+		list, err := s.DNS.ListDNS(ctx, wf.Domain)
+		if err != nil {
+			return err
+		}
+		want := wf.Request.DNS
+		index := slices.IndexFunc(list.Response.Records, func(record models.DNSRecord) bool {
+			return relativeRecordName(record.Name, wf.Domain) == relativeRecordName(want.Name, wf.Domain) && record.Type.Normalize() == want.Type.Normalize() && record.Content == want.Content
+		})
+		if index < 0 {
+			return nil
+		}
+		recordID = string(list.Response.Records[index].RecordID)
+	}
+
+	id, err := models.DNSRecordID(recordID).Int()
+	if err != nil {
+		return fmt.Errorf("ID de registro DNS inválido %q: %w", recordID, err)
+	}
+	_, err = s.DNS.DeleteDNS(ctx, wf.Domain, id)
+	return err
+}
+
+// snapshot retorna uma cópia do estado atual do onboarding
+func (s *OnboardingService) snapshot(id string) models.OnboardingWorkflow {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return copyOnboarding(s.workflows[id])
+}
+
+// updateStep altera uma etapa do onboarding e grava o estado
+func (s *OnboardingService) updateStep(id string, index int, change func(step *models.OnboardingStep)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	wf := s.workflows[id]
+	change(&wf.Steps[index])
+	wf.UpdatedAt = time.Now().UTC()
+	s.saveLocked()
+}
+
+// finish encerra o onboarding com a situação final
+func (s *OnboardingService) finish(id string, status models.OnboardingStatus, message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	wf := s.workflows[id]
+	wf.Status = status
+	wf.Error = message
+	wf.UpdatedAt = time.Now().UTC()
+	s.saveLocked()
+}
+
+// pruneLocked descarta os onboardings encerrados mais antigos acima do limite
+func (s *OnboardingService) pruneLocked() {
+	for i := 0; len(s.order) > DefaultMaxOnboardings && i < len(s.order); {
+		wf := s.workflows[s.order[i]]
+		if wf.Status == models.OnboardingRunning || s.running[wf.Domain] {
+			i++
+			continue
+		}
+		delete(s.workflows, wf.ID)
+		s.order = append(s.order[:i], s.order[i+1:]...)
+	}
+}
+
+// saveLocked grava os onboardings no arquivo configurado. Falhas são apenas registradas no
+// log, para não interromper a saga no meio de uma etapa.
+func (s *OnboardingService) saveLocked() {
+	if s.path == "" {
+		return
+	}
+	workflows := make([]models.OnboardingWorkflow, 0, len(s.order))
+	for _, id := range s.order {
+		workflows = append(workflows, *s.workflows[id])
+	}
+
+	if err := jsonfile.Save(s.path, workflows); err != nil {
+		log.Printf("Erro ao gravar onboardings: %v", err)
+	}
+}
+
+// onboardingRuleHost é o host atendido pela regra: o informado ou o nome completo do
+// registro DNS
+func onboardingRuleHost(wf models.OnboardingWorkflow) string {
+	if host := strings.TrimSpace(wf.Request.Rule.Host); host != "" {
+		return normalizeDomain(host)
+	}
+	return absoluteRecordName(relativeRecordName(wf.Request.DNS.Name, wf.Domain), wf.Domain)
+}
+
+func copyOnboarding(wf *models.OnboardingWorkflow) models.OnboardingWorkflow {
+	c := *wf
+	c.Steps = append([]models.OnboardingStep(nil), wf.Steps...)
+	return c
+}
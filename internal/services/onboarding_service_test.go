@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+// TestOnboardingDetachedFromRequest verifica que a saga e as compensações seguem até o fim
+// mesmo com o contexto da requisição já cancelado (ex: cliente que desconectou)
+func TestOnboardingDetachedFromRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		failRule   bool
+		wantStatus models.OnboardingStatus
+	}{
+		{"concluído", false, models.OnboardingCompleted},
+		{"desfeito", true, models.OnboardingRolledBack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newFakeGocache(t)
+			if tt.failRule {
+				srv.Fail(gocachetest.Failure{Method: http.MethodPost, Path: "/rules/settings/a.com", Status: http.StatusUnprocessableEntity, Message: "invalid rule"})
+			}
+			service, err := NewOnboardingService(NewDomainService(client), NewDNSService(client), NewSmartRuleRewriteService(client), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			wf, err := service.Start(ctx, models.OnboardingRequest{
+				Domain: "a.com",
+				Origin: "origem.a.com",
+				DNS:    models.DNSCreateRequest{Name: "www", Type: "CNAME", Content: "origem.a.com", TTL: 300},
+				Rule:   models.OnboardingRule{BucketURL: "https://bucket.s3.amazonaws.com", AccountID: "123"},
+			})
+			if (err != nil) != tt.failRule {
+				t.Fatalf("Start() erro = %v", err)
+			}
+			if wf.Status != tt.wantStatus {
+				t.Fatalf("status = %s, esperado %s: %+v", wf.Status, tt.wantStatus, wf.Steps)
+			}
+			if tt.failRule && len(srv.DNS("a.com")) != 0 {
+				t.Errorf("registros DNS após desfazer = %+v", srv.DNS("a.com"))
+			}
+		})
+	}
+}
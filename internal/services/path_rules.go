@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// Route é o resultado da resolução de uma requisição: o mapeamento do host e, se alguma
+// casou, a regra de path que define o destino
+type Route struct {
+	Mapping models.DomainMapping
+	// Rule é nil quando nenhuma regra casou e vale o destino padrão do mapeamento
+	Rule        *models.PathRule
+	Destination string
+	Mode        models.MappingMode
+	// Path é o trecho do path da requisição, já escapado, a anexar ao destino; vazio nas
+	// regras exact e regex, cujo destino é usado como está
+	Path string
+}
+
+// compilePathPattern compila a expressão da regra ancorada no início e no fim, para que ela
+// precise casar com o path inteiro. As expressões são compiladas ao validar o mapeamento e
+// ao incluí-lo na tabela (mappingEntry), nunca a cada requisição.
+func compilePathPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// validatePathRules valida as regras de path de um mapeamento
+func validatePathRules(rules []models.PathRule) error {
+	for i, rule := range rules {
+		switch rule.Match {
+		case models.PathMatchPrefix, models.PathMatchExact:
+			if !strings.HasPrefix(rule.Path, "/") {
+				return fmt.Errorf("regra %d: o path deve começar com /: %q", i+1, rule.Path)
+			}
+		case models.PathMatchRegex:
+			if _, err := compilePathPattern(rule.Path); err != nil {
+				return fmt.Errorf("regra %d: expressão regular inválida: %v", i+1, err)
+			}
+		default:
+			return fmt.Errorf("regra %d: tipo de comparação inválido %q (use prefix, exact ou regex)", i+1, rule.Match)
+		}
+
+		if !rule.Mode.Valid() {
+			return fmt.Errorf("regra %d: modo inválido %q (use redirect301, redirect302 ou proxy)", i+1, rule.Mode)
+		}
+		if !isHTTPURL(rule.Destination) {
+			return fmt.Errorf("regra %d: destino deve ser uma URL http(s) absoluta: %q", i+1, rule.Destination)
+		}
+	}
+	return nil
+}
+
+// resolveRoute escolhe o destino da requisição: a primeira regra que casar com o path ou,
+// sem nenhuma, o destino padrão do mapeamento com o path anexado
+func resolveRoute(entry mappingEntry, requestURL *url.URL) Route {
+	mapping := entry.mapping
+	path := requestURL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	for i := range mapping.Rules {
+		rule := &mapping.Rules[i]
+		route := Route{Mapping: mapping, Rule: rule, Destination: rule.Destination, Mode: rule.Mode}
+		if route.Mode == "" {
+			route.Mode = mapping.Mode
+		}
+
+		switch rule.Match {
+		case models.PathMatchExact:
+			if path == rule.Path {
+				return route
+			}
+		case models.PathMatchPrefix:
+			prefix := strings.TrimSuffix(rule.Path, "/")
+			if path == prefix || strings.HasPrefix(path, prefix+"/") || rule.Path == "/" {
+				route.Path = (&url.URL{Path: strings.TrimPrefix(path, prefix)}).EscapedPath()
+				return route
+			}
+		case models.PathMatchRegex:
+			pattern := entry.pattern(i)
+			if pattern == nil {
+				continue
+			}
+			if match := pattern.FindStringSubmatchIndex(path); match != nil {
+				route.Destination = string(pattern.ExpandString(nil, rule.Destination, path, match))
+				return route
+			}
+		}
+	}
+
+	return Route{
+		Mapping:     mapping,
+		Destination: mapping.Destination,
+		Mode:        mapping.Mode,
+		Path:        requestURL.EscapedPath(),
+	}
+}
+
+// isHTTPURL indica se o valor é uma URL http(s) absoluta
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -1,66 +1,324 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/store"
 )
 
 // ProxyService gerencia os mapeamentos de domu00ednios para destinos
 type ProxyService struct {
-	mappings []models.DomainMapping
-	mutex    sync.RWMutex
+	store  store.MappingStore
+	table  *mappingTable
+	health *HealthChecker // opcional; definido por NewHealthChecker
+	mutex  sync.RWMutex
+
+	// Events, quando definido, recebe as alterações de mapeamentos
+	Events *EventBus
 }
 
-// NewProxyService cria uma nova instu00e2ncia do serviu00e7o de proxy
-func NewProxyService() *ProxyService {
-	// Inicializa com alguns mapeamentos pru00e9-definidos
-	return &ProxyService{
-		mappings: []models.DomainMapping{
-			{
-				Domain:      "elizio.sites.kodestech.com.br",
-				Destination: "https://onm-funnel-builder-stg.s3.us-east-2.amazonaws.com/account_pages/bolo-brigadeiro/index.html",
-			},
-			// Adicione mais mapeamentos conforme necessidade
-		},
+// NewProxyService cria uma nova instância do serviço de proxy, carregando os
+// mapeamentos já gravados no store
+func NewProxyService(mappingStore store.MappingStore) (*ProxyService, error) {
+	s := &ProxyService{store: mappingStore}
+	if err := s.Reload(); err != nil {
+		return nil, err
 	}
+	return s, nil
 }
 
-// AddMapping adiciona um novo mapeamento de domu00ednio
-func (s *ProxyService) AddMapping(mapping models.DomainMapping) error {
+// Reload recarrega os mapeamentos do store. É usado para enxergar alterações feitas por
+// outro processo que compartilha o mesmo store.
+func (s *ProxyService) Reload() error {
+	mappings, err := s.store.List()
+	if err != nil {
+		return fmt.Errorf("erro ao carregar mapeamentos: %w", err)
+	}
+
+	table := newMappingTable()
+	for _, mapping := range mappings {
+		domain, err := normalizeMappingDomain(mapping.Domain)
+		if err != nil {
+			log.Printf("Mapeamento ignorado: %v", err)
+			continue
+		}
+		mapping.Domain = domain
+		if mapping.Version == 0 {
+			mapping.Version = 1
+		}
+		table.put(mapping)
+	}
+
+	s.mutex.Lock()
+	s.table = table
+	s.mutex.Unlock()
+	return nil
+}
+
+// StartRefresh recarrega os mapeamentos periodicamente até o contexto ser cancelado
+func (s *ProxyService) StartRefresh(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Reload(); err != nil {
+					log.Printf("Erro ao recarregar mapeamentos: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// MappingFilter filtra e pagina a listagem de mapeamentos. Domain e Destination filtram por
+// trecho (sem diferenciar maiúsculas) e Mode pelo valor exato. Page começa em 1.
+type MappingFilter struct {
+	Domain      string
+	Destination string
+	Mode        models.MappingMode
+	Page        int
+	PageSize    int
+}
+
+// Limites de paginação da listagem de mapeamentos
+const (
+	DefaultMappingPageSize = 50
+	MaxMappingPageSize     = 500
+)
+
+// validateMapping normaliza o domínio e valida o modo, as URLs de destino e as regras de path do mapeamento
+func validateMapping(mapping models.DomainMapping) (models.DomainMapping, error) {
+	domain, err := normalizeMappingDomain(mapping.Domain)
+	if err != nil {
+		return mapping, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	mapping.Domain = domain
+
+	if !mapping.Mode.Valid() {
+		return mapping, fmt.Errorf("%w: modo de mapeamento inválido %q (use redirect301, redirect302 ou proxy)", ErrInvalidRequest, mapping.Mode)
+	}
+	if mapping.Mode == "" {
+		mapping.Mode = models.MappingModeRedirect301
+	}
+	if !isHTTPURL(mapping.Destination) {
+		return mapping, fmt.Errorf("%w: destino deve ser uma URL http(s) absoluta: %q", ErrInvalidRequest, mapping.Destination)
+	}
+	for _, fallback := range mapping.Fallbacks {
+		if !isHTTPURL(fallback) {
+			return mapping, fmt.Errorf("%w: destino alternativo deve ser uma URL http(s) absoluta: %q", ErrInvalidRequest, fallback)
+		}
+	}
+	if err := validatePathRules(mapping.Rules); err != nil {
+		return mapping, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	return mapping, nil
+}
+
+// AddMapping adiciona um novo mapeamento de domínio. O domínio pode ser exato
+// ("site.exemplo.com"), um wildcard de sufixo ("*.exemplo.com") ou o padrão ("*").
+// Se o domínio já tiver mapeamento, retorna ErrConflict; para alterá-lo use UpdateMapping.
+func (s *ProxyService) AddMapping(mapping models.DomainMapping) (models.DomainMapping, error) {
+	mapping, err := validateMapping(mapping)
+	if err != nil {
+		return mapping, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// A gravação condicional no store enxerga também as gravações de outros processos
+	mapping.Version = 1
+	err = s.swap(store.MappingChange{Domain: mapping.Domain, ExpectedVersion: 0, Mapping: &mapping})
+	var versionErr *store.VersionError
+	if errors.As(err, &versionErr) {
+		return mapping, fmt.Errorf("%w: já existe mapeamento para o domínio %s", ErrConflict, mapping.Domain)
+	} else if err != nil {
+		return mapping, err
+	}
+	log.Printf("Novo mapeamento adicionado para o domínio %s: %s", mapping.Domain, mapping.Destination)
+	s.Events.Publish(Event{Type: EventMappingChanged, Host: mapping.Domain})
+	return mapping, nil
+}
+
+// UpdateMapping substitui o destino e o modo de um mapeamento existente. Se expectedVersion
+// for diferente de zero, a alteração só é feita se ela for a versão atual; caso contrário
+// retorna ErrPreconditionFailed.
+func (s *ProxyService) UpdateMapping(domain string, request models.DomainMappingUpdateRequest, expectedVersion int64) (models.DomainMapping, error) {
+	mapping, err := validateMapping(models.DomainMapping{
+		Domain:      domain,
+		Destination: request.Destination,
+		Mode:        request.Mode,
+		Fallbacks:   request.Fallbacks,
+		Rules:       request.Rules,
+	})
+	if err != nil {
+		return mapping, err
+	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	// Verifica se o domu00ednio ju00e1 existe
-	for i, m := range s.mappings {
-		if m.Domain == mapping.Domain {
-			// Atualiza o mapeamento existente
-			s.mappings[i] = mapping
-			log.Printf("Mapeamento atualizado para o domu00ednio %s: %s", mapping.Domain, mapping.Destination)
-			return nil
+	for attempt := 1; ; attempt++ {
+		current, err := s.storedMapping(mapping.Domain)
+		if err != nil {
+			return mapping, err
+		}
+		if expectedVersion != 0 && expectedVersion != current.Version {
+			return current, fmt.Errorf("%w: versão atual do mapeamento de %s é %d", ErrPreconditionFailed, mapping.Domain, current.Version)
+		}
+
+		mapping.Version = current.Version + 1
+		err = s.swap(store.MappingChange{Domain: mapping.Domain, ExpectedVersion: current.Version, Mapping: &mapping})
+		var versionErr *store.VersionError
+		if !errors.As(err, &versionErr) {
+			if err != nil {
+				return mapping, err
+			}
+			break
+		}
+		// Outro processo alterou o mapeamento entre a leitura e a gravação: sem versão
+		// esperada, relê e tenta de novo
+		if expectedVersion != 0 || attempt == maxSwapAttempts {
+			return current, fmt.Errorf("%w: %v", ErrPreconditionFailed, versionErr)
 		}
 	}
+	log.Printf("Mapeamento atualizado para o domínio %s: %s", mapping.Domain, mapping.Destination)
+	s.Events.Publish(Event{Type: EventMappingChanged, Host: mapping.Domain})
+	return mapping, nil
+}
 
-	// Adiciona novo mapeamento
-	s.mappings = append(s.mappings, mapping)
-	log.Printf("Novo mapeamento adicionado para o domu00ednio %s: %s", mapping.Domain, mapping.Destination)
-	return nil
+// ImportMappings grava vários mapeamentos de uma vez. Todos são validados antes de qualquer
+// gravação; se algum for inválido, repetido ou já existir (sem overwrite), nenhum é gravado
+// e os problemas são listados na resposta junto com o erro.
+func (s *ProxyService) ImportMappings(mappings []models.DomainMapping, lines []int, overwrite bool) (*models.MappingImportResponse, error) {
+	response := &models.MappingImportResponse{}
+	line := func(i int) int {
+		if i < len(lines) {
+			return lines[i]
+		}
+		return i + 1
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	valid := make([]models.DomainMapping, 0, len(mappings))
+	seen := make(map[string]int)
+	conflicts := 0
+	for i, mapping := range mappings {
+		normalized, err := validateMapping(mapping)
+		if err != nil {
+			response.Errors = append(response.Errors, models.MappingImportError{Line: line(i), Domain: mapping.Domain, Error: err.Error()})
+			continue
+		}
+		if previous, ok := seen[normalized.Domain]; ok {
+			response.Errors = append(response.Errors, models.MappingImportError{Line: line(i), Domain: mapping.Domain,
+				Error: fmt.Sprintf("domínio repetido na importação (linha %d)", previous)})
+			continue
+		}
+		seen[normalized.Domain] = line(i)
+
+		current, err := s.storedMapping(normalized.Domain)
+		switch {
+		case err == nil && !overwrite:
+			conflicts++
+			response.Errors = append(response.Errors, models.MappingImportError{Line: line(i), Domain: mapping.Domain,
+				Error: "já existe mapeamento para o domínio (use overwrite=true para substituir)"})
+			continue
+		case err == nil:
+			normalized.Version = current.Version + 1
+		case errors.Is(err, ErrNotFound):
+			normalized.Version = 1
+		default:
+			return nil, err
+		}
+		valid = append(valid, normalized)
+	}
+
+	if len(response.Errors) > 0 {
+		// Só conflitos viram 409; qualquer item inválido torna a importação uma requisição inválida
+		if conflicts == len(response.Errors) {
+			return response, fmt.Errorf("%w: %d mapeamentos já existem", ErrConflict, len(response.Errors))
+		}
+		return response, fmt.Errorf("%w: %d mapeamentos rejeitados", ErrInvalidRequest, len(response.Errors))
+	}
+
+	// Todos os mapeamentos são gravados em uma única operação: se outro processo alterar
+	// algum deles desde a validação, nada é gravado
+	changes := make([]store.MappingChange, len(valid))
+	for i := range valid {
+		changes[i] = store.MappingChange{Domain: valid[i].Domain, ExpectedVersion: valid[i].Version - 1, Mapping: &valid[i]}
+	}
+	var versionErr *store.VersionError
+	if err := s.swap(changes...); errors.As(err, &versionErr) {
+		return response, fmt.Errorf("%w: mapeamentos alterados durante a importação: %v", ErrConflict, versionErr)
+	} else if err != nil {
+		return response, err
+	}
+	for _, mapping := range valid {
+		if mapping.Version == 1 {
+			response.Created++
+		} else {
+			response.Updated++
+		}
+		s.Events.Publish(Event{Type: EventMappingChanged, Host: mapping.Domain})
+	}
+	response.Success = true
+	log.Printf("Importação de mapeamentos: %d criados, %d atualizados", response.Created, response.Updated)
+	return response, nil
 }
 
-// GetMapping retorna o mapeamento para um domu00ednio especu00edfico
+// GetMapping retorna o mapeamento para um domu00ednio especu00edfico. O host é normalizado
+// (porta, maiúsculas, ponto final, IDN) e, sem mapeamento exato, vale o wildcard mais
+// específico e depois o mapeamento padrão.
 func (s *ProxyService) GetMapping(domain string) (models.DomainMapping, error) {
+	host, err := NormalizeHost(domain)
+	if err != nil {
+		return models.DomainMapping{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	for _, m := range s.mappings {
-		if m.Domain == domain {
-			return m, nil
-		}
+	if entry, ok := s.table.lookup(host); ok {
+		return entry.mapping, nil
+	}
+
+	return models.DomainMapping{}, fmt.Errorf("%w: mapeamento não encontrado para o domínio: %s", ErrNotFound, domain)
+}
+
+// Resolve encontra o mapeamento do host, como GetMapping, e escolhe o destino conforme as
+// regras de path do mapeamento e a saúde dos destinos principal e alternativos
+func (s *ProxyService) Resolve(host string, requestURL *url.URL) (Route, error) {
+	normalized, err := NormalizeHost(host)
+	if err != nil {
+		return Route{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	s.mutex.RLock()
+	entry, ok := s.table.lookup(normalized)
+	health := s.health
+	s.mutex.RUnlock()
+	if !ok {
+		return Route{}, fmt.Errorf("%w: mapeamento não encontrado para o domínio: %s", ErrNotFound, host)
 	}
 
-	return models.DomainMapping{}, fmt.Errorf("mapeamento nu00e3o encontrado para o domu00ednio: %s", domain)
+	// Com verificação de saúde ativa, o destino padrão passa a ser o primeiro saudável
+	if health != nil && len(entry.mapping.Fallbacks) > 0 {
+		entry.mapping.Destination = health.active(entry.mapping)
+	}
+	return resolveRoute(entry, requestURL), nil
 }
 
 // GetAllMappings retorna todos os mapeamentos
@@ -68,26 +326,136 @@ func (s *ProxyService) GetAllMappings() []models.DomainMapping {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	// Cria uma cu00f3pia para evitar problemas de concorru00eancia
-	mappingsCopy := make([]models.DomainMapping, len(s.mappings))
-	copy(mappingsCopy, s.mappings)
+	// A listagem já é uma cópia, o que evita problemas de concorrência
+	return s.table.list()
+}
+
+// GetMappingByDomain retorna o mapeamento cadastrado para o domínio informado ("*",
+// "*.exemplo.com" ou exato), sem aplicar a precedência de GetMapping
+func (s *ProxyService) GetMappingByDomain(domain string) (models.DomainMapping, error) {
+	normalized, err := normalizeMappingDomain(domain)
+	if err != nil {
+		return models.DomainMapping{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
 
-	return mappingsCopy
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.storedMapping(normalized)
 }
 
-// DeleteMapping remove um mapeamento de domu00ednio
-func (s *ProxyService) DeleteMapping(domain string) error {
+// ListMappings retorna a página de mapeamentos que atende ao filtro e o total de mapeamentos
+// filtrados, ordenados por domínio
+func (s *ProxyService) ListMappings(filter MappingFilter) ([]models.DomainMapping, int) {
+	domain := strings.ToLower(filter.Domain)
+	destination := strings.ToLower(filter.Destination)
+
+	var matched []models.DomainMapping
+	for _, mapping := range s.GetAllMappings() {
+		if domain != "" && !strings.Contains(mapping.Domain, domain) {
+			continue
+		}
+		if destination != "" && !strings.Contains(strings.ToLower(mapping.Destination), destination) {
+			continue
+		}
+		if filter.Mode != "" && mapping.Mode != filter.Mode {
+			continue
+		}
+		matched = append(matched, mapping)
+	}
+
+	page, pageSize := filter.Page, filter.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultMappingPageSize
+	}
+	pageSize = min(pageSize, MaxMappingPageSize)
+
+	start := min((page-1)*pageSize, len(matched))
+	end := min(start+pageSize, len(matched))
+	return append([]models.DomainMapping{}, matched[start:end]...), len(matched)
+}
+
+// DeleteMapping remove um mapeamento de domínio. Se expectedVersion for diferente de zero,
+// só remove se ela for a versão atual.
+func (s *ProxyService) DeleteMapping(domain string, expectedVersion int64) error {
+	if normalized, err := normalizeMappingDomain(domain); err == nil {
+		domain = normalized
+	}
+	if err := s.deleteMapping(domain, expectedVersion); err != nil {
+		return err
+	}
+	s.Events.Publish(Event{Type: EventMappingChanged, Host: domain})
+	return nil
+}
+
+// deleteMapping remove o mapeamento do domínio já normalizado sem publicar evento; usado
+// diretamente na remoção do domínio
+func (s *ProxyService) deleteMapping(domain string, expectedVersion int64) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	for i, m := range s.mappings {
-		if m.Domain == domain {
-			// Remove o mapeamento
-			s.mappings = append(s.mappings[:i], s.mappings[i+1:]...)
-			log.Printf("Mapeamento removido para o domu00ednio %s", domain)
-			return nil
+	if expectedVersion != 0 {
+		// Confere a versão e remove na mesma operação do store
+		err := s.store.CompareAndSwap(store.MappingChange{Domain: domain, ExpectedVersion: expectedVersion})
+		var versionErr *store.VersionError
+		if errors.As(err, &versionErr) {
+			if versionErr.Current == 0 {
+				return fmt.Errorf("%w: mapeamento não encontrado para o domínio: %s", ErrNotFound, domain)
+			}
+			return fmt.Errorf("%w: %v", ErrPreconditionFailed, versionErr)
+		} else if err != nil {
+			return fmt.Errorf("erro ao remover mapeamento: %w", err)
+		}
+	} else if err := s.store.Delete(domain); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("%w: mapeamento não encontrado para o domínio: %s", ErrNotFound, domain)
 		}
+		return fmt.Errorf("erro ao remover mapeamento: %w", err)
 	}
 
-	return fmt.Errorf("mapeamento nu00e3o encontrado para o domu00ednio: %s", domain)
+	s.table.remove(domain)
+	log.Printf("Mapeamento removido para o domínio %s", domain)
+	return nil
+}
+
+// storedMapping lê o mapeamento do domínio normalizado direto do store. Mapeamentos gravados
+// antes do controle de versão são tratados como versão 1.
+func (s *ProxyService) storedMapping(domain string) (models.DomainMapping, error) {
+	mapping, err := s.store.Get(domain)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return mapping, fmt.Errorf("%w: mapeamento não encontrado para o domínio: %s", ErrNotFound, domain)
+		}
+		return mapping, fmt.Errorf("erro ao ler mapeamento: %w", err)
+	}
+	if mapping.Version == 0 {
+		mapping.Version = 1
+	}
+	return mapping, nil
+}
+
+// maxSwapAttempts limita as releituras de UpdateMapping quando outro processo altera o
+// mesmo mapeamento entre a leitura e a gravação
+const maxSwapAttempts = 3
+
+// swap grava as alterações no store com CompareAndSwap e, se elas forem aceitas, atualiza
+// a tabela. Erros de versão são retornados como *store.VersionError. Deve ser chamada com
+// s.mutex travado.
+func (s *ProxyService) swap(changes ...store.MappingChange) error {
+	if err := s.store.CompareAndSwap(changes...); err != nil {
+		var versionErr *store.VersionError
+		if errors.As(err, &versionErr) {
+			return err
+		}
+		return fmt.Errorf("erro ao gravar mapeamento: %w", err)
+	}
+	for _, change := range changes {
+		s.table.remove(change.Domain)
+		if change.Mapping != nil {
+			s.table.put(*change.Mapping)
+		}
+	}
+	return nil
 }
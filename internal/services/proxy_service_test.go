@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+	"github.com/renatoroquejani/poc-gocache/internal/store"
+)
+
+// TestProxyServiceSharedStore simula a API e o proxy alterando o mesmo arquivo de
+// mapeamentos: cada serviço tem seu próprio mutex, então os conflitos dependem da
+// gravação condicional do store
+func TestProxyServiceSharedStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mappings.json")
+	newService := func() *ProxyService {
+		mappingStore, err := store.New(store.KindFile, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		service, err := NewProxyService(mappingStore)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return service
+	}
+	api, proxy := newService(), newService()
+
+	if _, err := api.AddMapping(models.DomainMapping{Domain: "a.com", Destination: "https://api.origem.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := proxy.AddMapping(models.DomainMapping{Domain: "a.com", Destination: "https://proxy.origem.com"}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("AddMapping() concorrente = %v, esperado ErrConflict", err)
+	}
+
+	update := models.DomainMappingUpdateRequest{Destination: "https://nova.origem.com"}
+	updated, err := proxy.UpdateMapping("a.com", update, 1)
+	if err != nil || updated.Version != 2 {
+		t.Fatalf("UpdateMapping() = %+v, %v; esperado versão 2", updated, err)
+	}
+	if _, err := api.UpdateMapping("a.com", update, 1); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("UpdateMapping() com versão antiga = %v, esperado ErrPreconditionFailed", err)
+	}
+	if err := api.DeleteMapping("a.com", 1); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("DeleteMapping() com versão antiga = %v, esperado ErrPreconditionFailed", err)
+	}
+
+	response, err := api.ImportMappings([]models.DomainMapping{
+		{Domain: "a.com", Destination: "https://importada.origem.com"},
+		{Domain: "b.com", Destination: "https://b.origem.com"},
+	}, nil, true)
+	if err != nil || response.Created != 1 || response.Updated != 1 {
+		t.Fatalf("ImportMappings() = %+v, %v", response, err)
+	}
+	if err := proxy.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := proxy.GetMapping("a.com")
+	if err != nil || mapping.Version != 3 || mapping.Destination != "https://importada.origem.com" {
+		t.Errorf("GetMapping() = %+v, %v; esperado a versão importada", mapping, err)
+	}
+	if err := proxy.DeleteMapping("a.com", 3); err != nil {
+		t.Errorf("DeleteMapping() = %v", err)
+	}
+}
+
+func TestProxyServiceResolveRegexRules(t *testing.T) {
+	mappingStore := store.NewMemoryStore()
+	// Gravado sem validação, como faria outro processo: a regra inválida é ignorada
+	err := mappingStore.Put(models.DomainMapping{Domain: "a.com", Destination: "https://origem.com", Rules: []models.PathRule{
+		{Match: models.PathMatchRegex, Path: "/(", Destination: "https://invalida.com"},
+		{Match: models.PathMatchRegex, Path: "/p/(?P<slug>[a-z]+)", Destination: "https://s3.com/${slug}.html"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := NewProxyService(mappingStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/p/bolo", "https://s3.com/bolo.html"},
+		{"/p/bolo/1", "https://origem.com"},
+		{"/(", "https://origem.com"},
+	}
+	for _, tt := range tests {
+		route, err := service.Resolve("A.com:80", &url.URL{Path: tt.path})
+		if err != nil || route.Destination != tt.want {
+			t.Errorf("Resolve(%s) = %q, %v; esperado %q", tt.path, route.Destination, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/httpcache"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// Padrões da fila de expiração de URLs
+const (
+	// DefaultPurgeBatchSize é o número máximo de URLs por requisição de expiração à Gocache
+	DefaultPurgeBatchSize = 50
+	// DefaultPurgeDebounce é o tempo sem novas URLs após o qual a fila de um domínio é enviada
+	DefaultPurgeDebounce = 2 * time.Second
+	// DefaultPurgeMaxDelay é o tempo máximo que uma URL espera na fila, mesmo com novas chegando
+	DefaultPurgeMaxDelay = 10 * time.Second
+	// DefaultPurgeHistory é o número de lotes enviados mantidos para consulta
+	DefaultPurgeHistory = 200
+)
+
+// PurgeQueueOptions configura a PurgeQueue
+type PurgeQueueOptions struct {
+	BatchSize int
+	Debounce  time.Duration
+	MaxDelay  time.Duration
+	History   int
+}
+
+// PurgeQueueOptionsFromEnv lê CACHE_PURGE_BATCH_SIZE, CACHE_PURGE_DEBOUNCE e
+// CACHE_PURGE_MAX_DELAY
+func PurgeQueueOptionsFromEnv() (PurgeQueueOptions, error) {
+	opts := PurgeQueueOptions{
+		BatchSize: DefaultPurgeBatchSize,
+		Debounce:  DefaultPurgeDebounce,
+		MaxDelay:  DefaultPurgeMaxDelay,
+		History:   DefaultPurgeHistory,
+	}
+
+	if value := os.Getenv("CACHE_PURGE_BATCH_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			return opts, fmt.Errorf("valor inválido para CACHE_PURGE_BATCH_SIZE: %q", value)
+		}
+		opts.BatchSize = size
+	}
+	for name, target := range map[string]*time.Duration{
+		"CACHE_PURGE_DEBOUNCE":  &opts.Debounce,
+		"CACHE_PURGE_MAX_DELAY": &opts.MaxDelay,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return opts, fmt.Errorf("valor inválido para %s: %q", name, value)
+		}
+		*target = duration
+	}
+	return opts, nil
+}
+
+// PurgeQueue acumula expirações de URLs por domínio e as envia à Gocache em lotes. URLs
+// repetidas ou já cobertas por um wildcard na fila são descartadas. A fila de um domínio é
+// enviada quando fica Debounce sem novas URLs, quando a URL mais antiga completa MaxDelay
+// ou quando atinge BatchSize URLs.
+type PurgeQueue struct {
+	service *CacheService
+	options PurgeQueueOptions
+
+	// OnBatch, quando definido, é chamado com o resultado de cada lote enviado, fora do mutex
+	OnBatch func(models.PurgeBatchResult)
+
+	mutex   sync.Mutex
+	pending map[string]*pendingPurge
+	batches []models.PurgeBatchResult
+	closed  bool
+	// sending acompanha os envios em segundo plano, aguardados por Close
+	sending sync.WaitGroup
+}
+
+// pendingPurge são as URLs de um domínio aguardando envio
+type pendingPurge struct {
+	urls       []string
+	since      time.Time
+	timer      *time.Timer
+	requesters []string
+}
+
+// NewPurgeQueue cria a fila de expiração sobre o CacheService
+func NewPurgeQueue(service *CacheService, opts PurgeQueueOptions) *PurgeQueue {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultPurgeBatchSize
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultPurgeDebounce
+	}
+	if opts.MaxDelay < opts.Debounce {
+		opts.MaxDelay = opts.Debounce
+	}
+	if opts.History <= 0 {
+		opts.History = DefaultPurgeHistory
+	}
+	return &PurgeQueue{
+		service: service,
+		options: opts,
+		pending: make(map[string]*pendingPurge),
+	}
+}
+
+// Enqueue adiciona URLs à fila do domínio. O envio à Gocache acontece depois, em segundo plano.
+func (q *PurgeQueue) Enqueue(ctx context.Context, domain string, urls []string) (*models.PurgeQueueResponse, error) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return nil, fmt.Errorf("%w: domínio não especificado", ErrInvalidRequest)
+	}
+
+	response := &models.PurgeQueueResponse{Status: true, Domain: domain}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return nil, fmt.Errorf("fila de expiração encerrada")
+	}
+	entry, ok := q.pending[domain]
+	if !ok {
+		entry = &pendingPurge{since: time.Now()}
+	}
+
+	for _, raw := range urls {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		switch {
+		case slices.Contains(entry.urls, raw):
+			response.Duplicates++
+			continue
+		case coveredByWildcard(entry.urls, raw):
+			response.Covered++
+			continue
+		}
+
+		if strings.Contains(raw, "*") {
+			// O wildcard novo torna desnecessárias as URLs que ele cobre
+			kept := entry.urls[:0]
+			for _, existing := range entry.urls {
+				if httpcache.MatchWildcard(raw, existing) {
+					response.Covered++
+					continue
+				}
+				kept = append(kept, existing)
+			}
+			entry.urls = kept
+		}
+		entry.urls = append(entry.urls, raw)
+		response.Accepted++
+	}
+
+	if len(entry.urls) == 0 {
+		if response.Accepted+response.Duplicates+response.Covered == 0 {
+			return nil, fmt.Errorf("%w: a lista de URLs não pode estar vazia", ErrInvalidRequest)
+		}
+		return response, nil
+	}
+
+	if requester := requesterFromContext(ctx); requester != "" && !slices.Contains(entry.requesters, requester) {
+		entry.requesters = append(entry.requesters, requester)
+	}
+	q.pending[domain] = entry
+	response.Pending = len(entry.urls)
+
+	if len(entry.urls) >= q.options.BatchSize {
+		urls, requesters := q.takeLocked(domain)
+		q.sending.Add(1)
+		go func() {
+			defer q.sending.Done()
+			q.send(domain, urls, requesters, "size")
+		}()
+		response.Pending = 0
+		return response, nil
+	}
+
+	// Reinicia o debounce, sem passar do limite de espera da URL mais antiga
+	delay := q.options.Debounce
+	if remaining := time.Until(entry.since.Add(q.options.MaxDelay)); remaining < delay {
+		delay = max(remaining, 0)
+	}
+	if entry.timer != nil {
+		entry.timer.Stop()
+	}
+	entry.timer = time.AfterFunc(delay, func() { q.flushDomain(domain, "debounce") })
+	return response, nil
+}
+
+// Flush envia imediatamente a fila do domínio informado, ou de todos se vazio, e retorna
+// o resultado de cada lote
+func (q *PurgeQueue) Flush(domain string) []models.PurgeBatchResult {
+	domain = normalizeDomain(domain)
+
+	q.mutex.Lock()
+	domains := make([]string, 0, len(q.pending))
+	for pendingDomain := range q.pending {
+		if domain == "" || pendingDomain == domain {
+			domains = append(domains, pendingDomain)
+		}
+	}
+	sort.Strings(domains)
+	type flushItem struct {
+		domain     string
+		urls       []string
+		requesters []string
+	}
+	items := make([]flushItem, 0, len(domains))
+	for _, pendingDomain := range domains {
+		urls, requesters := q.takeLocked(pendingDomain)
+		items = append(items, flushItem{pendingDomain, urls, requesters})
+	}
+	q.mutex.Unlock()
+
+	results := []models.PurgeBatchResult{}
+	for _, item := range items {
+		results = append(results, q.send(item.domain, item.urls, item.requesters, "manual")...)
+	}
+	return results
+}
+
+// Close envia o que estiver na fila e aguarda os envios em andamento. Depois de Close,
+// Enqueue retorna erro; é chamado ao encerrar a API para não perder URLs pendentes.
+func (q *PurgeQueue) Close() {
+	q.mutex.Lock()
+	q.closed = true
+	q.mutex.Unlock()
+
+	q.Flush("")
+	q.sending.Wait()
+}
+
+// Status retorna as URLs aguardando envio e os últimos lotes enviados, do mais recente
+// para o mais antigo
+func (q *PurgeQueue) Status() ([]models.PendingPurge, []models.PurgeBatchResult) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	pending := make([]models.PendingPurge, 0, len(q.pending))
+	for domain, entry := range q.pending {
+		pending = append(pending, models.PendingPurge{
+			Domain: domain,
+			URLs:   append([]string(nil), entry.urls...),
+			Since:  entry.since.UTC(),
+		})
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Domain < pending[j].Domain })
+
+	batches := make([]models.PurgeBatchResult, 0, len(q.batches))
+	for i := len(q.batches) - 1; i >= 0; i-- {
+		batches = append(batches, q.batches[i])
+	}
+	return pending, batches
+}
+
+// flushDomain é chamado pelo timer de debounce
+func (q *PurgeQueue) flushDomain(domain, reason string) {
+	q.mutex.Lock()
+	urls, requesters := q.takeLocked(domain)
+	if len(urls) == 0 {
+		// A fila já foi enviada por Flush ou pelo limite de tamanho
+		q.mutex.Unlock()
+		return
+	}
+	q.sending.Add(1)
+	q.mutex.Unlock()
+
+	defer q.sending.Done()
+	q.send(domain, urls, requesters, reason)
+}
+
+// takeLocked retira da fila as URLs do domínio. Deve ser chamado com o mutex travado.
+func (q *PurgeQueue) takeLocked(domain string) ([]string, []string) {
+	entry, ok := q.pending[domain]
+	if !ok {
+		return nil, nil
+	}
+	if entry.timer != nil {
+		entry.timer.Stop()
+	}
+	delete(q.pending, domain)
+	return entry.urls, entry.requesters
+}
+
+// send envia as URLs em lotes de até BatchSize e registra o resultado de cada um
+func (q *PurgeQueue) send(domain string, urls, requesters []string, reason string) []models.PurgeBatchResult {
+	ctx := WithRequester(context.Background(), strings.Join(requesters, ", "))
+
+	var results []models.PurgeBatchResult
+	for start := 0; start < len(urls); start += q.options.BatchSize {
+		batch := urls[start:min(start+q.options.BatchSize, len(urls))]
+
+		result := models.PurgeBatchResult{Domain: domain, URLs: batch, Reason: reason}
+		response, err := q.service.PurgeUrls(ctx, models.CachePurgeRequest{Domain: domain, URLs: batch})
+		result.FlushedAt = time.Now().UTC()
+		if err != nil {
+			log.Printf("Erro ao expirar lote de %d URL(s) de %s: %v", len(batch), domain, err)
+			result.Error = err.Error()
+		} else {
+			result.Status = response.Status
+			result.Message = response.Message
+			result.JobID = response.JobID
+		}
+		results = append(results, result)
+	}
+
+	q.mutex.Lock()
+	q.batches = append(q.batches, results...)
+	if excess := len(q.batches) - q.options.History; excess > 0 {
+		q.batches = append([]models.PurgeBatchResult(nil), q.batches[excess:]...)
+	}
+	q.mutex.Unlock()
+
+	if q.OnBatch != nil {
+		for _, result := range results {
+			q.OnBatch(result)
+		}
+	}
+	return results
+}
+
+// coveredByWildcard indica se a URL é coberta por algum wildcard da lista
+func coveredByWildcard(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "*") && httpcache.MatchWildcard(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+func TestCoveredByWildcard(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		value    string
+		want     bool
+	}{
+		{"sem wildcard", []string{"https://a.com/x"}, "https://a.com/x", false},
+		{"coberta", []string{"https://a.com/x", "https://a.com/blog/*"}, "https://a.com/blog/post", true},
+		{"não coberta", []string{"https://a.com/blog/*"}, "https://a.com/loja/x", false},
+		{"lista vazia", nil, "https://a.com/x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coveredByWildcard(tt.patterns, tt.value); got != tt.want {
+				t.Errorf("coveredByWildcard(%v, %q) = %v, esperado %v", tt.patterns, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurgeQueueEnqueueCollapse(t *testing.T) {
+	tests := []struct {
+		name    string
+		batches [][]string
+		want    models.PurgeQueueResponse
+		pending []string
+	}{
+		{
+			name:    "duplicadas",
+			batches: [][]string{{"https://a.com/x", "https://a.com/x", " https://a.com/x "}},
+			want:    models.PurgeQueueResponse{Accepted: 1, Duplicates: 2, Pending: 1},
+			pending: []string{"https://a.com/x"},
+		},
+		{
+			name:    "cobertas por wildcard na fila",
+			batches: [][]string{{"https://a.com/blog/*"}, {"https://a.com/blog/post", "https://a.com/loja"}},
+			want:    models.PurgeQueueResponse{Accepted: 1, Covered: 1, Pending: 2},
+			pending: []string{"https://a.com/blog/*", "https://a.com/loja"},
+		},
+		{
+			name:    "wildcard novo substitui as URLs que cobre",
+			batches: [][]string{{"https://a.com/blog/a", "https://a.com/blog/b", "https://a.com/loja"}, {"https://a.com/blog/*"}},
+			want:    models.PurgeQueueResponse{Accepted: 1, Covered: 2, Pending: 2},
+			pending: []string{"https://a.com/loja", "https://a.com/blog/*"},
+		},
+		{
+			name:    "wildcard mais amplo substitui o mais específico",
+			batches: [][]string{{"https://a.com/blog/*"}, {"https://a.com/*"}},
+			want:    models.PurgeQueueResponse{Accepted: 1, Covered: 1, Pending: 1},
+			pending: []string{"https://a.com/*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newFakeGocache(t)
+			queue := NewPurgeQueue(NewCacheService(client), PurgeQueueOptions{Debounce: time.Hour})
+
+			var response *models.PurgeQueueResponse
+			for _, urls := range tt.batches {
+				var err error
+				if response, err = queue.Enqueue(context.Background(), "a.com", urls); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := *response
+			got.Status, got.Domain = false, ""
+			if got != tt.want {
+				t.Errorf("Enqueue() = %+v, esperado %+v", got, tt.want)
+			}
+
+			pending, _ := queue.Status()
+			if len(pending) != 1 || !slices.Equal(pending[0].URLs, tt.pending) {
+				t.Errorf("fila = %+v, esperado %v", pending, tt.pending)
+			}
+		})
+	}
+}
+
+func TestPurgeQueueFlushTriggers(t *testing.T) {
+	tests := []struct {
+		name    string
+		options PurgeQueueOptions
+		// interval é o tempo entre as chamadas de Enqueue
+		interval time.Duration
+		enqueues int
+		reason   string
+		batches  int
+	}{
+		{"tamanho do lote", PurgeQueueOptions{BatchSize: 2, Debounce: time.Hour}, 0, 4, "size", 2},
+		{"debounce", PurgeQueueOptions{Debounce: 20 * time.Millisecond, MaxDelay: time.Hour}, 0, 3, "debounce", 1},
+		// As URLs chegam mais rápido que o debounce, então só o MaxDelay dispara o envio
+		{"espera máxima", PurgeQueueOptions{Debounce: 40 * time.Millisecond, MaxDelay: 60 * time.Millisecond}, 15 * time.Millisecond, 8, "debounce", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newFakeGocache(t)
+			queue := NewPurgeQueue(NewCacheService(client), tt.options)
+
+			for i := 0; i < tt.enqueues; i++ {
+				if _, err := queue.Enqueue(context.Background(), "a.com", []string{"https://a.com/" + string(rune('a'+i))}); err != nil {
+					t.Fatal(err)
+				}
+				time.Sleep(tt.interval)
+			}
+
+			deadline := time.Now().Add(2 * time.Second)
+			for len(srv.Purges()) < tt.batches && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+			queue.Close()
+
+			_, batches := queue.Status()
+			if len(batches) < tt.batches {
+				t.Fatalf("lotes = %+v, esperado ao menos %d", batches, tt.batches)
+			}
+			for _, batch := range batches[len(batches)-tt.batches:] {
+				if batch.Reason != tt.reason || batch.Error != "" {
+					t.Errorf("lote = %+v, esperado motivo %q", batch, tt.reason)
+				}
+			}
+			sent := 0
+			for _, purge := range srv.Purges() {
+				sent += len(purge.URLs)
+			}
+			if sent != tt.enqueues {
+				t.Errorf("URLs expiradas = %d, esperado %d", sent, tt.enqueues)
+			}
+		})
+	}
+}
+
+func TestPurgeQueueClose(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	queue := NewPurgeQueue(NewCacheService(client), PurgeQueueOptions{Debounce: time.Hour})
+
+	if _, err := queue.Enqueue(context.Background(), "a.com", []string{"https://a.com/x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queue.Enqueue(context.Background(), "b.com", []string{"https://b.com/y"}); err != nil {
+		t.Fatal(err)
+	}
+	queue.Close()
+
+	if purges := srv.Purges(); len(purges) != 2 {
+		t.Errorf("expirações = %+v, esperado 2", purges)
+	}
+	if pending, _ := queue.Status(); len(pending) != 0 {
+		t.Errorf("fila após Close = %+v", pending)
+	}
+	if _, err := queue.Enqueue(context.Background(), "a.com", []string{"https://a.com/z"}); err == nil {
+		t.Error("Enqueue() após Close não retornou erro")
+	}
+}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strconv"
 
 	"github.com/renatoroquejani/poc-gocache/internal/models"
 	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
@@ -11,6 +13,9 @@ import (
 // RedirectService gerencia as operações relacionadas a regras de redirecionamento
 type RedirectService struct {
 	client *gocache.Client
+
+	// Events, quando definido, recebe as alterações de redirecionamentos
+	Events *EventBus
 }
 
 // NewRedirectService cria uma nova instância do serviço de redirecionamento
@@ -21,30 +26,31 @@ func NewRedirectService(client *gocache.Client) *RedirectService {
 }
 
 // CreateRedirect cria uma nova regra de redirecionamento
-func (s *RedirectService) CreateRedirect(request *models.RedirectCreateRequest) (*models.RedirectCreateResponse, error) {
+func (s *RedirectService) CreateRedirect(ctx context.Context, request *models.RedirectCreateRequest) (*models.RedirectCreateResponse, error) {
 	log.Printf("Criando regra de redirecionamento para o domínio %s: %s -> %s",
 		request.Domain, request.Source, request.Destination)
 
 	endpoint := fmt.Sprintf("/redirects/%s", request.Domain)
 	response := &models.RedirectCreateResponse{}
 
-	_, err := s.client.Post(endpoint, request, response)
+	_, err := s.client.PostContext(ctx, endpoint, request, response)
 	if err != nil {
 		log.Printf("Erro ao criar regra de redirecionamento: %v", err)
 		return nil, fmt.Errorf("erro ao criar regra de redirecionamento: %w", err)
 	}
 
+	s.Events.Publish(Event{Type: EventRedirectChanged, Domain: request.Domain, Path: request.Source, Requester: requesterFromContext(ctx)})
 	return response, nil
 }
 
 // ListRedirects lista todas as regras de redirecionamento para um domínio
-func (s *RedirectService) ListRedirects(domain string) (*models.RedirectListResponse, error) {
+func (s *RedirectService) ListRedirects(ctx context.Context, domain string) (*models.RedirectListResponse, error) {
 	log.Printf("Listando regras de redirecionamento para o domínio %s", domain)
 
 	endpoint := fmt.Sprintf("/redirects/%s", domain)
 	response := &models.RedirectListResponse{}
 
-	_, err := s.client.Get(endpoint, response)
+	_, err := s.client.GetContext(ctx, endpoint, response)
 	if err != nil {
 		log.Printf("Erro ao listar regras de redirecionamento: %v", err)
 		return nil, fmt.Errorf("erro ao listar regras de redirecionamento: %w", err)
@@ -54,17 +60,38 @@ func (s *RedirectService) ListRedirects(domain string) (*models.RedirectListResp
 }
 
 // DeleteRedirect exclui uma regra de redirecionamento
-func (s *RedirectService) DeleteRedirect(domain string, id int) (*models.RedirectDeleteResponse, error) {
+func (s *RedirectService) DeleteRedirect(ctx context.Context, domain string, id int) (*models.RedirectDeleteResponse, error) {
 	log.Printf("Excluindo regra de redirecionamento %d do domínio %s", id, domain)
 
-	endpoint := fmt.Sprintf("/redirects/%s/%d", domain, id)
-	response := &models.RedirectDeleteResponse{}
+	// O redirecionamento é consultado antes para que o evento informe o path afetado
+	event := Event{Type: EventRedirectChanged, Domain: domain, ID: strconv.Itoa(id), Requester: requesterFromContext(ctx)}
+	if s.Events != nil {
+		if list, err := s.ListRedirects(ctx, domain); err == nil {
+			for _, redirect := range list.Response {
+				if redirect.ID == id {
+					event.Path = redirect.Source
+				}
+			}
+		}
+	}
 
-	_, err := s.client.DeleteSimple(endpoint, response)
+	response, err := s.deleteRedirect(ctx, domain, id)
 	if err != nil {
 		log.Printf("Erro ao excluir regra de redirecionamento: %v", err)
-		return nil, fmt.Errorf("erro ao excluir regra de redirecionamento: %w", err)
+		return nil, err
 	}
 
+	s.Events.Publish(event)
+	return response, nil
+}
+
+// deleteRedirect remove o redirecionamento sem consultá-lo antes nem publicar evento; usado
+// na remoção do domínio, que já listou os redirecionamentos
+func (s *RedirectService) deleteRedirect(ctx context.Context, domain string, id int) (*models.RedirectDeleteResponse, error) {
+	endpoint := fmt.Sprintf("/redirects/%s/%d", domain, id)
+	response := &models.RedirectDeleteResponse{}
+	if _, err := s.client.DeleteSimpleContext(ctx, endpoint, response); err != nil {
+		return nil, fmt.Errorf("erro ao excluir regra de redirecionamento: %w", err)
+	}
 	return response, nil
 }
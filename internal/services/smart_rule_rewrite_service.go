@@ -1,9 +1,9 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"regexp"
 	"strings"
 
@@ -14,6 +14,9 @@ import (
 // SmartRuleRewriteService gerencia as Smart Rules de redirecionamento
 type SmartRuleRewriteService struct {
 	client *gocache.Client
+
+	// Events, quando definido, recebe as alterações de regras (ex: para expirar o cache)
+	Events *EventBus
 }
 
 // NewSmartRuleRewriteService cria uma nova instu00e2ncia do serviu00e7o de Smart Rules de redirecionamento
@@ -59,70 +62,36 @@ func extractURLFromMarkdown(input string) string {
 	return input
 }
 
-// CreateRewriteRule cria uma nova regra de redirecionamento
-func (s *SmartRuleRewriteService) CreateRewriteRule(request *models.SmartRuleRewriteCreateRequest) (*models.SmartRuleRewriteCreateResponse, error) {
-	log.Printf("Criando regra de redirecionamento para domu00ednio %s: %s -> %s",
-		request.Domain, request.Match.Request, request.Action.RedirectTo)
-
-	// Constru00f3i os paru00e2metros da requisiu00e7u00e3o
-	formData := make(map[string]string)
-
-	// Adiciona os paru00e2metros de match
-	if request.Match.RequestURI != "" {
-		formData["match[request_uri]"] = request.Match.RequestURI
-	} else if request.Match.Request != "" {
-		// Para compatibilidade, usa o campo Request se RequestURI nu00e3o for fornecido
-		formData["match[request_uri]"] = request.Match.Request
-	}
-
-	// Adiciona os mu00e9todos HTTP
-	if len(request.Match.RequestMethods) > 0 {
-		// Marca o checkbox do mu00e9todo HTTP
-		for _, method := range request.Match.RequestMethods {
-			formData["match[request_method][]"] = method
-		}
-	}
-
-	// Adiciona os tipos de dispositivo
-	for i, deviceType := range request.Match.DeviceTypes {
-		formData[fmt.Sprintf("match[device_type][%d]", i)] = deviceType
-	}
+// normalizeRewritePayload retorna uma cópia da requisição pronta para ser codificada como formulário
+func normalizeRewritePayload(request *models.SmartRuleRewriteCreateRequest) *models.SmartRuleRewriteCreateRequest {
+	payload := *request
 
-	// Adiciona os paru00e2metros de action
-	if request.Action.RedirectType != "" {
-		formData["action[redirect_type]"] = request.Action.RedirectType
+	// Para compatibilidade, usa o campo Request se RequestURI não for fornecido
+	if payload.Match.RequestURI == "" {
+		payload.Match.RequestURI = payload.Match.Request
 	}
 
-	if request.Action.RedirectTo != "" {
-		formData["action[redirect_to]"] = request.Action.RedirectTo
+	// Trata o problema de formatação Markdown no campo CORS
+	if payload.Action.CrossOrigin != "" {
+		payload.Action.CrossOrigin = extractURLFromMarkdown(payload.Action.CrossOrigin)
 	}
 
-	// Adiciona os novos paru00e2metros de action com os nomes corretos esperados pela API
-	if request.Action.RewriteURI != "" {
-		formData["action[set_uri]"] = request.Action.RewriteURI
-	}
+	return &payload
+}
 
-	if request.Action.RewriteHost != "" {
-		formData["action[set_host]"] = request.Action.RewriteHost
-	}
+// CreateRewriteRule cria uma nova regra de redirecionamento
+func (s *SmartRuleRewriteService) CreateRewriteRule(ctx context.Context, request *models.SmartRuleRewriteCreateRequest) (*models.SmartRuleRewriteCreateResponse, error) {
+	log.Printf("Criando regra de redirecionamento para domu00ednio %s: %s -> %s",
+		request.Domain, request.Match.Request, request.Action.RedirectTo)
 
-	if request.Action.Destination != "" {
-		formData["action[backend]"] = request.Action.Destination
-	}
+	// Prepara o payload normalizado para envio
+	payload := normalizeRewritePayload(request)
 
-	// O campo cross_origin na API u00e9 cors
-	if request.Action.CrossOrigin != "" {
-		// Tratar o problema de formatau00e7u00e3o Markdown
-		corsValue := extractURLFromMarkdown(request.Action.CrossOrigin)
-		formData["action[cors]"] = corsValue
-		// Imprimir para debug
-		log.Printf("CORS original: %s, CORS limpo: %s", request.Action.CrossOrigin, corsValue)
+	// Configuração SSL padrão para novas regras
+	if payload.Action.SSLMode == "" {
+		payload.Action.SSLMode = "partial"
 	}
 
-	// Configuração SSL completa conforme documentação
-	formData["action[ssl_mode]"] = "partial" // Valores válidos: off, flexible, full
-	log.Printf("Configurando SSL full com certificado compartilhado")
-
 	// Constru00f3i a URL da requisiu00e7u00e3o
 	// Formata o endpoint conforme documentau00e7u00e3o da GoCache
 	url := fmt.Sprintf("/rules/settings/%s", request.Domain)
@@ -130,27 +99,20 @@ func (s *SmartRuleRewriteService) CreateRewriteRule(request *models.SmartRuleRew
 	// Prepara o objeto de resposta
 	var response models.SmartRuleRewriteCreateResponse
 
-	// DEBUG: Imprime todos os paru00e2metros da requisiu00e7u00e3o
-	log.Printf("Enviando paru00e2metros: %v", formData)
-
 	// Faz a requisiu00e7u00e3o para a API
-	resp, err := s.client.Post(url, formData, &response)
+	_, err := s.client.PostContext(ctx, url, payload, &response)
 	if err != nil {
 		log.Printf("Erro ao criar regra de redirecionamento: %v", err)
-		return nil, err
-	}
-
-	if resp.StatusCode() != http.StatusOK {
-		log.Printf("Erro ao criar regra de redirecionamento. Cu00f3digo: %d", resp.StatusCode())
-		return nil, fmt.Errorf("erro ao criar regra de redirecionamento. Cu00f3digo: %d", resp.StatusCode())
+		return nil, fmt.Errorf("erro ao criar regra de redirecionamento: %w", err)
 	}
 
 	log.Printf("Regra de redirecionamento criada com sucesso. ID: %s", response.Response.ID)
+	s.publishRule(ctx, EventRuleCreated, request.Domain, response.Response.ID, &payload.Match)
 	return &response, nil
 }
 
 // ListRewriteRules lista todas as regras de redirecionamento de um domu00ednio
-func (s *SmartRuleRewriteService) ListRewriteRules(domain string) (*models.SmartRuleRewriteListResponse, error) {
+func (s *SmartRuleRewriteService) ListRewriteRules(ctx context.Context, domain string) (*models.SmartRuleRewriteListResponse, error) {
 	log.Printf("Listando regras de redirecionamento para domu00ednio %s", domain)
 
 	// Constru00f3i a URL da requisiu00e7u00e3o
@@ -161,15 +123,10 @@ func (s *SmartRuleRewriteService) ListRewriteRules(domain string) (*models.Smart
 	var response models.SmartRuleRewriteListResponse
 
 	// Faz a requisiu00e7u00e3o para a API
-	resp, err := s.client.Get(url, &response)
+	_, err := s.client.GetContext(ctx, url, &response)
 	if err != nil {
 		log.Printf("Erro ao listar regras de redirecionamento: %v", err)
-		return nil, err
-	}
-
-	if resp.StatusCode() != http.StatusOK {
-		log.Printf("Erro ao listar regras de redirecionamento. Cu00f3digo: %d", resp.StatusCode())
-		return nil, fmt.Errorf("erro ao listar regras de redirecionamento. Cu00f3digo: %d", resp.StatusCode())
+		return nil, fmt.Errorf("erro ao listar regras de redirecionamento: %w", err)
 	}
 
 	log.Printf("Regras de redirecionamento listadas com sucesso. Total: %d", len(response.Response.Rules))
@@ -177,34 +134,38 @@ func (s *SmartRuleRewriteService) ListRewriteRules(domain string) (*models.Smart
 }
 
 // DeleteRewriteRule remove uma regra de redirecionamento
-func (s *SmartRuleRewriteService) DeleteRewriteRule(domain, id string) (*models.SmartRuleRewriteDeleteResponse, error) {
+func (s *SmartRuleRewriteService) DeleteRewriteRule(ctx context.Context, domain, id string) (*models.SmartRuleRewriteDeleteResponse, error) {
 	log.Printf("Removendo regra de redirecionamento %s do domu00ednio %s", id, domain)
 
-	// Constru00f3i a URL da requisiu00e7u00e3o
-	// Formata o endpoint conforme documentau00e7u00e3o da GoCache
-	url := fmt.Sprintf("/rules/settings/%s/%s", domain, id)
+	// A regra é consultada antes para que o evento informe o host e o path afetados
+	previous := s.findRule(ctx, domain, id)
 
-	// Prepara o objeto de resposta
-	var response models.SmartRuleRewriteDeleteResponse
-
-	// Faz a requisiu00e7u00e3o para a API
-	resp, err := s.client.DeleteSimple(url, &response)
+	response, err := s.deleteRule(ctx, domain, id)
 	if err != nil {
 		log.Printf("Erro ao remover regra de redirecionamento: %v", err)
 		return nil, err
 	}
 
-	if resp.StatusCode() != http.StatusOK {
-		log.Printf("Erro ao remover regra de redirecionamento. Cu00f3digo: %d", resp.StatusCode())
-		return nil, fmt.Errorf("erro ao remover regra de redirecionamento. Cu00f3digo: %d", resp.StatusCode())
-	}
-
 	log.Printf("Regra de redirecionamento removida com sucesso")
+	s.publishRule(ctx, EventRuleDeleted, domain, id, previous)
+	return response, nil
+}
+
+// deleteRule remove a regra sem consultá-la antes nem publicar evento; usado na remoção do
+// domínio, que já listou as regras e não precisa expirar o cache de um domínio removido
+func (s *SmartRuleRewriteService) deleteRule(ctx context.Context, domain, id string) (*models.SmartRuleRewriteDeleteResponse, error) {
+	// Formata o endpoint conforme documentau00e7u00e3o da GoCache
+	url := fmt.Sprintf("/rules/settings/%s/%s", domain, id)
+
+	var response models.SmartRuleRewriteDeleteResponse
+	if _, err := s.client.DeleteSimpleContext(ctx, url, &response); err != nil {
+		return nil, fmt.Errorf("erro ao remover regra de redirecionamento: %w", err)
+	}
 	return &response, nil
 }
 
 // CreateSimplifiedRule cria uma regra de redirecionamento padrão com parâmetros simplificados
-func (s *SmartRuleRewriteService) CreateSimplifiedRule(request *models.SmartRuleSimplifiedRequest) (*models.SmartRuleRewriteCreateResponse, error) {
+func (s *SmartRuleRewriteService) CreateSimplifiedRule(ctx context.Context, request *models.SmartRuleSimplifiedRequest) (*models.SmartRuleRewriteCreateResponse, error) {
 	log.Printf("Criando regra de redirecionamento padrão para subdomínio: %s, bucket: %s, conta: %s",
 		request.Domain, request.BucketURL, request.AccountID)
 
@@ -242,85 +203,66 @@ func (s *SmartRuleRewriteService) CreateSimplifiedRule(request *models.SmartRule
 		completeRequest.Action.Destination)
 
 	// Usa o método existente para criar a regra
-	return s.CreateRewriteRule(completeRequest)
+	return s.CreateRewriteRule(ctx, completeRequest)
 }
 
 // UpdateRewriteRule atualiza uma regra de redirecionamento
-func (s *SmartRuleRewriteService) UpdateRewriteRule(domain, id string, request *models.SmartRuleRewriteCreateRequest) (*models.SmartRuleRewriteUpdateResponse, error) {
+func (s *SmartRuleRewriteService) UpdateRewriteRule(ctx context.Context, domain, id string, request *models.SmartRuleRewriteCreateRequest) (*models.SmartRuleRewriteUpdateResponse, error) {
 	log.Printf("Atualizando regra de redirecionamento %s do domu00ednio %s", id, domain)
 
-	// Constru00f3i os paru00e2metros da requisiu00e7u00e3o
-	formData := make(map[string]string)
-
-	// Adiciona os paru00e2metros de match
-	if request.Match.RequestURI != "" {
-		formData["match[request_uri]"] = request.Match.RequestURI
-	} else if request.Match.Request != "" {
-		// Para compatibilidade, usa o campo Request se RequestURI nu00e3o for fornecido
-		formData["match[request_uri]"] = request.Match.Request
-	}
-
-	// Adiciona os mu00e9todos HTTP
-	if len(request.Match.RequestMethods) > 0 {
-		// Marca o checkbox do mu00e9todo HTTP
-		for _, method := range request.Match.RequestMethods {
-			formData["match[request_method][]"] = method
-		}
-	}
-
-	// Adiciona os tipos de dispositivo
-	for i, deviceType := range request.Match.DeviceTypes {
-		formData[fmt.Sprintf("match[device_type][%d]", i)] = deviceType
-	}
-
-	// Adiciona os paru00e2metros de action
-	if request.Action.RedirectType != "" {
-		formData["action[redirect_type]"] = request.Action.RedirectType
-	}
-
-	if request.Action.RedirectTo != "" {
-		formData["action[redirect_to]"] = request.Action.RedirectTo
-	}
-
-	// Adiciona os novos paru00e2metros de action com os nomes corretos esperados pela API
-	if request.Action.RewriteURI != "" {
-		formData["action[set_uri]"] = request.Action.RewriteURI
-	}
-
-	if request.Action.RewriteHost != "" {
-		formData["action[set_host]"] = request.Action.RewriteHost
-	}
-
-	if request.Action.Destination != "" {
-		formData["action[backend]"] = request.Action.Destination
-	}
-
-	// O campo cross_origin na API u00e9 cors
-	if request.Action.CrossOrigin != "" {
-		// Tratar o problema de formatau00e7u00e3o Markdown
-		corsValue := extractURLFromMarkdown(request.Action.CrossOrigin)
-		formData["action[cors]"] = corsValue
-	}
+	// Prepara o payload normalizado para envio
+	payload := normalizeRewritePayload(request)
 
 	// Constru00f3i a URL da requisiu00e7u00e3o
 	// Formata o endpoint conforme documentau00e7u00e3o da GoCache
 	url := fmt.Sprintf("/rules/settings/%s/%s", domain, id)
 
+	// A regra anterior é consultada para expirar também o host e o path que ela atendia
+	previous := s.findRule(ctx, domain, id)
+
 	// Prepara o objeto de resposta
 	var response models.SmartRuleRewriteUpdateResponse
 
 	// Faz a requisiu00e7u00e3o para a API
-	resp, err := s.client.Put(url, formData, &response)
+	_, err := s.client.PutContext(ctx, url, payload, &response)
 	if err != nil {
 		log.Printf("Erro ao atualizar regra de redirecionamento: %v", err)
-		return nil, err
-	}
-
-	if resp.StatusCode() != http.StatusOK {
-		log.Printf("Erro ao atualizar regra de redirecionamento. Cu00f3digo: %d", resp.StatusCode())
-		return nil, fmt.Errorf("erro ao atualizar regra de redirecionamento. Cu00f3digo: %d", resp.StatusCode())
+		return nil, fmt.Errorf("erro ao atualizar regra de redirecionamento: %w", err)
 	}
 
 	log.Printf("Regra de redirecionamento atualizada com sucesso")
+	if previous != nil {
+		s.publishRule(ctx, EventRuleUpdated, domain, id, previous)
+	}
+	s.publishRule(ctx, EventRuleUpdated, domain, id, &payload.Match)
 	return &response, nil
 }
+
+// findRule busca a regra pelo ID, apenas quando há assinantes de eventos. Retorna nil se
+// ela não for encontrada.
+func (s *SmartRuleRewriteService) findRule(ctx context.Context, domain, id string) *models.SmartRuleRewriteMatch {
+	if s.Events == nil {
+		return nil
+	}
+	list, err := s.ListRewriteRules(ctx, domain)
+	if err != nil {
+		return nil
+	}
+	for _, rule := range list.Response.Rules {
+		if rule.ID == id {
+			return &rule.Match
+		}
+	}
+	return nil
+}
+
+// publishRule emite o evento da regra com o host e o request_uri da condição. Sem a
+// condição, o evento afeta o domínio inteiro.
+func (s *SmartRuleRewriteService) publishRule(ctx context.Context, eventType EventType, domain, id string, match *models.SmartRuleRewriteMatch) {
+	event := Event{Type: eventType, Domain: domain, ID: id, Requester: requesterFromContext(ctx)}
+	if match != nil {
+		event.Host = match.Host
+		event.Path = match.RequestURI
+	}
+	s.Events.Publish(event)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+func TestSmartRuleRewriteServiceCRUD(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewSmartRuleRewriteService(client)
+	var events []Event
+	service.Events = NewEventBus()
+	service.Events.Subscribe(func(event Event) { events = append(events, event) })
+	ctx := context.Background()
+
+	created, err := service.CreateRewriteRule(ctx, &models.SmartRuleRewriteCreateRequest{
+		Domain: "a.com",
+		Match:  models.SmartRuleRewriteMatch{Request: "/app/*", RequestMethods: []string{"GET", "HEAD"}, Host: "lp.a.com"},
+		Action: models.SmartRuleRewriteAction{RewriteURI: "/bucket/$1", RewriteHost: "b.s3.com", Destination: "b.s3.com", CrossOrigin: "[http://lp.a.com](http://lp.a.com)"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := srv.Rules("a.com")
+	if len(rules) != 1 || rules[0].ID != created.Response.ID {
+		t.Fatalf("regras = %+v", rules)
+	}
+	wantMatch := url.Values{"request_uri": {"/app/*"}, "request_method": {"GET", "HEAD"}, "host": {"lp.a.com"}}
+	wantAction := url.Values{"set_uri": {"/bucket/$1"}, "set_host": {"b.s3.com"}, "backend": {"b.s3.com"}, "cors": {"http://lp.a.com"}, "ssl_mode": {"partial"}}
+	if !reflect.DeepEqual(rules[0].Match, wantMatch) || !reflect.DeepEqual(rules[0].Action, wantAction) {
+		t.Errorf("formulário enviado: match=%v action=%v", rules[0].Match, rules[0].Action)
+	}
+
+	list, err := service.ListRewriteRules(ctx, "a.com")
+	if err != nil || len(list.Response.Rules) != 1 {
+		t.Fatalf("ListRewriteRules() = %+v, %v", list, err)
+	}
+	if action := list.Response.Rules[0].Action; action.RewriteURI != "/bucket/$1" || action.Destination != "b.s3.com" {
+		t.Errorf("ação listada = %+v", action)
+	}
+
+	update := &models.SmartRuleRewriteCreateRequest{
+		Domain: "a.com",
+		Match:  models.SmartRuleRewriteMatch{RequestURI: "/novo/*", Host: "lp.a.com"},
+		Action: models.SmartRuleRewriteAction{RewriteURI: "/outro/$1", RewriteHost: "b.s3.com", Destination: "b.s3.com"},
+	}
+	if _, err := service.UpdateRewriteRule(ctx, "a.com", created.Response.ID, update); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Rules("a.com")[0].Match.Get("request_uri"); got != "/novo/*" {
+		t.Errorf("request_uri após a alteração = %q", got)
+	}
+
+	if _, err := service.DeleteRewriteRule(ctx, "a.com", created.Response.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Rules("a.com"); len(got) != 0 {
+		t.Errorf("regras após a remoção = %+v", got)
+	}
+
+	// A alteração expira o path anterior e o novo; a remoção, o path da regra removida
+	var paths []string
+	for _, event := range events {
+		paths = append(paths, string(event.Type)+" "+event.Host+event.Path)
+	}
+	want := []string{
+		"rule.created lp.a.com/app/*",
+		"rule.updated lp.a.com/app/*",
+		"rule.updated lp.a.com/novo/*",
+		"rule.deleted lp.a.com/novo/*",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("eventos = %v, esperado %v", paths, want)
+	}
+}
+
+func TestCreateSimplifiedRule(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service := NewSmartRuleRewriteService(client)
+
+	_, err := service.CreateSimplifiedRule(context.Background(), &models.SmartRuleSimplifiedRequest{
+		Domain: "lp.a.com", ParentDomain: "a.com", BucketURL: "b.s3.com", AccountID: "cliente-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := srv.Rules("a.com")
+	if len(rules) != 1 {
+		t.Fatalf("regras = %+v", rules)
+	}
+	if got := rules[0].Match.Get("host"); got != "lp.a.com" {
+		t.Errorf("host = %q", got)
+	}
+	if got := rules[0].Action.Get("set_uri"); got != "/cliente-1/$1" {
+		t.Errorf("set_uri = %q", got)
+	}
+	if got := rules[0].Action.Get("cors"); got != "http://lp.a.com" {
+		t.Errorf("cors = %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -21,11 +22,11 @@ func NewSmartRuleService(client *gocache.Client) *SmartRuleService {
 }
 
 // ListSmartRules lista todas as smart rules para um domínio
-func (s *SmartRuleService) ListSmartRules(domainID int) (*models.SmartRuleListResponse, error) {
+func (s *SmartRuleService) ListSmartRules(ctx context.Context, domainID int) (*models.SmartRuleListResponse, error) {
 	endpoint := fmt.Sprintf("/domains/%d/smart-rules", domainID)
 	result := &models.SmartRuleListResponse{}
 
-	_, err := s.client.Get(endpoint, result)
+	_, err := s.client.GetContext(ctx, endpoint, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao listar smart rules: %w", err)
 	}
@@ -34,11 +35,11 @@ func (s *SmartRuleService) ListSmartRules(domainID int) (*models.SmartRuleListRe
 }
 
 // GetSmartRule obtém detalhes de uma smart rule específica
-func (s *SmartRuleService) GetSmartRule(domainID, ruleID int) (*models.SmartRuleResponse, error) {
+func (s *SmartRuleService) GetSmartRule(ctx context.Context, domainID, ruleID int) (*models.SmartRuleResponse, error) {
 	endpoint := fmt.Sprintf("/domains/%d/smart-rules/%d", domainID, ruleID)
 	result := &models.SmartRuleResponse{}
 
-	_, err := s.client.Get(endpoint, result)
+	_, err := s.client.GetContext(ctx, endpoint, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao obter smart rule: %w", err)
 	}
@@ -47,11 +48,11 @@ func (s *SmartRuleService) GetSmartRule(domainID, ruleID int) (*models.SmartRule
 }
 
 // CreateSmartRule cria uma nova smart rule
-func (s *SmartRuleService) CreateSmartRule(req models.SmartRuleCreateRequest) (*models.SmartRuleResponse, error) {
+func (s *SmartRuleService) CreateSmartRule(ctx context.Context, req models.SmartRuleCreateRequest) (*models.SmartRuleResponse, error) {
 	endpoint := fmt.Sprintf("/domains/%d/smart-rules", req.DomainID)
 	result := &models.SmartRuleResponse{}
 
-	_, err := s.client.Post(endpoint, req, result)
+	_, err := s.client.PostContext(ctx, endpoint, req, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar smart rule: %w", err)
 	}
@@ -60,7 +61,7 @@ func (s *SmartRuleService) CreateSmartRule(req models.SmartRuleCreateRequest) (*
 }
 
 // CreateS3SmartRule cria uma smart rule apontando para um bucket S3
-func (s *SmartRuleService) CreateS3SmartRule(req models.S3SmartRuleRequest) (*models.SmartRuleResponse, error) {
+func (s *SmartRuleService) CreateS3SmartRule(ctx context.Context, req models.S3SmartRuleRequest) (*models.SmartRuleResponse, error) {
 	// Monta a URL de origem para o bucket S3
 	s3Origin := fmt.Sprintf("%s/%s/", req.S3Bucket, req.UserFolder)
 
@@ -105,15 +106,15 @@ func (s *SmartRuleService) CreateS3SmartRule(req models.S3SmartRuleRequest) (*mo
 		Actions:     actions,
 	}
 
-	return s.CreateSmartRule(smartRule)
+	return s.CreateSmartRule(ctx, smartRule)
 }
 
 // UpdateSmartRule atualiza uma smart rule existente
-func (s *SmartRuleService) UpdateSmartRule(domainID, ruleID int, req models.SmartRuleUpdateRequest) (*models.SmartRuleResponse, error) {
+func (s *SmartRuleService) UpdateSmartRule(ctx context.Context, domainID, ruleID int, req models.SmartRuleUpdateRequest) (*models.SmartRuleResponse, error) {
 	endpoint := fmt.Sprintf("/domains/%d/smart-rules/%d", domainID, ruleID)
 	result := &models.SmartRuleResponse{}
 
-	_, err := s.client.Put(endpoint, req, result)
+	_, err := s.client.PutContext(ctx, endpoint, req, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao atualizar smart rule: %w", err)
 	}
@@ -122,11 +123,11 @@ func (s *SmartRuleService) UpdateSmartRule(domainID, ruleID int, req models.Smar
 }
 
 // DeleteSmartRule exclui uma smart rule
-func (s *SmartRuleService) DeleteSmartRule(domainID, ruleID int) (*models.SmartRuleDeleteResponse, error) {
+func (s *SmartRuleService) DeleteSmartRule(ctx context.Context, domainID, ruleID int) (*models.SmartRuleDeleteResponse, error) {
 	endpoint := fmt.Sprintf("/domains/%d/smart-rules/%d", domainID, ruleID)
 	result := &models.SmartRuleDeleteResponse{}
 
-	_, err := s.client.DeleteSimple(endpoint, result)
+	_, err := s.client.DeleteSimpleContext(ctx, endpoint, result)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao excluir smart rule: %w", err)
 	}
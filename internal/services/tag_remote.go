@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// maxRemoteTagCache limita quantas URLs o RemoteTagRegistrar lembra para evitar reenvios
+const maxRemoteTagCache = 10000
+
+// RemoteTagRegistrar envia ao índice de tags da API (POST /api/v1/cache/tags) as tags
+// lidas pelo cmd/proxy. Só envia quando as tags de uma URL mudam.
+type RemoteTagRegistrar struct {
+	baseURL string
+	client  *http.Client
+
+	mutex sync.Mutex
+	sent  map[string]string
+}
+
+// NewRemoteTagRegistrar cria o cliente para a API em baseURL (ex: http://localhost:8081)
+func NewRemoteTagRegistrar(baseURL string) *RemoteTagRegistrar {
+	return &RemoteTagRegistrar{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		sent:    make(map[string]string),
+	}
+}
+
+// RegisterTags envia as tags da URL à API, se forem diferentes das últimas enviadas
+func (r *RemoteTagRegistrar) RegisterTags(ctx context.Context, rawURL string, tags []string) error {
+	// Usa a mesma chave do índice para que variações de query string não gerem reenvios
+	key, err := tagIndexKey(rawURL)
+	if err != nil {
+		return err
+	}
+	tags = normalizeTags(tags)
+	joined := strings.Join(tags, ",")
+
+	r.mutex.Lock()
+	previous, known := r.sent[key]
+	r.mutex.Unlock()
+	// URLs sem tags só precisam ser enviadas se já tiveram tags antes
+	if previous == joined && (known || len(tags) == 0) {
+		return nil
+	}
+
+	body, err := json.Marshal(models.CacheTagRegisterRequest{
+		Entries: []models.CacheTagEntry{{URL: key, Tags: tags}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+"/api/v1/cache/tags", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar tags na API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("erro ao registrar tags na API: status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	r.mutex.Lock()
+	if len(r.sent) >= maxRemoteTagCache {
+		r.sent = make(map[string]string)
+	}
+	r.sent[key] = joined
+	r.mutex.Unlock()
+	return nil
+}
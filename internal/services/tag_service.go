@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/jsonfile"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// Limites do índice de tags
+const (
+	// MaxTagIndexURLs é o número máximo de URLs no índice; URLs novas além dele são ignoradas
+	MaxTagIndexURLs = 100000
+	// tagIndexSaveDelay agrupa as alterações do índice em uma única gravação do arquivo
+	tagIndexSaveDelay = 2 * time.Second
+)
+
+// TagService mantém o índice URL -> cache-tags e expira pela Gocache as URLs de uma tag.
+// As URLs são indexadas sem query string, então variações como ?utm= não criam entradas
+// novas. Quando path é informado, o índice é gravado em um arquivo JSON até
+// tagIndexSaveDelay depois de cada alteração, e em Close, e recarregado ao iniciar.
+type TagService struct {
+	cache *CacheService
+	path  string
+	// maxURLs é o limite de URLs do índice, MaxTagIndexURLs fora dos testes
+	maxURLs int
+
+	mutex     sync.RWMutex
+	urlTags   map[string][]string
+	tagURLs   map[string]map[string]struct{}
+	dirty     bool
+	saveTimer *time.Timer
+	// saveMutex impede que duas gravações do arquivo se sobreponham
+	saveMutex sync.Mutex
+}
+
+// tagIndexFile é o formato do arquivo do índice
+type tagIndexFile struct {
+	URLs map[string][]string `json:"urls"`
+}
+
+// NewTagService cria o serviço de tags, carregando o índice de path se ele existir.
+// Com path vazio, o índice fica só em memória.
+func NewTagService(cache *CacheService, path string) (*TagService, error) {
+	s := &TagService{
+		cache:   cache,
+		path:    path,
+		maxURLs: MaxTagIndexURLs,
+		urlTags: make(map[string][]string),
+		tagURLs: make(map[string]map[string]struct{}),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	var file tagIndexFile
+	if err := jsonfile.Load(path, &file); err != nil {
+		return nil, fmt.Errorf("erro ao carregar índice de tags: %w", err)
+	}
+	// Índices gravados por versões anteriores podem ter URLs com query string; as variações
+	// de uma mesma URL são unidas
+	for rawURL, tags := range file.URLs {
+		key, err := tagIndexKey(rawURL)
+		if err != nil {
+			continue
+		}
+		s.setLocked(key, normalizeTags(append(slices.Clone(s.urlTags[key]), tags...)))
+	}
+	return s, nil
+}
+
+// ParseCacheTags interpreta o valor de um header Cache-Tag (tags separadas por vírgula)
+func ParseCacheTags(header string) []string {
+	return normalizeTags(strings.Split(header, ","))
+}
+
+// RegisterTags substitui as tags da URL; sem tags, a URL sai do índice. Usado pelo proxy
+// ao ler o header Cache-Tag das respostas.
+func (s *TagService) RegisterTags(_ context.Context, rawURL string, tags []string) error {
+	_, err := s.Register([]models.CacheTagEntry{{URL: rawURL, Tags: tags}})
+	return err
+}
+
+// Register substitui as tags de cada URL e agenda a gravação do índice. Retorna quantas
+// URLs tiveram as tags alteradas; URLs novas com o índice cheio são ignoradas.
+func (s *TagService) Register(entries []models.CacheTagEntry) (int, error) {
+	type change struct {
+		url  string
+		tags []string
+	}
+	changes := make([]change, 0, len(entries))
+	for _, entry := range entries {
+		key, err := tagIndexKey(entry.URL)
+		if err != nil {
+			return 0, err
+		}
+		changes = append(changes, change{key, normalizeTags(entry.Tags)})
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	updated, ignored := 0, 0
+	for _, c := range changes {
+		if slices.Equal(s.urlTags[c.url], c.tags) {
+			continue
+		}
+		if _, known := s.urlTags[c.url]; !known && len(c.tags) > 0 && len(s.urlTags) >= s.maxURLs {
+			ignored++
+			continue
+		}
+		s.setLocked(c.url, c.tags)
+		updated++
+	}
+	if ignored > 0 {
+		log.Printf("Índice de tags cheio (%d URLs): %d URL(s) nova(s) ignorada(s)", s.maxURLs, ignored)
+	}
+	if updated > 0 {
+		s.scheduleSaveLocked()
+	}
+	return updated, nil
+}
+
+// Close grava o índice se houver alterações ainda não gravadas; é chamado ao encerrar a API
+func (s *TagService) Close() error {
+	s.mutex.Lock()
+	if s.saveTimer != nil {
+		s.saveTimer.Stop()
+		s.saveTimer = nil
+	}
+	s.mutex.Unlock()
+	return s.save()
+}
+
+// URLsForTags retorna, ordenadas, as URLs associadas a qualquer uma das tags
+func (s *TagService) URLsForTags(tags []string) []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	set := make(map[string]struct{})
+	for _, tag := range normalizeTags(tags) {
+		for rawURL := range s.tagURLs[tag] {
+			set[rawURL] = struct{}{}
+		}
+	}
+	urls := make([]string, 0, len(set))
+	for rawURL := range set {
+		urls = append(urls, rawURL)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// PurgeTags expira as URLs do domínio (ou de subdomínios dele) associadas às tags. Cada URL
+// é expirada nos esquemas http e https, com e sem query string, em lotes enviados por
+// CacheService.PurgeUrls.
+func (s *TagService) PurgeTags(ctx context.Context, req models.CachePurgeTagsRequest) (*models.CachePurgeTagsResponse, error) {
+	domain := normalizeDomain(req.Domain)
+	tags := normalizeTags(req.Tags)
+	if domain == "" || len(tags) == 0 {
+		return nil, fmt.Errorf("%w: informe o domínio e ao menos uma tag", ErrInvalidRequest)
+	}
+
+	response := &models.CachePurgeTagsResponse{Status: true, Tags: tags, URLs: []string{}}
+
+	seen := make(map[string]bool)
+	for _, rawURL := range s.URLsForTags(tags) {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+		host := parsed.Hostname()
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			continue
+		}
+		// O índice não guarda a query string, então as variações com query são expiradas
+		// pelo wildcard ?*
+		rest := strings.TrimPrefix(rawURL, parsed.Scheme+"://")
+		for _, scheme := range []string{"http", "https"} {
+			for _, variant := range []string{scheme + "://" + rest, scheme + "://" + rest + "?*"} {
+				if !seen[variant] {
+					seen[variant] = true
+					response.URLs = append(response.URLs, variant)
+				}
+			}
+		}
+	}
+
+	if len(response.URLs) == 0 {
+		response.Message = "nenhuma URL do domínio associada às tags"
+		return response, nil
+	}
+
+	for start := 0; start < len(response.URLs); start += DefaultPurgeBatchSize {
+		batch := response.URLs[start:min(start+DefaultPurgeBatchSize, len(response.URLs))]
+		result, err := s.cache.PurgeUrls(ctx, models.CachePurgeRequest{Domain: domain, URLs: batch})
+		if err != nil {
+			return nil, err
+		}
+		response.JobIDs = append(response.JobIDs, result.JobID)
+		response.Message = result.Message
+		if result.EdgeError != "" {
+			response.EdgeError = result.EdgeError
+		}
+	}
+	return response, nil
+}
+
+// setLocked substitui as tags da URL nos dois sentidos do índice
+func (s *TagService) setLocked(rawURL string, tags []string) {
+	for _, tag := range s.urlTags[rawURL] {
+		delete(s.tagURLs[tag], rawURL)
+		if len(s.tagURLs[tag]) == 0 {
+			delete(s.tagURLs, tag)
+		}
+	}
+	if len(tags) == 0 {
+		delete(s.urlTags, rawURL)
+		return
+	}
+
+	s.urlTags[rawURL] = tags
+	for _, tag := range tags {
+		if s.tagURLs[tag] == nil {
+			s.tagURLs[tag] = make(map[string]struct{})
+		}
+		s.tagURLs[tag][rawURL] = struct{}{}
+	}
+}
+
+// scheduleSaveLocked marca o índice como alterado e agenda a gravação, se ainda não houver
+// uma agendada. Deve ser chamado com o mutex travado.
+func (s *TagService) scheduleSaveLocked() {
+	if s.path == "" {
+		return
+	}
+	s.dirty = true
+	if s.saveTimer != nil {
+		return
+	}
+	s.saveTimer = time.AfterFunc(tagIndexSaveDelay, func() {
+		s.mutex.Lock()
+		s.saveTimer = nil
+		s.mutex.Unlock()
+		if err := s.save(); err != nil {
+			log.Printf("Erro ao gravar índice de tags: %v", err)
+		}
+	})
+}
+
+// save grava o índice no arquivo configurado, se houver alterações. Em caso de erro o
+// índice continua marcado como alterado para a próxima gravação.
+func (s *TagService) save() error {
+	s.saveMutex.Lock()
+	defer s.saveMutex.Unlock()
+
+	s.mutex.Lock()
+	if s.path == "" || !s.dirty {
+		s.mutex.Unlock()
+		return nil
+	}
+	// As tags de uma URL nunca são alteradas no lugar, então a cópia rasa pode ser
+	// serializada fora do mutex
+	file := tagIndexFile{URLs: maps.Clone(s.urlTags)}
+	s.dirty = false
+	s.mutex.Unlock()
+
+	if err := jsonfile.Save(s.path, file); err != nil {
+		s.mutex.Lock()
+		s.scheduleSaveLocked()
+		s.mutex.Unlock()
+		return fmt.Errorf("erro ao gravar índice de tags: %w", err)
+	}
+	return nil
+}
+
+// tagIndexKey normaliza a URL usada como chave do índice: esquema e host em minúsculas,
+// sem query string nem fragmento
+func tagIndexKey(rawURL string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", fmt.Errorf("%w: URL inválida para o índice de tags: %q", ErrInvalidRequest, rawURL)
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.RawQuery = ""
+	parsed.ForceQuery = false
+	parsed.Fragment = ""
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	}
+	return parsed.String(), nil
+}
+
+// normalizeTags remove espaços, repetições e tags vazias; as tags não diferenciam maiúsculas
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
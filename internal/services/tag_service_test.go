@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+func TestTagIndexKey(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: "https://Loja.com/produto/123", want: "https://loja.com/produto/123"},
+		{raw: "HTTP://loja.com", want: "http://loja.com/"},
+		{raw: "https://loja.com/produto/123?utm_source=x&cor=azul", want: "https://loja.com/produto/123"},
+		{raw: "https://loja.com/produto/123?", want: "https://loja.com/produto/123"},
+		{raw: "https://loja.com/produto/123#avaliacoes", want: "https://loja.com/produto/123"},
+		{raw: "/produto/123", wantErr: true},
+		{raw: "ftp://loja.com/x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := tagIndexKey(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tagIndexKey(%q) erro = %v, esperado erro = %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("tagIndexKey(%q) = %q, esperado %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagServiceRegister(t *testing.T) {
+	service, err := NewTagService(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.maxURLs = 2
+
+	updated, err := service.Register([]models.CacheTagEntry{
+		{URL: "https://loja.com/produto/1?utm=a", Tags: []string{"product-1"}},
+		{URL: "https://loja.com/produto/1?utm=b", Tags: []string{"product-1"}},
+		{URL: "https://loja.com/produto/2", Tags: []string{"product-2"}},
+		// Além do limite: ignorada
+		{URL: "https://loja.com/produto/3", Tags: []string{"product-3"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 2 {
+		t.Errorf("Register() = %d, esperado 2", updated)
+	}
+	if urls := service.URLsForTags([]string{"product-1", "product-3"}); !slices.Equal(urls, []string{"https://loja.com/produto/1"}) {
+		t.Errorf("URLsForTags() = %v", urls)
+	}
+
+	// Com o índice cheio, URLs já indexadas continuam podendo mudar ou sair
+	if _, err := service.Register([]models.CacheTagEntry{
+		{URL: "https://loja.com/produto/2", Tags: nil},
+		{URL: "https://loja.com/produto/1", Tags: []string{"product-1", "home"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if urls := service.URLsForTags([]string{"product-2"}); len(urls) != 0 {
+		t.Errorf("URLsForTags(product-2) = %v, esperado vazio", urls)
+	}
+	if urls := service.URLsForTags([]string{"home"}); len(urls) != 1 {
+		t.Errorf("URLsForTags(home) = %v", urls)
+	}
+}
+
+func TestTagServicePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags", "index.json")
+	service, err := NewTagService(nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rawURL := range []string{"https://loja.com/a", "https://loja.com/b", "https://loja.com/c"} {
+		if err := service.RegisterTags(context.Background(), rawURL, []string{"home"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A gravação é agrupada: o arquivo só aparece depois do atraso ou de Close
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("índice gravado antes do atraso: %v", err)
+	}
+	if err := service.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewTagService(nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if urls := reloaded.URLsForTags([]string{"home"}); len(urls) != 3 {
+		t.Errorf("URLsForTags() após recarregar = %v, esperado 3 URLs", urls)
+	}
+}
+
+func TestTagServiceLoadLegacyQueryKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	legacy := `{"urls": {"https://loja.com/p?utm=a": ["a"], "https://loja.com/p?utm=b": ["b"]}}`
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	service, err := NewTagService(nil, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if urls := service.URLsForTags([]string{"a", "b"}); !slices.Equal(urls, []string{"https://loja.com/p"}) {
+		t.Errorf("URLsForTags() = %v, esperado a URL sem query", urls)
+	}
+}
+
+func TestTagServicePurgeTags(t *testing.T) {
+	srv, client := newFakeGocache(t)
+	service, err := NewTagService(NewCacheService(client), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Register([]models.CacheTagEntry{
+		{URL: "https://loja.com/produto/1?cor=azul", Tags: []string{"product-1"}},
+		{URL: "https://outra.com/produto/1", Tags: []string{"product-1"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := service.PurgeTags(context.Background(), models.CachePurgeTagsRequest{Domain: "loja.com", Tags: []string{"Product-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"http://loja.com/produto/1", "http://loja.com/produto/1?*",
+		"https://loja.com/produto/1", "https://loja.com/produto/1?*",
+	}
+	if !slices.Equal(response.URLs, want) {
+		t.Errorf("URLs expiradas = %v, esperado %v", response.URLs, want)
+	}
+	if purges := srv.Purges(); len(purges) != 1 || !slices.Equal(purges[0].URLs, want) {
+		t.Errorf("expirações = %+v", purges)
+	}
+}
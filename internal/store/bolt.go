@@ -0,0 +1,163 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+var mappingsBucket = []byte("mappings")
+
+// boltLockTimeout é o tempo máximo de espera pelo lock do arquivo quando outro processo
+// está com o banco aberto
+const boltLockTimeout = 5 * time.Second
+
+// BoltStore grava os mapeamentos em um banco bbolt embarcado. O bbolt trava o arquivo
+// enquanto ele está aberto, então o banco é aberto a cada operação para que a API e o
+// proxy possam usar o mesmo arquivo; leituras usam lock compartilhado.
+type BoltStore struct {
+	path string
+}
+
+// NewBoltStore cria um store bbolt, criando o arquivo e o bucket se necessário
+func NewBoltStore(path string) (*BoltStore, error) {
+	s := &BoltStore{path: path}
+	err := s.update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(mappingsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// List retorna todos os mapeamentos, ordenados por domínio (ordem das chaves no bbolt)
+func (s *BoltStore) List() ([]models.DomainMapping, error) {
+	var mappings []models.DomainMapping
+	err := s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(mappingsBucket).ForEach(func(key, value []byte) error {
+			var mapping models.DomainMapping
+			if err := json.Unmarshal(value, &mapping); err != nil {
+				return fmt.Errorf("mapeamento %q inválido: %w", key, err)
+			}
+			mappings = append(mappings, mapping)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// Get retorna o mapeamento do domínio
+func (s *BoltStore) Get(domain string) (models.DomainMapping, error) {
+	var mapping models.DomainMapping
+	err := s.view(func(tx *bolt.Tx) error {
+		value := tx.Bucket(mappingsBucket).Get([]byte(domain))
+		if value == nil {
+			return ErrNotFound
+		}
+		if err := json.Unmarshal(value, &mapping); err != nil {
+			return fmt.Errorf("mapeamento %q inválido: %w", domain, err)
+		}
+		return nil
+	})
+	return mapping, err
+}
+
+// Put cria ou substitui o mapeamento do domínio
+func (s *BoltStore) Put(mapping models.DomainMapping) error {
+	value, err := json.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar mapeamento: %w", err)
+	}
+	return s.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(mappingsBucket).Put([]byte(mapping.Domain), value)
+	})
+}
+
+// Delete remove o mapeamento do domínio
+func (s *BoltStore) Delete(domain string) error {
+	return s.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(mappingsBucket)
+		if bucket.Get([]byte(domain)) == nil {
+			return ErrNotFound
+		}
+		return bucket.Delete([]byte(domain))
+	})
+}
+
+// CompareAndSwap aplica as alterações em uma única transação, se todas as versões
+// esperadas conferirem
+func (s *BoltStore) CompareAndSwap(changes ...MappingChange) error {
+	values := make([][]byte, len(changes))
+	for i, change := range changes {
+		if change.Mapping == nil {
+			continue
+		}
+		value, err := json.Marshal(change.Mapping)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar mapeamento: %w", err)
+		}
+		values[i] = value
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(mappingsBucket)
+		err := checkChanges(changes, func(domain string) (models.DomainMapping, bool, error) {
+			var mapping models.DomainMapping
+			value := bucket.Get([]byte(domain))
+			if value == nil {
+				return mapping, false, nil
+			}
+			if err := json.Unmarshal(value, &mapping); err != nil {
+				return mapping, true, fmt.Errorf("mapeamento %q inválido: %w", domain, err)
+			}
+			return mapping, true, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, change := range changes {
+			if values[i] == nil {
+				err = bucket.Delete([]byte(change.Domain))
+			} else {
+				err = bucket.Put([]byte(change.Domain), values[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close não mantém recursos abertos, pois o banco é aberto a cada operação
+func (s *BoltStore) Close() error {
+	return nil
+}
+
+func (s *BoltStore) view(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: boltLockTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("erro ao abrir store de mapeamentos %s: %w", s.path, err)
+	}
+	defer db.Close()
+	return db.View(fn)
+}
+
+func (s *BoltStore) update(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: boltLockTimeout})
+	if err != nil {
+		return fmt.Errorf("erro ao abrir store de mapeamentos %s: %w", s.path, err)
+	}
+	defer db.Close()
+	return db.Update(fn)
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/renatoroquejani/poc-gocache/internal/jsonfile"
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// FileStore grava os mapeamentos em um arquivo JSON. Cada escrita gera um arquivo
+// temporário que substitui o original com rename, então leitores em outros processos
+// nunca veem um arquivo pela metade. O arquivo é relido a cada operação e as escritas
+// travam o arquivo auxiliar <path>.lock com flock durante a leitura e a gravação, o que
+// permite que a API e o proxy compartilhem o mesmo caminho em sistemas Unix.
+type FileStore struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewFileStore cria um store em arquivo, criando o diretório e o arquivo se necessário
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório do store de mapeamentos: %w", err)
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := s.write(map[string]models.DomainMapping{}); err != nil {
+			return nil, err
+		}
+	} else if _, err := s.read(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// List retorna todos os mapeamentos, ordenados por domínio
+func (s *FileStore) List() ([]models.DomainMapping, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	mappings, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return sortedMappings(mappings), nil
+}
+
+// Get retorna o mapeamento do domínio
+func (s *FileStore) Get(domain string) (models.DomainMapping, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	mappings, err := s.read()
+	if err != nil {
+		return models.DomainMapping{}, err
+	}
+	mapping, ok := mappings[domain]
+	if !ok {
+		return models.DomainMapping{}, ErrNotFound
+	}
+	return mapping, nil
+}
+
+// Put cria ou substitui o mapeamento do domínio
+func (s *FileStore) Put(mapping models.DomainMapping) error {
+	return s.modify(func(mappings map[string]models.DomainMapping) error {
+		mappings[mapping.Domain] = mapping
+		return nil
+	})
+}
+
+// Delete remove o mapeamento do domínio
+func (s *FileStore) Delete(domain string) error {
+	return s.modify(func(mappings map[string]models.DomainMapping) error {
+		if _, ok := mappings[domain]; !ok {
+			return ErrNotFound
+		}
+		delete(mappings, domain)
+		return nil
+	})
+}
+
+// CompareAndSwap aplica as alterações em uma única gravação do arquivo, com o lock entre
+// processos, se todas as versões esperadas conferirem
+func (s *FileStore) CompareAndSwap(changes ...MappingChange) error {
+	return s.modify(func(mappings map[string]models.DomainMapping) error {
+		err := checkChanges(changes, func(domain string) (models.DomainMapping, bool, error) {
+			mapping, ok := mappings[domain]
+			return mapping, ok, nil
+		})
+		if err != nil {
+			return err
+		}
+		applyChanges(mappings, changes)
+		return nil
+	})
+}
+
+// Close não mantém recursos abertos no store em arquivo
+func (s *FileStore) Close() error {
+	return nil
+}
+
+// modify relê o arquivo, aplica fn e grava o resultado com o arquivo travado, para que
+// escritas de outros processos entre a leitura e a gravação não sejam perdidas. Se fn
+// retornar erro, nada é gravado.
+func (s *FileStore) modify(fn func(mappings map[string]models.DomainMapping) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := lockFile(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	mappings, err := s.read()
+	if err != nil {
+		return err
+	}
+	if err := fn(mappings); err != nil {
+		return err
+	}
+	return s.write(mappings)
+}
+
+func (s *FileStore) read() (map[string]models.DomainMapping, error) {
+	var list []models.DomainMapping
+	if err := jsonfile.Load(s.path, &list); err != nil {
+		return nil, fmt.Errorf("erro ao ler mapeamentos: %w", err)
+	}
+
+	mappings := make(map[string]models.DomainMapping, len(list))
+	for _, mapping := range list {
+		mappings[mapping.Domain] = mapping
+	}
+	return mappings, nil
+}
+
+// write grava os mapeamentos com jsonfile.Save, que substitui o arquivo atomicamente
+func (s *FileStore) write(mappings map[string]models.DomainMapping) error {
+	if err := jsonfile.Save(s.path, sortedMappings(mappings)); err != nil {
+		return fmt.Errorf("erro ao gravar mapeamentos: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// TestFileStoreConcurrentInstances simula a API e o proxy gravando no mesmo arquivo:
+// cada instância tem seu próprio mutex, então apenas o lock do arquivo evita que uma
+// escrita sobrescreva a outra
+func TestFileStoreConcurrentInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mappings.json")
+	stores := make([]*FileStore, 4)
+	for i := range stores {
+		store, err := NewFileStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stores[i] = store
+	}
+
+	const perStore = 25
+	var wg sync.WaitGroup
+	for i, store := range stores {
+		wg.Add(1)
+		go func(i int, store *FileStore) {
+			defer wg.Done()
+			for j := 0; j < perStore; j++ {
+				mapping := models.DomainMapping{Domain: fmt.Sprintf("d%d-%d.com", i, j), Destination: "https://origem.com"}
+				if err := store.Put(mapping); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i, store)
+	}
+	wg.Wait()
+
+	mappings, err := stores[0].List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(stores) * perStore; len(mappings) != want {
+		t.Errorf("mapeamentos = %d, esperado %d", len(mappings), want)
+	}
+
+	if err := stores[1].Delete("d0-0.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := stores[2].Delete("d0-0.com"); err != ErrNotFound {
+		t.Errorf("Delete() repetido = %v, esperado ErrNotFound", err)
+	}
+}
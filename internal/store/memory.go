@@ -0,0 +1,97 @@
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// MemoryStore mantém os mapeamentos apenas em memória; eles são perdidos ao reiniciar
+type MemoryStore struct {
+	mutex    sync.RWMutex
+	mappings map[string]models.DomainMapping
+}
+
+// NewMemoryStore cria um store em memória vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{mappings: make(map[string]models.DomainMapping)}
+}
+
+// List retorna todos os mapeamentos, ordenados por domínio
+func (s *MemoryStore) List() ([]models.DomainMapping, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return sortedMappings(s.mappings), nil
+}
+
+// Get retorna o mapeamento do domínio
+func (s *MemoryStore) Get(domain string) (models.DomainMapping, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	mapping, ok := s.mappings[domain]
+	if !ok {
+		return models.DomainMapping{}, ErrNotFound
+	}
+	return mapping, nil
+}
+
+// Put cria ou substitui o mapeamento do domínio
+func (s *MemoryStore) Put(mapping models.DomainMapping) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.mappings[mapping.Domain] = mapping
+	return nil
+}
+
+// Delete remove o mapeamento do domínio
+func (s *MemoryStore) Delete(domain string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.mappings[domain]; !ok {
+		return ErrNotFound
+	}
+	delete(s.mappings, domain)
+	return nil
+}
+
+// CompareAndSwap aplica as alterações se todas as versões esperadas conferirem
+func (s *MemoryStore) CompareAndSwap(changes ...MappingChange) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := checkChanges(changes, func(domain string) (models.DomainMapping, bool, error) {
+		mapping, ok := s.mappings[domain]
+		return mapping, ok, nil
+	})
+	if err != nil {
+		return err
+	}
+	applyChanges(s.mappings, changes)
+	return nil
+}
+
+// Close não faz nada no store em memória
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func sortedMappings(mappings map[string]models.DomainMapping) []models.DomainMapping {
+	list := make([]models.DomainMapping, 0, len(mappings))
+	for _, mapping := range mappings {
+		list = append(list, mapping)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Domain < list[j].Domain })
+	return list
+}
+
+// applyChanges grava as alterações já conferidas no mapa de mapeamentos
+func applyChanges(mappings map[string]models.DomainMapping, changes []MappingChange) {
+	for _, change := range changes {
+		if change.Mapping == nil {
+			delete(mappings, change.Domain)
+		} else {
+			mappings[change.Domain] = *change.Mapping
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package store implementa o armazenamento persistente dos mapeamentos de domínio do proxy.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+// ErrNotFound indica que não existe mapeamento para o domínio informado
+var ErrNotFound = errors.New("mapeamento não encontrado")
+
+// VersionError é retornado por CompareAndSwap quando a versão gravada de um domínio
+// difere da esperada
+type VersionError struct {
+	Domain string
+	// Current é a versão gravada, ou zero se o domínio não tiver mapeamento
+	Current int64
+}
+
+func (e *VersionError) Error() string {
+	if e.Current == 0 {
+		return fmt.Sprintf("não existe mapeamento para o domínio %s", e.Domain)
+	}
+	return fmt.Sprintf("versão atual do mapeamento de %s é %d", e.Domain, e.Current)
+}
+
+// MappingChange é uma gravação condicional de CompareAndSwap
+type MappingChange struct {
+	Domain string
+	// ExpectedVersion é a versão que deve estar gravada; zero exige que o domínio não
+	// tenha mapeamento. Mapeamentos gravados sem versão contam como versão 1.
+	ExpectedVersion int64
+	// Mapping substitui o mapeamento do domínio; nil remove o domínio
+	Mapping *models.DomainMapping
+}
+
+// Tipos de armazenamento aceitos em MAPPING_STORE
+const (
+	KindMemory = "memory"
+	KindFile   = "file"
+	KindBolt   = "bolt"
+)
+
+// MappingStore persiste os mapeamentos de domínio. As implementações devem ser seguras
+// para uso concorrente; os stores em arquivo também podem ser compartilhados entre
+// processos (ex: cmd/api e cmd/proxy apontando para o mesmo caminho).
+type MappingStore interface {
+	// List retorna todos os mapeamentos, ordenados por domínio
+	List() ([]models.DomainMapping, error)
+	// Get retorna o mapeamento do domínio, ou ErrNotFound se ele não existir
+	Get(domain string) (models.DomainMapping, error)
+	// Put cria ou substitui o mapeamento do domínio
+	Put(mapping models.DomainMapping) error
+	// Delete remove o mapeamento do domínio, retornando ErrNotFound se ele não existir
+	Delete(domain string) error
+	// CompareAndSwap aplica as alterações em uma única operação, atômica também entre
+	// processos: se a versão de algum domínio divergir da esperada, retorna *VersionError
+	// e nada é gravado
+	CompareAndSwap(changes ...MappingChange) error
+	// Close libera os recursos do store
+	Close() error
+}
+
+// storedVersion retorna a versão do mapeamento gravado, tratando mapeamentos gravados
+// antes do controle de versão como versão 1
+func storedVersion(mapping models.DomainMapping, exists bool) int64 {
+	switch {
+	case !exists:
+		return 0
+	case mapping.Version == 0:
+		return 1
+	default:
+		return mapping.Version
+	}
+}
+
+// checkChanges confere as versões esperadas de todas as alterações antes de qualquer gravação
+func checkChanges(changes []MappingChange, get func(domain string) (models.DomainMapping, bool, error)) error {
+	for _, change := range changes {
+		current, exists, err := get(change.Domain)
+		if err != nil {
+			return err
+		}
+		if version := storedVersion(current, exists); version != change.ExpectedVersion {
+			return &VersionError{Domain: change.Domain, Current: version}
+		}
+	}
+	return nil
+}
+
+// New cria o store do tipo informado. path é ignorado pelo store em memória.
+func New(kind, path string) (MappingStore, error) {
+	switch strings.ToLower(kind) {
+	case "", KindMemory:
+		return NewMemoryStore(), nil
+	case KindFile:
+		if path == "" {
+			path = "mappings.json"
+		}
+		return NewFileStore(path)
+	case KindBolt:
+		if path == "" {
+			path = "mappings.db"
+		}
+		return NewBoltStore(path)
+	default:
+		return nil, fmt.Errorf("tipo de store de mapeamentos desconhecido: %q (use memory, file ou bolt)", kind)
+	}
+}
+
+// NewFromEnv cria o store configurado pelas variáveis MAPPING_STORE e MAPPING_STORE_PATH
+func NewFromEnv() (MappingStore, error) {
+	return New(os.Getenv("MAPPING_STORE"), os.Getenv("MAPPING_STORE_PATH"))
+}
+
+// DefaultRefreshInterval é o intervalo padrão para recarregar mapeamentos de stores em arquivo
+const DefaultRefreshInterval = 5 * time.Second
+
+// RefreshIntervalFromEnv retorna o intervalo de recarga definido em MAPPING_STORE_REFRESH.
+// O store em memória não é compartilhado entre processos, então nesse caso retorna zero.
+func RefreshIntervalFromEnv() (time.Duration, error) {
+	if kind := strings.ToLower(os.Getenv("MAPPING_STORE")); kind == "" || kind == KindMemory {
+		return 0, nil
+	}
+
+	value := os.Getenv("MAPPING_STORE_REFRESH")
+	if value == "" {
+		return DefaultRefreshInterval, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("valor inválido para MAPPING_STORE_REFRESH: %w", err)
+	}
+	return interval, nil
+}
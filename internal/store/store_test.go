@@ -0,0 +1,67 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+func TestCompareAndSwap(t *testing.T) {
+	kinds := []string{KindMemory, KindFile, KindBolt}
+	for _, kind := range kinds {
+		t.Run(kind, func(t *testing.T) {
+			store, err := New(kind, filepath.Join(t.TempDir(), "mappings"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+
+			legacy := models.DomainMapping{Domain: "antigo.com", Destination: "https://origem.com"}
+			if err := store.Put(legacy); err != nil {
+				t.Fatal(err)
+			}
+			a := models.DomainMapping{Domain: "a.com", Destination: "https://a.origem.com", Version: 1}
+			if err := store.CompareAndSwap(MappingChange{Domain: "a.com", Mapping: &a}); err != nil {
+				t.Fatalf("criação: %v", err)
+			}
+
+			// A segunda alteração falha, então a primeira também não pode ser gravada
+			b := models.DomainMapping{Domain: "b.com", Destination: "https://b.origem.com", Version: 1}
+			err = store.CompareAndSwap(
+				MappingChange{Domain: "b.com", Mapping: &b},
+				MappingChange{Domain: "a.com", Mapping: &a},
+			)
+			var versionErr *VersionError
+			if !errors.As(err, &versionErr) || versionErr.Domain != "a.com" || versionErr.Current != 1 {
+				t.Fatalf("CompareAndSwap() = %v, esperado VersionError de a.com na versão 1", err)
+			}
+			if _, err := store.Get("b.com"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("b.com gravado apesar do conflito: %v", err)
+			}
+
+			// Mapeamentos sem versão contam como versão 1
+			legacy.Version = 2
+			if err := store.CompareAndSwap(
+				MappingChange{Domain: "antigo.com", ExpectedVersion: 1, Mapping: &legacy},
+				MappingChange{Domain: "b.com", Mapping: &b},
+				MappingChange{Domain: "a.com", ExpectedVersion: 1},
+			); err != nil {
+				t.Fatalf("lote: %v", err)
+			}
+			mappings, err := store.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mappings) != 2 || mappings[0].Domain != "antigo.com" || mappings[0].Version != 2 || mappings[1].Domain != "b.com" {
+				t.Errorf("mapeamentos = %+v", mappings)
+			}
+
+			err = store.CompareAndSwap(MappingChange{Domain: "a.com", ExpectedVersion: 1})
+			if !errors.As(err, &versionErr) || versionErr.Current != 0 {
+				t.Errorf("remoção de domínio inexistente = %v, esperado VersionError com versão 0", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package gocache_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache/gocachetest"
+)
+
+var quietLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestClientAPIError(t *testing.T) {
+	srv := gocachetest.NewServer()
+	defer srv.Close()
+	client := srv.Client(gocache.WithLogger(quietLogger))
+
+	var result map[string]interface{}
+	_, err := client.GetContext(context.Background(), "/domain/inexistente.com", &result)
+	apiErr, ok := gocache.AsAPIError(err)
+	if !ok {
+		t.Fatalf("erro = %v, esperado *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "domain not found" {
+		t.Errorf("APIError = %+v, esperado 404 domain not found", apiErr)
+	}
+	if !gocache.IsNotFound(err) {
+		t.Error("IsNotFound() = false, esperado true")
+	}
+
+	srv.AddDomain("a.com", nil)
+	if _, err := client.GetContext(context.Background(), "/domain/a.com", &result); err != nil {
+		t.Fatalf("GetContext() = %v", err)
+	}
+	if result["response"].(map[string]interface{})["domain"] != "a.com" {
+		t.Errorf("resposta = %v", result)
+	}
+}
+
+func TestClientInvalidToken(t *testing.T) {
+	srv := gocachetest.NewServer()
+	defer srv.Close()
+	srv.Fail(gocachetest.Failure{Path: "/domain", Status: http.StatusServiceUnavailable, Times: 1})
+
+	invalid, err := gocache.NewClient(srv.URL, "outro-token", gocache.WithRetryPolicy(gocache.NoRetry()), gocache.WithLogger(quietLogger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = invalid.GetContext(context.Background(), "/domain", nil)
+	if apiErr, ok := gocache.AsAPIError(err); !ok || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("erro = %v, esperado 401", err)
+	}
+
+	// A falha programada continua reservada para a próxima requisição autenticada
+	client := srv.Client(gocache.WithLogger(quietLogger))
+	_, err = client.GetContext(context.Background(), "/domain", nil)
+	if apiErr, ok := gocache.AsAPIError(err); !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("erro = %v, esperado 503", err)
+	}
+	if _, err := client.GetContext(context.Background(), "/domain", nil); err != nil {
+		t.Errorf("após consumir a falha: %v", err)
+	}
+}
+
+func TestClientRetryAgainstFakeServer(t *testing.T) {
+	srv := gocachetest.NewServer()
+	defer srv.Close()
+	srv.AddDomain("a.com", nil)
+	srv.Fail(gocachetest.Failure{Method: http.MethodGet, Path: "/domain", Status: http.StatusTooManyRequests, RetryAfter: "0", Times: 2})
+
+	client := srv.Client(
+		gocache.WithRetryPolicy(gocache.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second, RetryableStatus: []int{429}}),
+		gocache.WithLogger(quietLogger),
+	)
+	if _, err := client.GetContext(context.Background(), "/domain", nil); err != nil {
+		t.Fatalf("GetContext() = %v, esperado sucesso após as novas tentativas", err)
+	}
+	if got := len(srv.Requests()); got != 3 {
+		t.Errorf("requisições = %d, esperado 3", got)
+	}
+}
+
+func TestClientFormBody(t *testing.T) {
+	srv := gocachetest.NewServer()
+	defer srv.Close()
+	client := srv.Client(gocache.WithLogger(quietLogger))
+
+	body := struct {
+		Content string   `form:"content-type"`
+		URLs    []string `form:"urls"`
+	}{"*", []string{"https://a.com/x", "https://a.com/y"}}
+	if _, err := client.DeleteContext(context.Background(), "/cache/a.com", body, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	purges := srv.Purges()
+	if len(purges) != 1 || purges[0].ContentType != "*" || len(purges[0].URLs) != 2 || purges[0].URLs[1] != "https://a.com/y" {
+		t.Errorf("expirações = %+v", purges)
+	}
+}
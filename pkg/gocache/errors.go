@@ -0,0 +1,102 @@
+package gocache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// APIError representa uma resposta não-2xx da API da Gocache
+type APIError struct {
+	StatusCode int    // Código HTTP retornado pela Gocache
+	Message    string // Mensagem de erro extraída do corpo da resposta
+	RequestID  string // Identificador da requisição, quando enviado pela Gocache
+	Body       string // Corpo bruto da resposta, útil para diagnóstico
+}
+
+// Error implementa a interface error
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("gocache: %d %s (request_id=%s)", e.StatusCode, msg, e.RequestID)
+	}
+	return fmt.Sprintf("gocache: %d %s", e.StatusCode, msg)
+}
+
+// AsAPIError extrai um *APIError da cadeia de erros, se houver
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound indica se o erro corresponde a um 404 da Gocache
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// checkResponse retorna um *APIError quando a resposta não for 2xx
+func checkResponse(resp *resty.Response) error {
+	if resp == nil || resp.IsSuccess() {
+		return nil
+	}
+
+	return &APIError{
+		StatusCode: resp.StatusCode(),
+		Message:    extractErrorMessage(resp.Body()),
+		RequestID:  resp.Header().Get("X-Request-Id"),
+		Body:       resp.String(),
+	}
+}
+
+// extractErrorMessage tenta obter a mensagem de erro nos formatos usados pela Gocache:
+// {"response": {"msg": "..."}}, {"response": "..."}, {"msg": "..."} ou {"error": "..."}
+func extractErrorMessage(body []byte) string {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+
+	if msg := messageFrom(payload["response"]); msg != "" {
+		return msg
+	}
+	for _, key := range []string{"msg", "message", "error", "errors"} {
+		if msg := messageFrom(payload[key]); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// messageFrom converte os diferentes formatos de mensagem em uma string
+func messageFrom(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if msg := messageFrom(item); msg != "" {
+				parts = append(parts, msg)
+			}
+		}
+		return strings.Join(parts, "; ")
+	case map[string]interface{}:
+		for _, key := range []string{"msg", "message", "error", "errors"} {
+			if msg := messageFrom(v[key]); msg != "" {
+				return msg
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,261 @@
+package gocache
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Encoder converte o body de uma requisição em dados de formulário
+type Encoder interface {
+	Encode(v interface{}) (url.Values, error)
+}
+
+// FormMarshaler permite que um tipo controle sua própria codificação no formulário.
+// A implementação recebe a chave já resolvida (ex: "match[host]") e adiciona seus valores.
+type FormMarshaler interface {
+	MarshalForm(key string, values url.Values) error
+}
+
+// FormEncoder codifica structs, maps e slices no formato de formulário esperado pela Gocache.
+//
+// Regras de codificação:
+//   - o nome do campo vem da tag "form", depois da tag "json" e por último do nome do campo;
+//   - structs e maps aninhados geram chaves no formato pai[filho];
+//   - slices geram pai[0], pai[1]... ou pai[] repetido quando a tag tiver a opção "brackets";
+//   - a opção "omitempty" ignora valores zero e ponteiros nil são sempre ignorados;
+//   - tipos que implementam FormMarshaler ou encoding.TextMarshaler codificam a si mesmos.
+type FormEncoder struct {
+	// TagName é a tag consultada primeiro para obter o nome do campo (padrão "form")
+	TagName string
+}
+
+// NewFormEncoder cria um FormEncoder com a configuração padrão
+func NewFormEncoder() *FormEncoder {
+	return &FormEncoder{TagName: "form"}
+}
+
+var (
+	formMarshalerType = reflect.TypeOf((*FormMarshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Encode converte v em url.Values
+func (e *FormEncoder) Encode(v interface{}) (url.Values, error) {
+	values := url.Values{}
+
+	switch body := v.(type) {
+	case nil:
+		return values, nil
+	case url.Values:
+		return body, nil
+	case map[string]string:
+		for key, value := range body {
+			values.Set(key, value)
+		}
+		return values, nil
+	}
+
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return values, nil
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		if err := e.encodeStruct("", val, values); err != nil {
+			return nil, err
+		}
+	case reflect.Map:
+		if err := e.encodeMap("", val, values); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("body deve ser uma struct, um map ou ponteiro para um deles, recebido %s", val.Kind())
+	}
+
+	return values, nil
+}
+
+// formField descreve como um campo de struct deve ser codificado
+type formField struct {
+	name      string
+	omitEmpty bool
+	brackets  bool
+}
+
+// parseField lê as tags do campo e retorna suas opções de codificação
+func (e *FormEncoder) parseField(field reflect.StructField) (formField, bool) {
+	tagName := e.TagName
+	if tagName == "" {
+		tagName = "form"
+	}
+
+	tag, ok := field.Tag.Lookup(tagName)
+	if !ok {
+		tag, ok = field.Tag.Lookup("json")
+	}
+	if tag == "-" {
+		return formField{}, false
+	}
+
+	parts := strings.Split(tag, ",")
+	info := formField{name: parts[0]}
+	if !ok || info.name == "" {
+		info.name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty":
+			info.omitEmpty = true
+		case "brackets":
+			info.brackets = true
+		}
+	}
+	return info, true
+}
+
+// joinKey monta a chave aninhada no formato pai[filho]
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "[" + name + "]"
+}
+
+func (e *FormEncoder) encodeStruct(prefix string, val reflect.Value, values url.Values) error {
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		info, ok := e.parseField(field)
+		if !ok {
+			continue
+		}
+
+		fieldValue := val.Field(i)
+		if info.omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+
+		key := joinKey(prefix, info.name)
+		if err := e.encodeValue(key, fieldValue, info, values); err != nil {
+			return fmt.Errorf("campo %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (e *FormEncoder) encodeMap(prefix string, val reflect.Value, values url.Values) error {
+	if val.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("maps devem ter chaves do tipo string, recebido %s", val.Type().Key())
+	}
+
+	// Ordena as chaves para que a codificação seja determinística
+	keys := val.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	for _, k := range keys {
+		key := joinKey(prefix, k.String())
+		if err := e.encodeValue(key, val.MapIndex(k), formField{}, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *FormEncoder) encodeValue(key string, val reflect.Value, info formField, values url.Values) error {
+	// Ponteiros e interfaces nil não são enviados
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		if ok, err := e.encodeCustom(key, val, values); ok {
+			return err
+		}
+		val = val.Elem()
+	}
+
+	if ok, err := e.encodeCustom(key, val, values); ok {
+		return err
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		return e.encodeStruct(key, val, values)
+	case reflect.Map:
+		return e.encodeMap(key, val, values)
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8 {
+			values.Add(key, string(val.Bytes()))
+			return nil
+		}
+		for j := 0; j < val.Len(); j++ {
+			itemKey := fmt.Sprintf("%s[%d]", key, j)
+			if info.brackets {
+				itemKey = key + "[]"
+			}
+			if err := e.encodeValue(itemKey, val.Index(j), formField{}, values); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	str, err := scalarString(val)
+	if err != nil {
+		return err
+	}
+	values.Add(key, str)
+	return nil
+}
+
+// encodeCustom usa FormMarshaler ou encoding.TextMarshaler quando o tipo os implementa
+func (e *FormEncoder) encodeCustom(key string, val reflect.Value, values url.Values) (bool, error) {
+	if !val.CanInterface() {
+		return false, nil
+	}
+
+	if val.Type().Implements(formMarshalerType) {
+		return true, val.Interface().(FormMarshaler).MarshalForm(key, values)
+	}
+	if val.Type().Implements(textMarshalerType) {
+		text, err := val.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return true, err
+		}
+		values.Add(key, string(text))
+		return true, nil
+	}
+	return false, nil
+}
+
+// scalarString converte um valor escalar em string
+func scalarString(val reflect.Value) (string, error) {
+	switch val.Kind() {
+	case reflect.String:
+		return val.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), nil
+	default:
+		return "", fmt.Errorf("tipo não suportado para codificação de formulário: %s", val.Kind())
+	}
+}
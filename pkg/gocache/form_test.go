@@ -0,0 +1,233 @@
+package gocache
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/internal/models"
+)
+
+type formCode string
+
+func (c formCode) MarshalText() ([]byte, error) {
+	if c == "" {
+		return nil, errors.New("código vazio")
+	}
+	return []byte(strings.ToUpper(string(c))), nil
+}
+
+type formPair [2]string
+
+func (p formPair) MarshalForm(key string, values url.Values) error {
+	values.Add(key+"[min]", p[0])
+	values.Add(key+"[max]", p[1])
+	return nil
+}
+
+type formInner struct {
+	Host  string   `form:"host,omitempty"`
+	Items []string `form:"items,omitempty"`
+}
+
+type formOuter struct {
+	Name     string `form:"name"`
+	JSONOnly string `json:"json_only,omitempty"`
+	NoTag    int
+	Skipped  string            `form:"-"`
+	Empty    string            `form:"empty,omitempty"`
+	Zero     int               `form:"zero"`
+	Enabled  *bool             `form:"enabled"`
+	TTL      *int              `form:"ttl,omitempty"`
+	Match    formInner         `form:"match"`
+	Methods  []string          `form:"methods,brackets"`
+	Nested   []formInner       `form:"nested,omitempty"`
+	Labels   map[string]string `form:"labels,omitempty"`
+	Code     formCode          `form:"code,omitempty"`
+	Range    *formPair         `form:"range"`
+	private  string
+}
+
+func TestFormEncoderEncode(t *testing.T) {
+	enabled := false
+	tests := []struct {
+		name string
+		body interface{}
+		want url.Values
+	}{
+		{"nil", nil, url.Values{}},
+		{"url.Values repassado", url.Values{"a": {"1", "2"}}, url.Values{"a": {"1", "2"}}},
+		{"map[string]string", map[string]string{"a": "1"}, url.Values{"a": {"1"}}},
+		{"ponteiro nil", (*formOuter)(nil), url.Values{}},
+		{
+			"tags, omitempty e ponteiros nil",
+			formOuter{Name: "n", JSONOnly: "j", NoTag: 3, Skipped: "x", private: "p"},
+			url.Values{"name": {"n"}, "json_only": {"j"}, "NoTag": {"3"}, "zero": {"0"}},
+		},
+		{
+			"ponteiro preenchido",
+			&formOuter{Enabled: &enabled},
+			url.Values{"name": {""}, "NoTag": {"0"}, "zero": {"0"}, "enabled": {"false"}},
+		},
+		{
+			"structs aninhadas e slices",
+			formOuter{
+				Match:   formInner{Host: "a.com", Items: []string{"x", "y"}},
+				Methods: []string{"GET", "POST"},
+				Nested:  []formInner{{Host: "b.com"}, {Items: []string{"z"}}},
+			},
+			url.Values{
+				"name": {""}, "NoTag": {"0"}, "zero": {"0"},
+				"match[host]":         {"a.com"},
+				"match[items][0]":     {"x"},
+				"match[items][1]":     {"y"},
+				"methods[]":           {"GET", "POST"},
+				"nested[0][host]":     {"b.com"},
+				"nested[1][items][0]": {"z"},
+			},
+		},
+		{
+			"maps, TextMarshaler e FormMarshaler",
+			formOuter{Labels: map[string]string{"b": "2", "a": "1"}, Code: "br", Range: &formPair{"1", "9"}},
+			url.Values{
+				"name": {""}, "NoTag": {"0"}, "zero": {"0"},
+				"labels[a]": {"1"}, "labels[b]": {"2"},
+				"code":       {"BR"},
+				"range[min]": {"1"}, "range[max]": {"9"},
+			},
+		},
+	}
+
+	encoder := NewFormEncoder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encoder.Encode(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Encode() = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormEncoderErrors(t *testing.T) {
+	encoder := NewFormEncoder()
+	for name, body := range map[string]interface{}{
+		"tipo escalar":          "texto",
+		"map com chave int":     map[int]string{1: "a"},
+		"tipo não suportado":    struct{ C chan int }{make(chan int)},
+		"erro do TextMarshaler": struct{ Code formCode }{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := encoder.Encode(body); err == nil {
+				t.Errorf("Encode(%#v) não retornou erro", body)
+			}
+		})
+	}
+}
+
+// TestFormEncoderParity garante que os modelos de requisição existentes continuam gerando o
+// mesmo formulário da conversão por reflection que o FormEncoder substituiu
+func TestFormEncoderParity(t *testing.T) {
+	tests := []struct {
+		name string
+		body interface{}
+	}{
+		{"redirecionamento", &models.RedirectCreateRequest{Domain: "a.com", Source: "/old", Destination: "https://a.com/new", Type: 301}},
+		{"criação de DNS", models.DNSCreateRequest{Name: "www", Type: "A", Content: "1.2.3.4", TTL: 300, Cloud: 1, Domain: "a.com"}},
+		{"alteração de DNS", models.DNSUpdateRequest{Name: "@", Type: "TXT", Content: "v=spf1 -all", TTL: 3600}},
+	}
+
+	encoder := NewFormEncoder()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encoder.Encode(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := legacyFormData(tt.body)
+			if len(got) != len(want) {
+				t.Fatalf("Encode() = %v, esperado %v", got, want)
+			}
+			for key, value := range want {
+				if got.Get(key) != value || len(got[key]) != 1 {
+					t.Errorf("%s = %v, esperado %q", key, got[key], value)
+				}
+			}
+		})
+	}
+
+	// O formulário de Smart Rules era montado manualmente com essas chaves
+	rule := models.SmartRuleRewriteCreateRequest{
+		Domain: "a.com",
+		Match:  models.SmartRuleRewriteMatch{RequestURI: "/app*", RequestMethods: []string{"GET"}, DeviceTypes: []string{"mobile"}, Host: "a.com"},
+		Action: models.SmartRuleRewriteAction{RewriteURI: "/b/app", RewriteHost: "b.s3.com", Destination: "b.s3.com", CrossOrigin: "*", SSLMode: "partial"},
+	}
+	want := url.Values{
+		"match[request_uri]":      {"/app*"},
+		"match[request_method][]": {"GET"},
+		"match[device_type][0]":   {"mobile"},
+		"match[host]":             {"a.com"},
+		"action[set_uri]":         {"/b/app"},
+		"action[set_host]":        {"b.s3.com"},
+		"action[backend]":         {"b.s3.com"},
+		"action[cors]":            {"*"},
+		"action[ssl_mode]":        {"partial"},
+	}
+	got, err := encoder.Encode(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Encode(regra) = %v, esperado %v", got, want)
+	}
+}
+
+// legacyFormData reproduz a conversão por reflection usada antes do FormEncoder
+func legacyFormData(body interface{}) map[string]string {
+	formData := make(map[string]string)
+	val := reflect.ValueOf(body)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		formName := field.Tag.Get("form")
+		if formName == "" {
+			formName = field.Tag.Get("json")
+			if formName == "" {
+				formName = field.Name
+			} else {
+				formName = strings.Split(formName, ",")[0]
+			}
+		}
+		if formName == "-" {
+			continue
+		}
+
+		fieldValue := val.Field(i)
+		switch fieldValue.Kind() {
+		case reflect.String:
+			if fieldValue.String() != "" {
+				formData[formName] = fieldValue.String()
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			formData[formName] = strconv.FormatInt(fieldValue.Int(), 10)
+		case reflect.Slice, reflect.Array:
+			for j := 0; j < fieldValue.Len(); j++ {
+				formData[fmt.Sprintf("%s[%d]", formName, j)] = fmt.Sprint(fieldValue.Index(j).Interface())
+			}
+		}
+	}
+	return formData
+}
@@ -0,0 +1,403 @@
+package gocachetest
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// parseForm lê o formulário da requisição. Diferente de http.Request.ParseForm,
+// também interpreta o body de requisições DELETE, usado pela rota de cache.
+func parseForm(r *http.Request) url.Values {
+	if r.Method != http.MethodDelete {
+		_ = r.ParseForm()
+		return r.PostForm
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/x-www-form-urlencoded" {
+		return url.Values{}
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return url.Values{}
+	}
+	values, _ := url.ParseQuery(string(body))
+	r.PostForm = values
+	return values
+}
+
+func (s *Server) listDomains(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	names := s.domainNames()
+	s.mu.Unlock()
+
+	writeOK(w, map[string]interface{}{
+		"domains":        names,
+		"size":           len(names),
+		"auto_discovery": map[string]interface{}{},
+	})
+}
+
+func (s *Server) getDomain(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("domain")
+
+	s.mu.Lock()
+	settings, ok := s.domains[name]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "domain not found")
+		return
+	}
+	response := flatten(settings)
+	response["domain"] = name
+	writeOK(w, response)
+}
+
+func (s *Server) createDomain(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("domain")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.domains[name]; ok {
+		writeError(w, http.StatusConflict, "domain already exists")
+		return
+	}
+	settings := url.Values{}
+	for key, vals := range r.PostForm {
+		settings[key] = append([]string(nil), vals...)
+	}
+	s.domains[name] = settings
+
+	response := flatten(settings)
+	response["domain"] = name
+	writeOK(w, response)
+}
+
+func (s *Server) updateDomain(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("domain")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settings, ok := s.domains[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "domain not found")
+		return
+	}
+	for key, vals := range r.PostForm {
+		settings[key] = append([]string(nil), vals...)
+	}
+
+	response := flatten(settings)
+	response["domain"] = name
+	writeOK(w, response)
+}
+
+func (s *Server) deleteDomain(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("domain")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.domains[name]; !ok {
+		writeError(w, http.StatusNotFound, "domain not found")
+		return
+	}
+	delete(s.domains, name)
+	delete(s.dns, name)
+	delete(s.redirects, name)
+	delete(s.rules, name)
+	writeOK(w, map[string]string{"msg": "domain deleted"})
+}
+
+// dnsJSON converte um registro para o formato retornado pela Gocache
+func dnsJSON(record DNSRecord) map[string]string {
+	return map[string]string{
+		"record_id": record.ID,
+		"name":      record.Name,
+		"type":      record.Type,
+		"content":   record.Content,
+		"ttl":       record.TTL,
+		"cloud":     record.Cloud,
+	}
+}
+
+func (s *Server) listDNS(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("domain")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Um segmento numérico é tratado como ID de registro (rota GET /dns/{id})
+	if _, err := strconv.Atoi(key); err == nil {
+		if _, record, ok := s.findDNS("", key); ok {
+			writeOK(w, map[string]interface{}{"records": []map[string]string{dnsJSON(record)}})
+			return
+		}
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+
+	records := make([]map[string]string, 0, len(s.dns[key]))
+	for _, record := range s.dns[key] {
+		records = append(records, dnsJSON(record))
+	}
+	writeOK(w, map[string]interface{}{"records": records})
+}
+
+func (s *Server) createDNS(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	form := r.PostForm
+
+	if form.Get("name") == "" || form.Get("type") == "" || form.Get("content") == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name, type and content are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := DNSRecord{
+		ID:      s.newID(),
+		Name:    form.Get("name"),
+		Type:    form.Get("type"),
+		Content: form.Get("content"),
+		TTL:     form.Get("ttl"),
+		Cloud:   form.Get("cloud"),
+	}
+	s.dns[domain] = append(s.dns[domain], record)
+	writeOK(w, map[string]interface{}{"records": []map[string]string{dnsJSON(record)}})
+}
+
+func (s *Server) updateDNS(w http.ResponseWriter, r *http.Request) {
+	domain, id := r.PathValue("domain"), r.PathValue("id")
+	form := r.PostForm
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owner, record, ok := s.findDNS(domain, id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+
+	for field, target := range map[string]*string{
+		"name": &record.Name, "type": &record.Type, "content": &record.Content,
+		"ttl": &record.TTL, "cloud": &record.Cloud,
+	} {
+		if value := form.Get(field); value != "" {
+			*target = value
+		}
+	}
+	for i := range s.dns[owner] {
+		if s.dns[owner][i].ID == id {
+			s.dns[owner][i] = record
+		}
+	}
+	writeOK(w, map[string]interface{}{"records": []map[string]string{dnsJSON(record)}})
+}
+
+func (s *Server) deleteDNS(w http.ResponseWriter, r *http.Request) {
+	domain, id := r.PathValue("domain"), r.PathValue("id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owner, _, ok := s.findDNS(domain, id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+
+	records := s.dns[owner][:0]
+	for _, record := range s.dns[owner] {
+		if record.ID != id {
+			records = append(records, record)
+		}
+	}
+	s.dns[owner] = records
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status_code": http.StatusOK,
+		"response":    "record deleted",
+	})
+}
+
+// findDNS localiza um registro pelo ID, opcionalmente restrito a um domínio.
+// Deve ser chamada com s.mu travado.
+func (s *Server) findDNS(domain, id string) (string, DNSRecord, bool) {
+	for owner, records := range s.dns {
+		if domain != "" && owner != domain {
+			continue
+		}
+		for _, record := range records {
+			if record.ID == id {
+				return owner, record, true
+			}
+		}
+	}
+	return "", DNSRecord{}, false
+}
+
+func (s *Server) purgeAll(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	s.mu.Lock()
+	s.purges = append(s.purges, Purge{Domain: domain, All: true})
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": true, "message": "cache purged"})
+}
+
+func (s *Server) purgeURLs(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	form := r.PostForm
+
+	urls := indexedValues(form, "urls")
+	if len(urls) == 0 {
+		writeError(w, http.StatusBadRequest, "urls is required")
+		return
+	}
+
+	s.mu.Lock()
+	s.purges = append(s.purges, Purge{Domain: domain, URLs: urls, ContentType: form.Get("content-type")})
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": true, "message": "cache purged"})
+}
+
+func (s *Server) listRedirects(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	s.mu.Lock()
+	redirects := append([]Redirect{}, s.redirects[domain]...)
+	s.mu.Unlock()
+
+	writeOK(w, redirects)
+}
+
+func (s *Server) createRedirect(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	form := r.PostForm
+
+	redirectType, _ := strconv.Atoi(form.Get("type"))
+	redirect := Redirect{
+		Domain:      domain,
+		Source:      form.Get("source"),
+		Destination: form.Get("destination"),
+		Type:        redirectType,
+	}
+	if redirect.Source == "" || redirect.Destination == "" {
+		writeError(w, http.StatusUnprocessableEntity, "source and destination are required")
+		return
+	}
+
+	s.mu.Lock()
+	redirect.ID, _ = strconv.Atoi(s.newID())
+	s.redirects[domain] = append(s.redirects[domain], redirect)
+	s.mu.Unlock()
+
+	writeOK(w, strconv.Itoa(redirect.ID))
+}
+
+func (s *Server) deleteRedirect(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	redirects := s.redirects[domain]
+	for i, redirect := range redirects {
+		if redirect.ID == id {
+			s.redirects[domain] = append(redirects[:i], redirects[i+1:]...)
+			writeOK(w, "redirect deleted")
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "redirect not found")
+}
+
+// ruleJSON converte uma regra para o formato retornado pela Gocache
+func ruleJSON(rule Rule) map[string]interface{} {
+	return map[string]interface{}{
+		"id":       rule.ID,
+		"match":    flatten(rule.Match),
+		"action":   flatten(rule.Action),
+		"metadata": map[string]string{"status": "active"},
+	}
+}
+
+func (s *Server) listRules(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+
+	s.mu.Lock()
+	rules := make([]map[string]interface{}, 0, len(s.rules[domain]))
+	for _, rule := range s.rules[domain] {
+		rules = append(rules, ruleJSON(rule))
+	}
+	s.mu.Unlock()
+
+	writeOK(w, map[string]interface{}{"rules": rules})
+}
+
+func (s *Server) createRule(w http.ResponseWriter, r *http.Request) {
+	domain := r.PathValue("domain")
+	rule := Rule{
+		Match:  nestedValues(r.PostForm, "match"),
+		Action: nestedValues(r.PostForm, "action"),
+	}
+	if len(rule.Match) == 0 || len(rule.Action) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "match and action are required")
+		return
+	}
+
+	s.mu.Lock()
+	rule.ID = s.newID()
+	s.rules[domain] = append(s.rules[domain], rule)
+	s.mu.Unlock()
+
+	writeOK(w, map[string]string{"id": rule.ID})
+}
+
+func (s *Server) updateRule(w http.ResponseWriter, r *http.Request) {
+	domain, id := r.PathValue("domain"), r.PathValue("id")
+	match := nestedValues(r.PostForm, "match")
+	action := nestedValues(r.PostForm, "action")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, rule := range s.rules[domain] {
+		if rule.ID != id {
+			continue
+		}
+		for key, vals := range match {
+			rule.Match[key] = vals
+		}
+		for key, vals := range action {
+			rule.Action[key] = vals
+		}
+		s.rules[domain][i] = rule
+		writeOK(w, map[string]string{"msg": "rule updated"})
+		return
+	}
+	writeError(w, http.StatusNotFound, "rule not found")
+}
+
+func (s *Server) deleteRule(w http.ResponseWriter, r *http.Request) {
+	domain, id := r.PathValue("domain"), r.PathValue("id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rules := s.rules[domain]
+	for i, rule := range rules {
+		if rule.ID == id {
+			s.rules[domain] = append(rules[:i], rules[i+1:]...)
+			writeOK(w, map[string]string{"msg": "rule deleted"})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "rule not found")
+}
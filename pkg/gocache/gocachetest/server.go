@@ -0,0 +1,403 @@
+// Package gocachetest fornece um servidor falso da API da Gocache para testes.
+//
+// O Server emula as rotas /domain, /dns, /cache, /redirects e /rules/settings com
+// estado em memória, interpreta os formulários enviados pelo gocache.Client e permite
+// programar falhas para exercitar o tratamento de erros sem acesso à rede.
+package gocachetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
+)
+
+// DefaultToken é o token aceito pelo servidor quando nenhum outro é configurado
+const DefaultToken = "gocachetest-token"
+
+// DNSRecord representa um registro DNS armazenado no servidor falso
+type DNSRecord struct {
+	ID      string
+	Name    string
+	Type    string
+	Content string
+	TTL     string
+	Cloud   string
+}
+
+// Redirect representa uma regra de redirecionamento armazenada no servidor falso
+type Redirect struct {
+	ID          int    `json:"id"`
+	Domain      string `json:"domain"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Type        int    `json:"type"`
+}
+
+// Rule representa uma smart rule de rewrite armazenada no servidor falso.
+// Match e Action guardam os campos exatamente como recebidos no formulário.
+type Rule struct {
+	ID     string
+	Match  url.Values
+	Action url.Values
+}
+
+// Purge registra uma chamada de expiração de cache recebida pelo servidor
+type Purge struct {
+	Domain      string
+	All         bool
+	URLs        []string
+	ContentType string
+}
+
+// Request registra uma requisição recebida pelo servidor
+type Request struct {
+	Method string
+	Path   string
+	Form   url.Values
+}
+
+// Failure descreve uma falha programada. Requisições cujo método e path
+// correspondem recebem Status e Message em vez da resposta normal.
+type Failure struct {
+	Method     string // Método HTTP; vazio corresponde a qualquer método
+	Path       string // Prefixo do path (ex: "/dns/example.com"); vazio corresponde a qualquer path
+	Status     int
+	Message    string
+	RetryAfter string // Valor do header Retry-After, se informado
+	Times      int    // Quantidade de vezes que a falha ocorre; 0 significa sempre
+}
+
+// Server é um servidor HTTP que emula a API da Gocache
+type Server struct {
+	*httptest.Server
+
+	Token string
+
+	mu        sync.Mutex
+	nextID    int
+	domains   map[string]url.Values
+	dns       map[string][]DNSRecord
+	redirects map[string][]Redirect
+	rules     map[string][]Rule
+	purges    []Purge
+	requests  []Request
+	failures  []*Failure
+}
+
+// NewServer inicia um novo servidor falso. Use Close ao final do teste.
+func NewServer() *Server {
+	s := &Server{
+		Token:     DefaultToken,
+		nextID:    1,
+		domains:   make(map[string]url.Values),
+		dns:       make(map[string][]DNSRecord),
+		redirects: make(map[string][]Redirect),
+		rules:     make(map[string][]Rule),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /domain", s.listDomains)
+	mux.HandleFunc("GET /domain/{domain}", s.getDomain)
+	mux.HandleFunc("POST /domain/{domain}", s.createDomain)
+	mux.HandleFunc("PUT /domain/{domain}", s.updateDomain)
+	mux.HandleFunc("PATCH /domain/{domain}", s.updateDomain)
+	mux.HandleFunc("DELETE /domain/{domain}", s.deleteDomain)
+
+	mux.HandleFunc("GET /dns/{domain}", s.listDNS)
+	mux.HandleFunc("POST /dns/{domain}", s.createDNS)
+	mux.HandleFunc("PUT /dns/{id}", s.updateDNS)
+	mux.HandleFunc("PUT /dns/{domain}/{id}", s.updateDNS)
+	mux.HandleFunc("DELETE /dns/{id}", s.deleteDNS)
+	mux.HandleFunc("DELETE /dns/{domain}/{id}", s.deleteDNS)
+
+	mux.HandleFunc("DELETE /cache/{domain}", s.purgeURLs)
+	mux.HandleFunc("DELETE /cache/{domain}/all", s.purgeAll)
+
+	mux.HandleFunc("GET /redirects/{domain}", s.listRedirects)
+	mux.HandleFunc("POST /redirects/{domain}", s.createRedirect)
+	mux.HandleFunc("DELETE /redirects/{domain}/{id}", s.deleteRedirect)
+
+	mux.HandleFunc("GET /rules/settings/{domain}", s.listRules)
+	mux.HandleFunc("POST /rules/settings/{domain}", s.createRule)
+	mux.HandleFunc("PUT /rules/settings/{domain}/{id}", s.updateRule)
+	mux.HandleFunc("DELETE /rules/settings/{domain}/{id}", s.deleteRule)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+// Client cria um gocache.Client apontando para o servidor, sem novas tentativas por padrão
+func (s *Server) Client(opts ...gocache.Option) *gocache.Client {
+	opts = append([]gocache.Option{gocache.WithRetryPolicy(gocache.NoRetry())}, opts...)
+	client, err := gocache.NewClient(s.URL, s.Token, opts...)
+	if err != nil {
+		panic("gocachetest: " + err.Error())
+	}
+	return client
+}
+
+// Fail programa uma falha para as próximas requisições correspondentes
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// ClearFailures remove todas as falhas programadas
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// Requests retorna as requisições recebidas, na ordem de chegada
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Purges retorna as expirações de cache recebidas, na ordem de chegada
+func (s *Server) Purges() []Purge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Purge(nil), s.purges...)
+}
+
+// AddDomain cadastra um domínio com as configurações informadas
+func (s *Server) AddDomain(name string, settings url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if settings == nil {
+		settings = url.Values{}
+	}
+	s.domains[name] = settings
+}
+
+// Domains retorna os nomes dos domínios cadastrados, em ordem alfabética
+func (s *Server) Domains() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.domainNames()
+}
+
+// AddDNS cadastra registros DNS para um domínio, gerando IDs quando ausentes
+func (s *Server) AddDNS(domain string, records ...DNSRecord) []DNSRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range records {
+		if records[i].ID == "" {
+			records[i].ID = s.newID()
+		}
+		s.dns[domain] = append(s.dns[domain], records[i])
+	}
+	return records
+}
+
+// DNS retorna os registros DNS de um domínio
+func (s *Server) DNS(domain string) []DNSRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DNSRecord(nil), s.dns[domain]...)
+}
+
+// AddRedirect cadastra uma regra de redirecionamento, gerando o ID quando ausente
+func (s *Server) AddRedirect(r Redirect) Redirect {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.ID == 0 {
+		r.ID, _ = strconv.Atoi(s.newID())
+	}
+	s.redirects[r.Domain] = append(s.redirects[r.Domain], r)
+	return r
+}
+
+// Redirects retorna as regras de redirecionamento de um domínio
+func (s *Server) Redirects(domain string) []Redirect {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Redirect(nil), s.redirects[domain]...)
+}
+
+// AddRule cadastra uma smart rule de rewrite, gerando o ID quando ausente
+func (s *Server) AddRule(domain string, rule Rule) Rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rule.ID == "" {
+		rule.ID = s.newID()
+	}
+	s.rules[domain] = append(s.rules[domain], rule)
+	return rule
+}
+
+// Rules retorna as smart rules de rewrite de um domínio
+func (s *Server) Rules(domain string) []Rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Rule(nil), s.rules[domain]...)
+}
+
+// middleware registra a requisição, valida o token e aplica as falhas programadas
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		form := parseForm(r)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Form: form})
+		s.mu.Unlock()
+
+		// O token é validado antes das falhas programadas, para que uma requisição sem
+		// autenticação não consuma a falha destinada a outra
+		if r.Header.Get("GoCache-Token") != s.Token {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		s.mu.Lock()
+		failure := s.matchFailure(r)
+		s.mu.Unlock()
+		if failure != nil {
+			if failure.RetryAfter != "" {
+				w.Header().Set("Retry-After", failure.RetryAfter)
+			}
+			writeError(w, failure.Status, failure.Message)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// matchFailure retorna a primeira falha correspondente à requisição, consumindo uma ocorrência.
+// Deve ser chamada com s.mu travado.
+func (s *Server) matchFailure(r *http.Request) *Failure {
+	for i, f := range s.failures {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+
+		match := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		return &match
+	}
+	return nil
+}
+
+// newID gera um identificador sequencial. Deve ser chamada com s.mu travado.
+func (s *Server) newID() string {
+	id := strconv.Itoa(s.nextID)
+	s.nextID++
+	return id
+}
+
+// domainNames deve ser chamada com s.mu travado
+func (s *Server) domainNames() []string {
+	names := make([]string, 0, len(s.domains))
+	for name := range s.domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"status_code": status,
+		"response":    map[string]string{"msg": message},
+	})
+}
+
+func writeOK(w http.ResponseWriter, response interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status_code": http.StatusOK,
+		"response":    response,
+	})
+}
+
+// nestedValues extrai os campos de um grupo do formulário, como match[...] ou action[...]
+func nestedValues(form url.Values, group string) url.Values {
+	values := url.Values{}
+	prefix := group + "["
+	for key, vals := range form {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, prefix)
+		if end := strings.Index(name, "]"); end >= 0 {
+			name = name[:end]
+		}
+		values[name] = append(values[name], vals...)
+	}
+	return values
+}
+
+// indexedValues extrai listas enviadas como chave[0], chave[1]... ou chave[]
+func indexedValues(form url.Values, key string) []string {
+	type item struct {
+		index int
+		value string
+	}
+	var items []item
+	for k, vals := range form {
+		if k == key+"[]" {
+			for _, v := range vals {
+				items = append(items, item{index: len(items), value: v})
+			}
+			continue
+		}
+		if !strings.HasPrefix(k, key+"[") || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		index, err := strconv.Atoi(k[len(key)+1 : len(k)-1])
+		if err != nil {
+			continue
+		}
+		for _, v := range vals {
+			items = append(items, item{index: index, value: v})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].index < items[j].index })
+	values := make([]string, len(items))
+	for i, it := range items {
+		values[i] = it.value
+	}
+	return values
+}
+
+// flatten converte url.Values em um objeto JSON, mantendo listas quando houver múltiplos valores
+func flatten(values url.Values) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for key, vals := range values {
+		if len(vals) == 1 {
+			out[key] = vals[0]
+		} else {
+			out[key] = vals
+		}
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package gocache
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders lista os headers cujo valor nunca deve ser registrado
+var sensitiveHeaders = []string{"GoCache-Token", "Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveFields contém trechos de nomes de campos que indicam credenciais
+var sensitiveFields = []string{"token", "secret", "password", "passwd", "api_key", "apikey", "authorization", "private_key"}
+
+// isSensitiveField indica se o nome do campo sugere conteúdo sensível
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders retorna uma cópia dos headers com as credenciais mascaradas
+func redactHeaders(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if clean.Get(name) != "" {
+			clean.Set(name, redacted)
+		}
+	}
+	return clean
+}
+
+// redactForm retorna uma cópia do formulário com os campos sensíveis mascarados
+func redactForm(values url.Values) url.Values {
+	clean := url.Values{}
+	for key, vals := range values {
+		if isSensitiveField(key) {
+			clean[key] = []string{redacted}
+			continue
+		}
+		clean[key] = append([]string(nil), vals...)
+	}
+	return clean
+}
+
+// redactBody mascara os campos sensíveis de um corpo JSON. Corpos que não são JSON
+// são omitidos, já que não é possível garantir que não contenham credenciais.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "[corpo não-JSON omitido]"
+	}
+
+	clean, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return "[corpo omitido]"
+	}
+	return string(clean)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveField(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// installLogging registra os hooks de log do resty. Por padrão apenas método, URL,
+// status e duração são registrados; headers e corpos (mascarados) só em modo debug.
+func (c *Client) installLogging() {
+	c.httpClient.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		attrs := []any{"method", req.Method, "url", req.URL}
+		if c.debug {
+			attrs = append(attrs,
+				"headers", redactHeaders(req.Header),
+				"form", redactForm(req.FormData),
+			)
+		}
+		c.logger.Debug("gocache: enviando requisição", attrs...)
+		return nil
+	})
+
+	c.httpClient.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		attrs := []any{
+			"method", resp.Request.Method,
+			"url", resp.Request.URL,
+			"status", resp.StatusCode(),
+			"duration", resp.Time().Round(time.Millisecond),
+		}
+		if c.debug {
+			attrs = append(attrs,
+				"headers", redactHeaders(resp.Header()),
+				"body", redactBody(resp.Body()),
+			)
+		}
+
+		level := slog.LevelDebug
+		if !resp.IsSuccess() {
+			level = slog.LevelWarn
+		}
+		c.logger.Log(resp.Request.Context(), level, "gocache: resposta recebida", attrs...)
+		return nil
+	})
+}
@@ -0,0 +1,61 @@
+package gocache_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/renatoroquejani/poc-gocache/pkg/gocache"
+)
+
+func TestClientDebugLogRedaction(t *testing.T) {
+	const apiKey = "x9Q" // menor que 5 caracteres
+	secrets := []string{apiKey, "senha-do-form", "chave-do-form", "token-da-resposta", "segredo-do-texto"}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("GoCache-Token") != apiKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"response": {"name": "a.com", "api_token": "token-da-resposta", "users": [{"password": "token-da-resposta"}]}}`))
+		default:
+			w.Write([]byte("texto com segredo-do-texto"))
+		}
+	}))
+	defer ts.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := gocache.NewClient(ts.URL, apiKey, gocache.WithDebug(true), gocache.WithLogger(logger), gocache.WithRetryPolicy(gocache.NoRetry()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := map[string]string{"name": "a.com", "password": "senha-do-form", "api_key": "chave-do-form"}
+	if _, err := client.PostContext(context.Background(), "/json", form, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetContext(context.Background(), "/texto", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	output := logs.String()
+	for _, secret := range secrets {
+		if strings.Contains(output, secret) {
+			t.Errorf("log contém %q:\n%s", secret, output)
+		}
+	}
+	// O restante continua visível em modo debug
+	for _, want := range []string{"[REDACTED]", `\"name\":\"a.com\"`, "[corpo não-JSON omitido]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("log não contém %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package gocache
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Option configura o Client na sua criação
+type Option func(*Client)
+
+// WithRetryPolicy substitui a política de novas tentativas padrão
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimit limita o cliente a ratePerSecond requisições por segundo, com rajadas de até burst
+func WithRateLimit(ratePerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter = NewTokenBucket(ratePerSecond, burst)
+	}
+}
+
+// WithLimiter usa um Limiter existente, permitindo compartilhá-lo entre vários clientes
+func WithLimiter(limiter Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithEncoder substitui o encoder usado para montar o body das requisições
+func WithEncoder(encoder Encoder) Option {
+	return func(c *Client) {
+		c.encoder = encoder
+	}
+}
+
+// WithTimeout define o timeout de cada tentativa de requisição (padrão 30s)
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.SetTimeout(timeout)
+	}
+}
+
+// WithTransport define o http.RoundTripper usado pelo cliente, como um *http.Transport customizado
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.SetTransport(transport)
+	}
+}
+
+// WithLogger define o logger estruturado do cliente (padrão slog.Default())
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithUserAgent define o header User-Agent enviado em todas as requisições
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.httpClient.SetHeader("User-Agent", userAgent)
+	}
+}
+
+// WithDebug habilita o log de headers e corpos das requisições. O header GoCache-Token
+// e campos sensíveis dos corpos são sempre mascarados.
+func WithDebug(debug bool) Option {
+	return func(c *Client) {
+		c.debug = debug
+	}
+}
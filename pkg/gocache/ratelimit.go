@@ -0,0 +1,75 @@
+package gocache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter controla a taxa de requisições enviadas para a Gocache
+type Limiter interface {
+	// Wait bloqueia até que uma requisição possa ser enviada ou o contexto seja cancelado
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket é um Limiter do tipo token bucket seguro para uso concorrente.
+// Uma única instância deve ser compartilhada por todos os serviços que usam a mesma conta.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens repostos por segundo
+	burst  float64 // capacidade máxima do bucket
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket cria um limitador que permite ratePerSecond requisições por segundo
+// com rajadas de até burst requisições
+func NewTokenBucket(ratePerSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait consome um token, aguardando a reposição quando o bucket estiver vazio
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		wait := b.reserve()
+		if wait == 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve tenta consumir um token e retorna quanto tempo falta para o próximo, se não houver
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	missing := 1 - b.tokens
+	return time.Duration(missing / b.rate * float64(time.Second))
+}
@@ -0,0 +1,178 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RetryPolicy define quando e quanto tempo esperar antes de repetir uma requisição
+type RetryPolicy struct {
+	// MaxRetries é o número máximo de novas tentativas após a primeira (0 desabilita)
+	MaxRetries int
+	// BaseDelay é a espera inicial do backoff exponencial
+	BaseDelay time.Duration
+	// MaxDelay limita a espera entre tentativas. Um Retry-After maior que este valor
+	// encerra as tentativas e devolve o erro ao chamador.
+	MaxDelay time.Duration
+	// RetryNonIdempotent permite repetir POST/PATCH após falhas de rede ou 5xx.
+	// Respostas 429 são sempre repetidas, pois a Gocache não processou a requisição.
+	RetryNonIdempotent bool
+	// RetryableStatus lista os status HTTP que disparam nova tentativa
+	RetryableStatus []int
+}
+
+// DefaultRetryPolicy retorna a política padrão: 3 novas tentativas com backoff
+// exponencial a partir de 500ms, limitado a 20s, apenas para verbos idempotentes
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   20 * time.Second,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// NoRetry retorna uma política que nunca repete requisições
+func NoRetry() RetryPolicy {
+	return RetryPolicy{}
+}
+
+// isIdempotent indica se o método HTTP pode ser repetido com segurança
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry decide se a tentativa attempt (começando em 0) deve ser repetida e retorna a espera
+func (p RetryPolicy) shouldRetry(method string, attempt int, resp *resty.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxRetries {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	apiErr, isAPIErr := AsAPIError(err)
+	switch {
+	case err == nil:
+		return 0, false
+	case isAPIErr && apiErr.StatusCode == http.StatusTooManyRequests:
+		// A requisição foi rejeitada antes de ser processada, então repetir é seguro para qualquer verbo
+	case !isIdempotent(method) && !p.RetryNonIdempotent:
+		return 0, false
+	case isAPIErr && !p.isRetryableStatus(apiErr.StatusCode):
+		return 0, false
+	}
+
+	delay := p.backoff(attempt)
+	if wait, ok := rateLimitDelay(resp); ok {
+		if p.MaxDelay > 0 && wait > p.MaxDelay {
+			return 0, false
+		}
+		delay = wait
+	}
+	return delay, true
+}
+
+func (p RetryPolicy) isRetryableStatus(status int) bool {
+	for _, s := range p.RetryableStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff calcula a espera exponencial com "full jitter" para a tentativa informada
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	ceiling := p.BaseDelay << attempt
+	if ceiling <= 0 || (p.MaxDelay > 0 && ceiling > p.MaxDelay) {
+		ceiling = p.MaxDelay
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// rateLimitDelay lê os headers Retry-After e X-RateLimit-* da resposta
+func rateLimitDelay(resp *resty.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	header := resp.Header()
+
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return nonNegative(time.Until(date)), true
+		}
+	}
+
+	// Sem Retry-After, só aguarda o reset quando a cota estiver esgotada
+	remaining := firstHeader(header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	reset := firstHeader(header, "X-RateLimit-Reset", "RateLimit-Reset")
+	if remaining != "0" || reset == "" {
+		return 0, false
+	}
+
+	value, err := strconv.ParseInt(reset, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	// Valores grandes são timestamps Unix; valores pequenos são segundos até o reset
+	if value > 1_000_000_000 {
+		return nonNegative(time.Until(time.Unix(value, 0))), true
+	}
+	return time.Duration(value) * time.Second, true
+}
+
+func firstHeader(header http.Header, names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(header.Get(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// sleepContext aguarda a duração informada ou o cancelamento do contexto
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,240 @@
+package gocache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// responseWithHeader monta uma resposta do resty contendo apenas os headers informados
+func responseWithHeader(header map[string]string) *resty.Response {
+	raw := &http.Response{Header: http.Header{}}
+	for name, value := range header {
+		raw.Header.Set(name, value)
+	}
+	return &resty.Response{RawResponse: raw}
+}
+
+func TestShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	apiError := func(status int) error { return &APIError{StatusCode: status} }
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		method  string
+		attempt int
+		err     error
+		want    bool
+	}{
+		{"sucesso", policy, http.MethodGet, 0, nil, false},
+		{"503 em GET", policy, http.MethodGet, 0, apiError(503), true},
+		{"503 em POST", policy, http.MethodPost, 0, apiError(503), false},
+		{"503 em POST permitido", RetryPolicy{MaxRetries: 1, RetryNonIdempotent: true, RetryableStatus: []int{503}}, http.MethodPost, 0, apiError(503), true},
+		{"429 em POST", policy, http.MethodPost, 0, apiError(429), true},
+		{"400 não é repetido", policy, http.MethodGet, 0, apiError(400), false},
+		{"erro de rede em PUT", policy, http.MethodPut, 0, errors.New("connection reset"), true},
+		{"erro de rede em PATCH", policy, http.MethodPatch, 0, errors.New("connection reset"), false},
+		{"tentativas esgotadas", policy, http.MethodGet, 3, apiError(503), false},
+		{"contexto cancelado", policy, http.MethodGet, 0, context.Canceled, false},
+		{"prazo esgotado", policy, http.MethodGet, 0, context.DeadlineExceeded, false},
+		{"sem novas tentativas", NoRetry(), http.MethodGet, 0, apiError(503), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := tt.policy.shouldRetry(tt.method, tt.attempt, nil, tt.err); got != tt.want {
+				t.Errorf("shouldRetry() = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldRetryRetryAfter(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.MaxDelay = 10 * time.Second
+	err := &APIError{StatusCode: http.StatusTooManyRequests}
+
+	delay, retry := policy.shouldRetry(http.MethodGet, 0, responseWithHeader(map[string]string{"Retry-After": "2"}), err)
+	if !retry || delay != 2*time.Second {
+		t.Errorf("Retry-After 2: shouldRetry() = %v, %v; esperado 2s, true", delay, retry)
+	}
+
+	// Um Retry-After maior que MaxDelay devolve o erro em vez de aguardar
+	if _, retry := policy.shouldRetry(http.MethodGet, 0, responseWithHeader(map[string]string{"Retry-After": "60"}), err); retry {
+		t.Error("Retry-After acima de MaxDelay não deveria ser repetido")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{70, time.Second}, // o deslocamento estoura e cai no MaxDelay
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 200; i++ {
+			if got := policy.backoff(tt.attempt); got < 0 || got > tt.ceiling {
+				t.Fatalf("backoff(%d) = %v, esperado entre 0 e %v", tt.attempt, got, tt.ceiling)
+			}
+		}
+	}
+
+	if got := (RetryPolicy{}).backoff(2); got != 0 {
+		t.Errorf("backoff sem BaseDelay = %v, esperado 0", got)
+	}
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	inOneMinute := time.Now().Add(time.Minute)
+	tests := []struct {
+		name   string
+		header map[string]string
+		min    time.Duration
+		max    time.Duration
+		ok     bool
+	}{
+		{"sem headers", nil, 0, 0, false},
+		{"Retry-After em segundos", map[string]string{"Retry-After": " 5 "}, 5 * time.Second, 5 * time.Second, true},
+		{"Retry-After como data HTTP", map[string]string{"Retry-After": inOneMinute.UTC().Format(http.TimeFormat)}, 58 * time.Second, time.Minute, true},
+		{"Retry-After no passado", map[string]string{"Retry-After": "Mon, 02 Jan 2006 15:04:05 GMT"}, 0, 0, true},
+		{"Retry-After inválido", map[string]string{"Retry-After": "logo"}, 0, 0, false},
+		{"cota esgotada com reset em segundos", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "3"}, 3 * time.Second, 3 * time.Second, true},
+		{"cota esgotada com reset em timestamp", map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": strconv.FormatInt(inOneMinute.Unix(), 10)}, 58 * time.Second, time.Minute, true},
+		{"cota disponível", map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "3"}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rateLimitDelay(responseWithHeader(tt.header))
+			if ok != tt.ok || got < tt.min || got > tt.max {
+				t.Errorf("rateLimitDelay() = %v, %v; esperado entre %v e %v, %v", got, ok, tt.min, tt.max, tt.ok)
+			}
+		})
+	}
+
+	if _, ok := rateLimitDelay(nil); ok {
+		t.Error("rateLimitDelay(nil) deveria retornar false")
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	quietLogger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tests := []struct {
+		name       string
+		method     string
+		failures   int32
+		status     int
+		retryAfter string
+		policy     RetryPolicy
+		wantCalls  int32
+		wantErr    bool
+		maxElapsed time.Duration
+	}{
+		{
+			name: "429 com Retry-After é repetido até o sucesso", method: http.MethodGet,
+			failures: 2, status: http.StatusTooManyRequests, retryAfter: "0",
+			policy:    RetryPolicy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: time.Second, RetryableStatus: []int{429}},
+			wantCalls: 3, maxElapsed: 500 * time.Millisecond,
+		},
+		{
+			name: "503 em POST não é repetido", method: http.MethodPost,
+			failures: 1, status: http.StatusServiceUnavailable,
+			policy:    DefaultRetryPolicy(),
+			wantCalls: 1, wantErr: true,
+		},
+		{
+			name: "tentativas esgotadas", method: http.MethodDelete,
+			failures: 10, status: http.StatusServiceUnavailable,
+			policy:    RetryPolicy{MaxRetries: 2, BaseDelay: 5 * time.Millisecond, MaxDelay: 10 * time.Millisecond, RetryableStatus: []int{503}},
+			wantCalls: 3, wantErr: true, maxElapsed: 500 * time.Millisecond,
+		},
+		{
+			name: "Retry-After acima do limite devolve o erro", method: http.MethodGet,
+			failures: 1, status: http.StatusTooManyRequests, retryAfter: "120",
+			policy:    DefaultRetryPolicy(),
+			wantCalls: 1, wantErr: true, maxElapsed: 500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					io.WriteString(w, `{"msg":"falha"}`)
+					return
+				}
+				io.WriteString(w, `{"status_code":1}`)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(server.URL, "token", WithRetryPolicy(tt.policy), WithLogger(quietLogger))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			if tt.method == http.MethodGet {
+				_, err = client.Get("/x", nil)
+			} else {
+				_, err = client.doRequest(context.Background(), tt.method, "/x", map[string]string{"a": "1"}, nil)
+			}
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("erro = %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("chamadas = %d, esperado %d", got, tt.wantCalls)
+			}
+			if tt.maxElapsed > 0 && elapsed > tt.maxElapsed {
+				t.Errorf("tempo total = %v, esperado no máximo %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+func TestClientRetryHonorsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "token", WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.GetContext(ctx, "/x", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("erro = %v, esperado context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("a espera do Retry-After não respeitou o contexto: %v", elapsed)
+	}
+}